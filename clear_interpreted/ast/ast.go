@@ -110,10 +110,15 @@ func (ms *ModuleStatement) String() string {
 
 // Statement used to assign variables to an expression
 // let x = 7;
+// Destructuring lets (let [a, b] = arr;) use a Pattern instead of a Name
+// `const` declarations are also let statements, marked as Constant
 type LetStatement struct {
-	Token token.Token // the token.LET token
-	Name  *Identifier `json:"name"`
-	Value Expression  `json:"value"`
+	Token    token.Token // the token.LET or token.CONST token
+	Name     *Identifier `json:"name"`
+	Pattern  Pattern     `json:"pattern"`   // set instead of Name when destructuring
+	TypeName *TypeExpr   `json:"type_name"` // optional, `let x: int = 5;`
+	Value    Expression  `json:"value"`
+	Constant bool        `json:"constant"`
 }
 
 func (ls *LetStatement) statementNode()       {}
@@ -122,7 +127,14 @@ func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
-	out.WriteString(ls.Name.String())
+	if ls.Pattern != nil {
+		out.WriteString(ls.Pattern.String())
+	} else {
+		out.WriteString(ls.Name.String())
+	}
+	if ls.TypeName != nil {
+		out.WriteString(": " + ls.TypeName.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -218,6 +230,202 @@ func (fs *ForStatement) String() string {
 	return out.String()
 }
 
+// Loops over the elements of an array, the pairs of a hash,
+// the characters of a string or the integers of a range
+// for (x in arr) { ... }
+// for (i, x in arr) { ... }
+// for (k, v in hash) { ... }
+type ForInStatement struct {
+	Token    token.Token     // The 'for' token
+	Key      *Identifier     `json:"key"` // optional, the index or hash key
+	Value    *Identifier     `json:"value"`
+	Iterable Expression      `json:"iterable"`
+	Body     *BlockStatement `json:"body"`
+}
+
+func (fis *ForInStatement) statementNode()       {}
+func (fis *ForInStatement) TokenLiteral() string { return fis.Token.Literal }
+func (fis *ForInStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(fis.TokenLiteral() + " (")
+	if fis.Key != nil {
+		out.WriteString(fis.Key.String() + ", ")
+	}
+	out.WriteString(fis.Value.String())
+	out.WriteString(" in ")
+	out.WriteString(fis.Iterable.String())
+	out.WriteString(") {")
+	out.WriteString(fis.Body.String())
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Declares a record type with named fields, which can be constructed like a function
+// Fields can optionally declare a type and a default value
+// Ex. type Point { x: int, y: int = 0 }
+type TypeStatement struct {
+	Token  token.Token  // The 'type' token
+	Name   *Identifier  `json:"name"`
+	Fields []*FieldDecl `json:"fields"`
+}
+
+func (ts *TypeStatement) statementNode()       {}
+func (ts *TypeStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *TypeStatement) String() string {
+	var out bytes.Buffer
+
+	fields := []string{}
+	for _, field := range ts.Fields {
+		fields = append(fields, field.String())
+	}
+
+	out.WriteString("type ")
+	out.WriteString(ts.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(fields, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// A single field of a record type: `name`, `name: type` or `name: type = default`
+type FieldDecl struct {
+	Name     *Identifier `json:"name"`
+	TypeName *TypeExpr   `json:"type_name"` // optional
+	Default  Expression  `json:"default"`   // optional
+}
+
+func (fd *FieldDecl) String() string {
+	out := fd.Name.String()
+	if fd.TypeName != nil {
+		out += ": " + fd.TypeName.String()
+	}
+	if fd.Default != nil {
+		out += " = " + fd.Default.String()
+	}
+	return out
+}
+
+// Declares an enum: `enum Color { Red, Green, Blue }`
+// Variants may carry fields, `enum Shape { Circle(r), Rect(w, h) }`, or set their value, `Ok = 200`
+type EnumStatement struct {
+	Token    token.Token    // The 'enum' token
+	Name     *Identifier    `json:"name"`
+	Variants []*VariantDecl `json:"variants"`
+}
+
+func (es *EnumStatement) statementNode()       {}
+func (es *EnumStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *EnumStatement) String() string {
+	var out bytes.Buffer
+
+	variants := []string{}
+	for _, variant := range es.Variants {
+		variants = append(variants, variant.String())
+	}
+
+	out.WriteString("enum ")
+	out.WriteString(es.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(variants, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// A single variant of an enum: `Red`, `Circle(r)` or `Ok = 200`
+type VariantDecl struct {
+	Name   *Identifier   `json:"name"`
+	Fields []*Identifier `json:"fields"` // nil for variants without a field list
+	Value  Expression    `json:"value"`  // optional
+}
+
+func (vd *VariantDecl) String() string {
+	out := vd.Name.String()
+	if vd.Fields != nil {
+		fields := []string{}
+		for _, field := range vd.Fields {
+			fields = append(fields, field.String())
+		}
+		out += "(" + strings.Join(fields, ", ") + ")"
+	}
+	if vd.Value != nil {
+		out += " = " + vd.Value.String()
+	}
+	return out
+}
+
+// Declares the methods a type needs to satisfy it, `interface Shape { area(); scale(by: float) -> Shape; }`
+// Any record type or enum with those methods satisfies it, without naming it
+type InterfaceStatement struct {
+	Token   token.Token        // The 'interface' token
+	Name    *Identifier        `json:"name"`
+	Methods []*MethodSignature `json:"methods"`
+}
+
+func (is *InterfaceStatement) statementNode()       {}
+func (is *InterfaceStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *InterfaceStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("interface ")
+	out.WriteString(is.Name.String())
+	out.WriteString(" { ")
+	for _, method := range is.Methods {
+		out.WriteString(method.String() + "; ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// A single method of an interface. Its parameters don't include the receiver
+type MethodSignature struct {
+	Name           *Identifier   `json:"name"`
+	Parameters     []*Identifier `json:"parameters"`
+	ParameterTypes []*TypeExpr   `json:"parameter_types"`
+	ReturnType     *TypeExpr     `json:"return_type"` // optional
+}
+
+func (ms *MethodSignature) String() string {
+	out := ms.Name.String() + "(" + parameterList(ms.Parameters, ms.ParameterTypes) + ")"
+	if ms.ReturnType != nil {
+		out += " -> " + ms.ReturnType.String()
+	}
+	return out
+}
+
+// Attaches methods to a record type or enum, `impl Circle { fn area(self) { ... } }`
+// Methods take the value they're called on as their first parameter
+// Naming an interface, `impl Shape for Circle { ... }`, also checks that the type satisfies it
+type ImplStatement struct {
+	Token     token.Token     // The 'impl' token
+	Interface *Identifier     `json:"interface"` // optional
+	Target    *Identifier     `json:"target"`
+	Methods   []*LetStatement `json:"methods"` // declared like functions, `fn area(self) { ... }`
+}
+
+func (is *ImplStatement) statementNode()       {}
+func (is *ImplStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImplStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("impl ")
+	if is.Interface != nil {
+		out.WriteString(is.Interface.String() + " for ")
+	}
+	out.WriteString(is.Target.String())
+	out.WriteString(" { ")
+	for _, method := range is.Methods {
+		out.WriteString("fn " + method.Name.String() + strings.TrimPrefix(method.Value.String(), "fn") + " ")
+	}
+	out.WriteString("}")
+
+	return out.String()
+}
+
 type BreakStatement struct {
 	Token token.Token
 }
@@ -472,10 +680,17 @@ func (ie *IfExpression) String() string {
 
 // Function literals contain a list of parameters and a block statement to execute
 // Obivously, functions may have infinite parameters and statements within the block
+// Parameter and return types are optional annotations, `fn(a: int, b) -> bool { ... }`
+// ParameterTypes lines up with Parameters, with nil for parameters that aren't annotated
+// Generic functions declare type parameters before their parameters, `fn<T>(xs: array<T>) -> T { ... }`
 type FunctionLiteral struct {
-	Token      token.Token     // The 'fn' token
-	Parameters []*Identifier   `json:"parameters"`
-	Body       *BlockStatement `json:"body"`
+	Token          token.Token     // The 'fn' token
+	TypeParameters []*Identifier   `json:"type_parameters"`
+	Parameters     []*Identifier   `json:"parameters"`
+	ParameterTypes []*TypeExpr     `json:"parameter_types"`
+	ReturnType     *TypeExpr       `json:"return_type"` // optional
+	Body           *BlockStatement `json:"body"`
+	Generator      bool            `json:"generator"` // declared with `fn*`, so calling it returns an iterator over what it yields
 }
 
 func (fl *FunctionLiteral) expressionNode()      {}
@@ -483,20 +698,38 @@ func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
 func (fl *FunctionLiteral) String() string {
 	var out bytes.Buffer
 
-	params := []string{}
-	for _, p := range fl.Parameters {
-		params = append(params, p.String())
-	}
-
 	out.WriteString(fl.TokenLiteral())
+	if len(fl.TypeParameters) > 0 {
+		typeParams := []string{}
+		for _, tp := range fl.TypeParameters {
+			typeParams = append(typeParams, tp.String())
+		}
+		out.WriteString("<" + strings.Join(typeParams, ", ") + ">")
+	}
 	out.WriteString("(")
-	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(parameterList(fl.Parameters, fl.ParameterTypes))
 	out.WriteString(") ")
+	if fl.ReturnType != nil {
+		out.WriteString("-> " + fl.ReturnType.String() + " ")
+	}
 	out.WriteString(fl.Body.String())
 
 	return out.String()
 }
 
+// Parameters separated by commas, with their annotations when they have one: "a: int, b"
+func parameterList(params []*Identifier, types []*TypeExpr) string {
+	list := []string{}
+	for i, p := range params {
+		if i < len(types) && types[i] != nil {
+			list = append(list, p.String()+": "+types[i].String())
+			continue
+		}
+		list = append(list, p.String())
+	}
+	return strings.Join(list, ", ")
+}
+
 // Expression that invokes a predefined function with an optional list of arguments
 type CallExpression struct {
 	Token     token.Token  // The '(' token
@@ -564,9 +797,88 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
+// Slices take a part of an array or string, each part of the slice is optional
+// arr[1:3], arr[:n], arr[::2], str[2:5]
+type SliceExpression struct {
+	Token token.Token // The '[' token
+	Left  Expression  `json:"left"`
+	Start Expression  `json:"start"` // optional
+	End   Expression  `json:"end"`   // optional
+	Step  Expression  `json:"step"`  // optional
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	if se.Step != nil {
+		out.WriteString(":")
+		out.WriteString(se.Step.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
+
+// Accessing a member of a value with `.`, such as a module function (math.sqrt),
+// a hash field (user.name) or a method on a value ("abc".upper)
+// Chained access (config.db.host) nests MemberExpressions from left to right
+type MemberExpression struct {
+	Token    token.Token // The '.' token
+	Object   Expression  `json:"object"`
+	Property *Identifier `json:"property"`
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MemberExpression) String() string {
+	return me.Object.String() + "." + me.Property.String()
+}
+
+// Spreading expands an array into the surrounding array literal or call arguments,
+// or a hash into the surrounding hash literal: [...a, ...b], f(...args), {...defaults}
+type SpreadExpression struct {
+	Token token.Token // The '...' token
+	Value Expression  `json:"value"`
+}
+
+func (se *SpreadExpression) expressionNode()      {}
+func (se *SpreadExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpreadExpression) String() string       { return "..." + se.Value.String() }
+
+// Hands a value to whoever is iterating over the generator it's in, pausing the generator until the next value is asked for
+type YieldExpression struct {
+	Token token.Token // The 'yield' token
+	Value Expression  `json:"value"`
+}
+
+func (ye *YieldExpression) expressionNode()      {}
+func (ye *YieldExpression) TokenLiteral() string { return ye.Token.Literal }
+func (ye *YieldExpression) String() string       { return "yield " + ye.Value.String() }
+
+// A single `key: value` entry within a hash literal
+// Spread entries ({...other}) hold a SpreadExpression as the key and no value
+type HashLiteralPair struct {
+	Key   Expression `json:"key"`
+	Value Expression `json:"value"`
+}
+
+// Pairs are stored in the order they appear in the source code,
+// so they are also evaluated in that order
 type HashLiteral struct {
-	Token token.Token               // The '{' token
-	Pairs map[Expression]Expression `json:"pairs"`
+	Token token.Token        // The '{' token
+	Pairs []*HashLiteralPair `json:"pairs"`
 }
 
 func (hl *HashLiteral) expressionNode()      {}
@@ -575,8 +887,12 @@ func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, pair := range hl.Pairs {
+		if pair.Value == nil {
+			pairs = append(pairs, pair.Key.String())
+			continue
+		}
+		pairs = append(pairs, pair.Key.String()+":"+pair.Value.String())
 	}
 
 	out.WriteString("{")
@@ -586,6 +902,140 @@ func (hl *HashLiteral) String() string {
 	return out.String()
 }
 
+// Range expressions produce a sequence of integers, mainly used by for-in loops
+// 0..10 includes the end, 0..<10 excludes it
+// An optional step can follow the end: 0..10 step 2
+type RangeExpression struct {
+	Token     token.Token // The '..' or '..<' token
+	Start     Expression  `json:"start"`
+	End       Expression  `json:"end"`
+	Step      Expression  `json:"step"` // optional
+	Inclusive bool        `json:"inclusive"`
+}
+
+func (re *RangeExpression) expressionNode()      {}
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+func (re *RangeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(re.Start.String())
+	out.WriteString(re.Token.Literal)
+	out.WriteString(re.End.String())
+	if re.Step != nil {
+		out.WriteString(" step ")
+		out.WriteString(re.Step.String())
+	}
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// Match expressions compare a subject against each arm's pattern in order,
+// evaluating the body of the first arm that matches (and whose guard holds)
+// Ex. match (x) { 0 => "zero", n if n > 0 => "positive", _ => "negative" }
+type MatchExpression struct {
+	Token   token.Token // The 'match' token
+	Subject Expression  `json:"subject"`
+	Arms    []*MatchArm `json:"arms"`
+}
+
+func (me *MatchExpression) expressionNode()      {}
+func (me *MatchExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MatchExpression) String() string {
+	var out bytes.Buffer
+
+	arms := []string{}
+	for _, arm := range me.Arms {
+		arms = append(arms, arm.String())
+	}
+
+	out.WriteString("match (")
+	out.WriteString(me.Subject.String())
+	out.WriteString(") { ")
+	out.WriteString(strings.Join(arms, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+// Starts a call on its own task, evaluating the function and its arguments right away: spawn worker(job)
+type SpawnExpression struct {
+	Token token.Token     // The 'spawn' token
+	Call  *CallExpression `json:"call"`
+}
+
+func (se *SpawnExpression) expressionNode()      {}
+func (se *SpawnExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SpawnExpression) String() string       { return "spawn " + se.Call.String() }
+
+// Waits until one of its cases can go ahead and evaluates to that case's body, like a match over channel operations
+// A `_` case runs when no other case is ready right away, so the select doesn't wait at all
+type SelectExpression struct {
+	Token token.Token   // The 'select' token
+	Cases []*SelectCase `json:"cases"`
+}
+
+func (se *SelectExpression) expressionNode()      {}
+func (se *SelectExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SelectExpression) String() string {
+	cases := []string{}
+	for _, c := range se.Cases {
+		cases = append(cases, c.String())
+	}
+	return "select { " + strings.Join(cases, ", ") + " }"
+}
+
+// A single `recv(ch)`, `name = recv(ch)`, `send(ch, value)` or `_` case of a select
+// Bodies written as a lone expression are wrapped in a block, like the arms of a match
+type SelectCase struct {
+	Token     token.Token     // The '=>' token
+	Name      *Identifier     `json:"name"`      // bound to the received value, optional
+	Operation *CallExpression `json:"operation"` // nil for the `_` case
+	Body      *BlockStatement `json:"body"`
+}
+
+func (sc *SelectCase) TokenLiteral() string { return sc.Token.Literal }
+func (sc *SelectCase) String() string {
+	operation := "_"
+	if sc.Operation != nil {
+		operation = sc.Operation.String()
+	}
+	if sc.Name != nil {
+		operation = sc.Name.String() + " = " + operation
+	}
+	return operation + " => " + sc.Body.String()
+}
+
+// Whether the case receives rather than sends, only meaningful for cases with an operation
+func (sc *SelectCase) Receives() bool {
+	return sc.Operation.Function.(*Identifier).Value == "recv"
+}
+
+// A single `pattern if guard => body` arm of a match expression
+// Arms written as a lone expression are wrapped in a block, so every body is a block
+type MatchArm struct {
+	Token   token.Token     // The '=>' token
+	Pattern Pattern         `json:"pattern"`
+	Guard   Expression      `json:"guard"` // optional
+	Body    *BlockStatement `json:"body"`
+}
+
+func (ma *MatchArm) TokenLiteral() string { return ma.Token.Literal }
+func (ma *MatchArm) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ma.Pattern.String())
+	if ma.Guard != nil {
+		out.WriteString(" if ")
+		out.WriteString(ma.Guard.String())
+	}
+	out.WriteString(" => ")
+	out.WriteString(ma.Body.String())
+
+	return out.String()
+}
+
 type NewInstanceExpression struct {
 	Token     token.Token
 	Class     *Identifier
@@ -597,3 +1047,184 @@ func (nie *NewInstanceExpression) TokenLiteral() string { return nie.Token.Liter
 func (nie *NewInstanceExpression) String() string       { return litter.Sdump(nie) }
 
 // -------------------------
+// # PATTERN NODES
+
+// Patterns describe the shape of a value in match arms
+// Matching a pattern may bind names, which are scoped to the arm
+type Pattern interface {
+	Node
+	patternNode() // Tracking method for patterns
+}
+
+// `_` matches anything without binding it
+type WildcardPattern struct {
+	Token token.Token // The '_' token
+}
+
+func (wp *WildcardPattern) patternNode()         {}
+func (wp *WildcardPattern) TokenLiteral() string { return wp.Token.Literal }
+func (wp *WildcardPattern) String() string       { return "_" }
+
+// A plain name matches anything and binds the value to that name
+type BindingPattern struct {
+	Token token.Token // The identifier token
+	Name  *Identifier `json:"name"`
+}
+
+func (bp *BindingPattern) patternNode()         {}
+func (bp *BindingPattern) TokenLiteral() string { return bp.Token.Literal }
+func (bp *BindingPattern) String() string       { return bp.Name.String() }
+
+// Matches values equal to a literal: 0, -1.5, "user", true
+type LiteralPattern struct {
+	Token token.Token // The literal's first token
+	Value Expression  `json:"value"`
+}
+
+func (lp *LiteralPattern) patternNode()         {}
+func (lp *LiteralPattern) TokenLiteral() string { return lp.Token.Literal }
+func (lp *LiteralPattern) String() string       { return lp.Value.String() }
+
+// Matches numbers within a range: 1..5 includes 5, 1..<5 does not
+type RangePattern struct {
+	Token     token.Token // The '..' or '..<' token
+	Start     Expression  `json:"start"`
+	End       Expression  `json:"end"`
+	Inclusive bool        `json:"inclusive"`
+}
+
+func (rp *RangePattern) patternNode()         {}
+func (rp *RangePattern) TokenLiteral() string { return rp.Token.Literal }
+func (rp *RangePattern) String() string {
+	return rp.Start.String() + rp.Token.Literal + rp.End.String()
+}
+
+// Matches arrays element by element: [first, second]
+// A trailing `...rest` matches any remaining elements and binds them as an array
+type ArrayPattern struct {
+	Token    token.Token // The '[' token
+	Elements []Pattern   `json:"elements"`
+	Rest     *Identifier `json:"rest"` // optional
+}
+
+func (ap *ArrayPattern) patternNode()         {}
+func (ap *ArrayPattern) TokenLiteral() string { return ap.Token.Literal }
+func (ap *ArrayPattern) String() string {
+	var out bytes.Buffer
+
+	elements := []string{}
+	for _, el := range ap.Elements {
+		elements = append(elements, el.String())
+	}
+	if ap.Rest != nil {
+		elements = append(elements, "..."+ap.Rest.String())
+	}
+
+	out.WriteString("[")
+	out.WriteString(strings.Join(elements, ", "))
+	out.WriteString("]")
+
+	return out.String()
+}
+
+// A single `key: pattern` entry within a hash pattern
+type HashPatternPair struct {
+	Key   Expression `json:"key"`
+	Value Pattern    `json:"value"`
+}
+
+// Matches hashes containing every listed key, ignoring any other keys
+// `{name}` is shorthand for `{"name": name}`
+type HashPattern struct {
+	Token token.Token        // The '{' token
+	Pairs []*HashPatternPair `json:"pairs"`
+}
+
+func (hp *HashPattern) patternNode()         {}
+func (hp *HashPattern) TokenLiteral() string { return hp.Token.Literal }
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range hp.Pairs {
+		pairs = append(pairs, pair.Key.String()+": "+pair.Value.String())
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Only valid for array elements and hash values in destructuring:
+// the default is used when the element or key is missing
+// Ex. let [a, b = 2] = arr; let {name = "anonymous"} = user;
+type DefaultPattern struct {
+	Token   token.Token // The '=' token
+	Pattern Pattern     `json:"pattern"`
+	Default Expression  `json:"default"`
+}
+
+func (dp *DefaultPattern) patternNode()         {}
+func (dp *DefaultPattern) TokenLiteral() string { return dp.Token.Literal }
+func (dp *DefaultPattern) String() string {
+	return dp.Pattern.String() + " = " + dp.Default.String()
+}
+
+// Matches values of a given type, then matches the inner pattern: n: int, _: string
+type TypePattern struct {
+	Token    token.Token // The ':' token
+	Pattern  Pattern     `json:"pattern"`
+	TypeName *TypeExpr   `json:"type_name"`
+}
+
+func (tp *TypePattern) patternNode()         {}
+func (tp *TypePattern) TokenLiteral() string { return tp.Token.Literal }
+func (tp *TypePattern) String() string {
+	return tp.Pattern.String() + ": " + tp.TypeName.String()
+}
+
+// Matches one variant of an enum: Color.Red, Shape.Circle(r), Shape.Rect(w, _)
+// Without parentheses the variant's fields aren't matched at all
+type VariantPattern struct {
+	Token   token.Token // The enum name token
+	Enum    *Identifier `json:"enum"`
+	Variant *Identifier `json:"variant"`
+	Fields  []Pattern   `json:"fields"` // nil when there are no parentheses
+}
+
+func (vp *VariantPattern) patternNode()         {}
+func (vp *VariantPattern) TokenLiteral() string { return vp.Token.Literal }
+func (vp *VariantPattern) String() string {
+	out := vp.Enum.String() + "." + vp.Variant.String()
+	if vp.Fields != nil {
+		fields := []string{}
+		for _, field := range vp.Fields {
+			fields = append(fields, field.String())
+		}
+		out += "(" + strings.Join(fields, ", ") + ")"
+	}
+	return out
+}
+
+// A type annotation: int, Point, array<int>, hash<string, array<User>>
+// Args holds the type arguments between the angle brackets, and is empty for plain names
+type TypeExpr struct {
+	Token token.Token // The type name token
+	Name  string      `json:"name"`
+	Args  []*TypeExpr `json:"args"`
+}
+
+func (te *TypeExpr) TokenLiteral() string { return te.Token.Literal }
+func (te *TypeExpr) String() string {
+	if len(te.Args) == 0 {
+		return te.Name
+	}
+
+	args := []string{}
+	for _, arg := range te.Args {
+		args = append(args, arg.String())
+	}
+	return te.Name + "<" + strings.Join(args, ", ") + ">"
+}
@@ -0,0 +1,192 @@
+package ast
+
+// Inspect traverses the tree rooted at node in depth-first order,
+// calling f for every node it visits (the same idea as go/ast.Inspect)
+// If f returns false, the children of that node are skipped
+func Inspect(node Node, f func(Node) bool) {
+	if node == nil || !f(node) {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		for _, mod := range n.Modules {
+			Inspect(mod, f)
+		}
+		for _, stmt := range n.Statements {
+			Inspect(stmt, f)
+		}
+
+	// Statements
+	case *LetStatement:
+		inspectPattern(n.Pattern, f)
+		inspectExpression(n.Value, f)
+	case *AssignStatement:
+		inspectExpression(n.Value, f)
+	case *ReturnStatement:
+		inspectExpression(n.ReturnValue, f)
+	case *ExpressionStatement:
+		inspectExpression(n.Expression, f)
+	case *WhileStatement:
+		inspectExpression(n.Condition, f)
+		inspectBlock(n.Body, f)
+	case *ForStatement:
+		if n.Init != nil {
+			Inspect(n.Init, f)
+		}
+		inspectExpression(n.Condition, f)
+		inspectExpression(n.Post, f)
+		inspectBlock(n.Body, f)
+	case *ForInStatement:
+		inspectExpression(n.Iterable, f)
+		inspectBlock(n.Body, f)
+	case *BlockStatement:
+		for _, stmt := range n.Statements {
+			Inspect(stmt, f)
+		}
+	case *TypeStatement:
+		for _, field := range n.Fields {
+			inspectExpression(field.Default, f)
+		}
+	case *EnumStatement:
+		for _, variant := range n.Variants {
+			inspectExpression(variant.Value, f)
+		}
+	case *ImplStatement:
+		// Methods aren't variables, so only their functions are visited
+		for _, method := range n.Methods {
+			inspectExpression(method.Value, f)
+		}
+	case *ClassStatement:
+		for _, method := range n.Methods {
+			Inspect(method, f)
+		}
+	case *MethodStatement:
+		inspectBlock(n.Body, f)
+
+	// Expressions
+	case *PrefixExpression:
+		inspectExpression(n.Right, f)
+	case *InfixExpression:
+		inspectExpression(n.Left, f)
+		inspectExpression(n.Right, f)
+	case *PostfixExpression:
+		inspectExpression(n.Left, f)
+	case *IfExpression:
+		inspectExpression(n.Condition, f)
+		inspectBlock(n.Consequence, f)
+		inspectBlock(n.Alternative, f)
+	case *FunctionLiteral:
+		inspectBlock(n.Body, f)
+	case *CallExpression:
+		inspectExpression(n.Function, f)
+		for _, arg := range n.Arguments {
+			inspectExpression(arg, f)
+		}
+	case *ArrayLiteral:
+		for _, el := range n.Elements {
+			inspectExpression(el, f)
+		}
+	case *IndexExpression:
+		inspectExpression(n.Left, f)
+		inspectExpression(n.Index, f)
+	case *SliceExpression:
+		inspectExpression(n.Left, f)
+		inspectExpression(n.Start, f)
+		inspectExpression(n.End, f)
+		inspectExpression(n.Step, f)
+	case *MemberExpression:
+		inspectExpression(n.Object, f)
+	case *SpreadExpression:
+		inspectExpression(n.Value, f)
+	case *YieldExpression:
+		inspectExpression(n.Value, f)
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			inspectExpression(pair.Key, f)
+			inspectExpression(pair.Value, f)
+		}
+	case *RangeExpression:
+		inspectExpression(n.Start, f)
+		inspectExpression(n.End, f)
+		inspectExpression(n.Step, f)
+	case *MatchExpression:
+		inspectExpression(n.Subject, f)
+		for _, arm := range n.Arms {
+			Inspect(arm, f)
+		}
+	case *MatchArm:
+		inspectPattern(n.Pattern, f)
+		inspectExpression(n.Guard, f)
+		inspectBlock(n.Body, f)
+	case *SpawnExpression:
+		inspectExpression(n.Call, f)
+	case *SelectExpression:
+		for _, c := range n.Cases {
+			Inspect(c, f)
+		}
+	case *SelectCase:
+		if n.Operation != nil {
+			inspectExpression(n.Operation, f)
+		}
+		inspectBlock(n.Body, f)
+
+	// Patterns
+	case *ArrayPattern:
+		for _, el := range n.Elements {
+			inspectPattern(el, f)
+		}
+	case *HashPattern:
+		for _, pair := range n.Pairs {
+			inspectPattern(pair.Value, f)
+		}
+	case *TypePattern:
+		inspectPattern(n.Pattern, f)
+	case *VariantPattern:
+		for _, field := range n.Fields {
+			inspectPattern(field, f)
+		}
+	case *DefaultPattern:
+		inspectPattern(n.Pattern, f)
+		inspectExpression(n.Default, f)
+	}
+}
+
+// Optional children are often nil, which shouldn't be visited
+func inspectExpression(exp Expression, f func(Node) bool) {
+	if exp != nil {
+		Inspect(exp, f)
+	}
+}
+
+func inspectBlock(block *BlockStatement, f func(Node) bool) {
+	if block != nil {
+		Inspect(block, f)
+	}
+}
+
+func inspectPattern(pattern Pattern, f func(Node) bool) {
+	if pattern != nil {
+		Inspect(pattern, f)
+	}
+}
+
+// Every name a pattern binds, including array rest names
+func PatternNames(pattern Pattern) []*Identifier {
+	names := []*Identifier{}
+	Inspect(pattern, func(node Node) bool {
+		switch node := node.(type) {
+		case *BindingPattern:
+			names = append(names, node.Name)
+		case *ArrayPattern:
+			if node.Rest != nil {
+				names = append(names, node.Rest)
+			}
+		case Expression:
+			// Defaults are expressions, any patterns inside them bind their own names
+			return false
+		}
+		return true
+	})
+	return names
+}
@@ -0,0 +1,273 @@
+/*
+	The checker walks the parsed program before it is evaluated, reporting
+	problems that can be spotted without running any code
+
+	Most of what is reported here is a warning, so the program still runs
+	Errors (like reassigning a constant) stop the program before it starts
+*/
+
+package checker
+
+import (
+	"strings"
+
+	"github.com/ajtroup1/clear/ast"
+	"github.com/ajtroup1/clear/errors"
+	"github.com/ajtroup1/clear/token"
+)
+
+type Checker struct {
+	lines   []string
+	enums   map[string]*ast.EnumStatement // declared enums by name, used to check matches on enums
+	records map[string]*ast.TypeStatement // declared record types by name
+	fields  map[string]map[string]*typ    // resolved field types of record types, filled in as their declarations are checked
+
+	interfaces map[string]*ast.InterfaceStatement         // declared interfaces by name
+	methods    map[string]map[string]*ast.FunctionLiteral // methods attached with impl, by type name and method name
+	attached   map[string]map[string]*ast.FunctionLiteral // the methods attached by the impls checked so far
+
+	Errors []*errors.Error
+}
+
+func New(lines []string) *Checker {
+	return &Checker{
+		lines:   lines,
+		enums:   map[string]*ast.EnumStatement{},
+		records: map[string]*ast.TypeStatement{},
+		fields:  map[string]map[string]*typ{},
+
+		interfaces: map[string]*ast.InterfaceStatement{},
+		methods:    map[string]map[string]*ast.FunctionLiteral{},
+		attached:   map[string]map[string]*ast.FunctionLiteral{},
+
+		Errors: []*errors.Error{},
+	}
+}
+
+// Runs every check over the program and returns what was found
+func (c *Checker) Check(program *ast.Program) []*errors.Error {
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.EnumStatement:
+			c.enums[node.Name.Value] = node
+		case *ast.TypeStatement:
+			c.records[node.Name.Value] = node
+		case *ast.InterfaceStatement:
+			c.interfaces[node.Name.Value] = node
+		case *ast.ImplStatement:
+			// Methods can be called before the impl that attaches them runs, so they're all collected up front
+			if c.methods[node.Target.Value] == nil {
+				c.methods[node.Target.Value] = map[string]*ast.FunctionLiteral{}
+			}
+			for _, method := range node.Methods {
+				if lit, ok := method.Value.(*ast.FunctionLiteral); ok {
+					c.methods[node.Target.Value][method.Name.Value] = lit
+				}
+			}
+		}
+		return true
+	})
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.MatchExpression:
+			c.checkMatch(node)
+		}
+		return true
+	})
+
+	c.checkConstants(program, newScope(nil))
+	c.checkTypes(program)
+
+	return c.Errors
+}
+
+// Tracks which names are declared in a scope, and which of them are constants
+// Scopes mirror the evaluator: functions and match arms get their own,
+// while blocks (if, loops) share the scope they appear in
+type scope struct {
+	names map[string]bool // name -> declared with `const`
+	outer *scope
+}
+
+func newScope(outer *scope) *scope {
+	return &scope{names: map[string]bool{}, outer: outer}
+}
+
+func (s *scope) isConst(name string) bool {
+	for sc := s; sc != nil; sc = sc.outer {
+		if constant, ok := sc.names[name]; ok {
+			return constant
+		}
+	}
+	return false
+}
+
+// Reports assignments to constants, and constants declared twice in the same scope
+func (c *Checker) checkConstants(root ast.Node, s *scope) {
+	ast.Inspect(root, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.LetStatement:
+			names := []*ast.Identifier{node.Name}
+			if node.Pattern != nil {
+				names = ast.PatternNames(node.Pattern)
+			}
+			for _, name := range names {
+				if s.names[name.Value] {
+					c.error("cannot redeclare constant: "+name.Value, name.Token)
+				}
+				s.names[name.Value] = node.Constant
+			}
+
+		case *ast.AssignStatement:
+			if s.isConst(node.Name.Value) {
+				c.error("cannot assign to constant: "+node.Name.Value, node.Name.Token)
+			}
+
+		case *ast.InfixExpression:
+			if ident, ok := node.Left.(*ast.Identifier); ok && isCompoundOperator(node.Operator) && s.isConst(ident.Value) {
+				c.error("cannot assign to constant: "+ident.Value, ident.Token)
+			}
+
+		case *ast.ForStatement:
+			if postfix, ok := node.Post.(*ast.PostfixExpression); ok {
+				if ident, ok := postfix.Left.(*ast.Identifier); ok && s.isConst(ident.Value) {
+					c.error("cannot assign to constant: "+ident.Value, ident.Token)
+				}
+			}
+
+		case *ast.ForInStatement:
+			for _, name := range []*ast.Identifier{node.Key, node.Value} {
+				if name != nil {
+					s.names[name.Value] = false
+				}
+			}
+
+		case *ast.FunctionLiteral:
+			inner := newScope(s)
+			for _, param := range node.Parameters {
+				inner.names[param.Value] = false
+			}
+			c.checkConstants(node.Body, inner)
+			return false
+
+		case *ast.MatchArm:
+			inner := newScope(s)
+			for _, name := range ast.PatternNames(node.Pattern) {
+				inner.names[name.Value] = false
+			}
+			if node.Guard != nil {
+				c.checkConstants(node.Guard, inner)
+			}
+			c.checkConstants(node.Body, inner)
+			return false
+
+		case *ast.SelectCase:
+			inner := newScope(s)
+			if node.Operation != nil {
+				c.checkConstants(node.Operation, s)
+			}
+			if node.Name != nil {
+				inner.names[node.Name.Value] = false
+			}
+			c.checkConstants(node.Body, inner)
+			return false
+		}
+		return true
+	})
+}
+
+func isCompoundOperator(operator string) bool {
+	switch operator {
+	case "+=", "-=", "*=", "/=":
+		return true
+	}
+	return false
+}
+
+// A match is obviously non-exhaustive when no arm is guaranteed to match,
+// which is only the case for an unguarded `_` or binding arm,
+// unguarded arms for both `true` and `false`, or unguarded arms for every variant of an enum
+// Arms after a catch-all arm can never run, so they are reported too
+func (c *Checker) checkMatch(node *ast.MatchExpression) {
+	catchAll := false
+	seenTrue, seenFalse := false, false
+	enum := ""
+	covered := map[string]bool{}
+
+	for _, arm := range node.Arms {
+		if catchAll {
+			c.warn("unreachable match arm, an earlier arm already matches every value", arm.Token)
+			break
+		}
+		if arm.Guard != nil {
+			continue
+		}
+
+		switch pattern := arm.Pattern.(type) {
+		case *ast.WildcardPattern, *ast.BindingPattern:
+			catchAll = true
+		case *ast.LiteralPattern:
+			if boolean, ok := pattern.Value.(*ast.Boolean); ok {
+				seenTrue = seenTrue || boolean.Value
+				seenFalse = seenFalse || !boolean.Value
+			}
+		case *ast.VariantPattern:
+			// Only variants whose fields can't fail to match cover the whole variant
+			if _, known := c.enums[pattern.Enum.Value]; !known || !coversFields(pattern) {
+				continue
+			}
+			enum = pattern.Enum.Value
+			covered[pattern.Variant.Value] = true
+			catchAll = len(c.missingVariants(enum, covered)) == 0
+		}
+	}
+
+	if catchAll || (seenTrue && seenFalse) {
+		return
+	}
+
+	if enum != "" {
+		c.warn("match is not exhaustive, missing "+strings.Join(c.missingVariants(enum, covered), ", "), node.Token)
+		return
+	}
+	c.warn("match is not exhaustive, add a `_` arm to handle any other value", node.Token)
+}
+
+func coversFields(pattern *ast.VariantPattern) bool {
+	for _, field := range pattern.Fields {
+		switch field.(type) {
+		case *ast.WildcardPattern, *ast.BindingPattern:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// The variants of the enum (as Enum.Variant) that haven't been covered yet
+func (c *Checker) missingVariants(enum string, covered map[string]bool) []string {
+	missing := []string{}
+	for _, variant := range c.enums[enum].Variants {
+		if !covered[variant.Name.Value] {
+			missing = append(missing, enum+"."+variant.Name.Value)
+		}
+	}
+	return missing
+}
+
+func (c *Checker) warn(msg string, tok token.Token) {
+	c.report(msg, tok, true)
+}
+
+func (c *Checker) error(msg string, tok token.Token) {
+	c.report(msg, tok, false)
+}
+
+func (c *Checker) report(msg string, tok token.Token, isWarning bool) {
+	if tok.Line < 1 || tok.Line > len(c.lines) {
+		c.Errors = append(c.Errors, &errors.Error{Message: msg, Line: tok.Line, Col: tok.Col, Stage: "Checker", IsWarning: isWarning})
+		return
+	}
+	c.Errors = append(c.Errors, errors.New(msg, tok.Line, tok.Col, "Checker", c.lines, isWarning))
+}
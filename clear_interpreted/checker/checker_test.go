@@ -0,0 +1,216 @@
+package checker
+
+import (
+	"testing"
+
+	"github.com/ajtroup1/clear/lexer"
+	"github.com/ajtroup1/clear/logger"
+	"github.com/ajtroup1/clear/parser"
+)
+
+func TestMatchExhaustiveness(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"match (x) { 0 => 1, _ => 2 }", []string{}},
+		{"match (x) { 0 => 1, n => n }", []string{}},
+		{"match (x) { true => 1, false => 0 }", []string{}},
+		{"match (x) { 0 => 1, 1 => 2 }", []string{"match is not exhaustive, add a `_` arm to handle any other value"}},
+		{"match (x) { n if n > 0 => n }", []string{"match is not exhaustive, add a `_` arm to handle any other value"}},
+		{"match (x) { true => 1 }", []string{"match is not exhaustive, add a `_` arm to handle any other value"}},
+		{"match (x) { [a, ...rest] => a, {name} => name }", []string{"match is not exhaustive, add a `_` arm to handle any other value"}},
+		{"match (x) { _ => 1, 0 => 2 }", []string{"unreachable match arm, an earlier arm already matches every value"}},
+		{"let f = fn(x) { match (x) { 0 => 1 } };", []string{"match is not exhaustive, add a `_` arm to handle any other value"}},
+		{"enum Color { Red, Green }; match (c) { Color.Red => 1, Color.Green => 2 }", []string{}},
+		{"enum Color { Red, Green }; match (c) { Color.Red => 1 }", []string{"match is not exhaustive, missing Color.Green"}},
+		{"enum Shape { Circle(r), Rect(w, h) }; match (s) { Shape.Circle(r) => r, Shape.Rect(0, h) => h }", []string{"match is not exhaustive, missing Shape.Rect"}},
+		{"enum Shape { Circle(r), Rect(w, h) }; match (s) { Shape.Circle(_) => 1, Shape.Rect => 2, _ => 3 }", []string{"unreachable match arm, an earlier arm already matches every value"}},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := parser.New(l, log, false)
+		program := p.ParseProgram()
+		if len(p.Errors) != 0 {
+			t.Fatalf("parser errors for %q: %s", tt.input, p.Errors[0].Message)
+		}
+
+		warnings := New(l.Lines).Check(program)
+		if len(warnings) != len(tt.expected) {
+			t.Errorf("wrong number of warnings for %q. expected=%d, got=%d", tt.input, len(tt.expected), len(warnings))
+			continue
+		}
+		for i, warning := range warnings {
+			if !warning.IsWarning {
+				t.Errorf("expected a warning, got an error: %s", warning.Message)
+			}
+			if warning.Message != tt.expected[i] {
+				t.Errorf("wrong warning. expected=%q, got=%q", tt.expected[i], warning.Message)
+			}
+		}
+	}
+}
+
+func TestConstantReassignment(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"const x = 1; let y = x + 1;", []string{}},
+		{"const x = 1; x = 2;", []string{"cannot assign to constant: x"}},
+		{"const x = 1; x += 2;", []string{"cannot assign to constant: x"}},
+		{"const x = 1; let f = fn() { x = 2; };", []string{"cannot assign to constant: x"}},
+		{"const x = 1; let f = fn(x) { x = 2; };", []string{}},
+		{"const x = 1; let f = fn() { let x = 0; x = 2; };", []string{}},
+		{"const x = 1; const x = 2;", []string{"cannot redeclare constant: x"}},
+		{"const {name} = user; if (true) { name = 1; }", []string{"cannot assign to constant: name"}},
+		{"const n = 1; match (v) { n if n > 0 => { n = 2; }, _ => 0 }", []string{}},
+		{"const i = 0; for (let j = 0; j < 3; i++) { }", []string{"cannot assign to constant: i"}},
+		{"const v = 1; select { v = recv(ch) => { v = 2; } _ => { v = 3; } }", []string{"cannot assign to constant: v"}},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := parser.New(l, log, false)
+		program := p.ParseProgram()
+		if len(p.Errors) != 0 {
+			t.Fatalf("parser errors for %q: %s", tt.input, p.Errors[0].Message)
+		}
+
+		errs := New(l.Lines).Check(program)
+		if len(errs) != len(tt.expected) {
+			t.Errorf("wrong number of errors for %q. expected=%d, got=%d", tt.input, len(tt.expected), len(errs))
+			continue
+		}
+		for i, err := range errs {
+			if err.IsWarning {
+				t.Errorf("expected an error, got a warning: %s", err.Message)
+			}
+			if err.Message != tt.expected[i] {
+				t.Errorf("wrong error. expected=%q, got=%q", tt.expected[i], err.Message)
+			}
+		}
+	}
+}
+
+func TestTypeCheck(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []string
+	}{
+		{"let x: int = 5; let y: number = x; let z: any = \"a\";", []string{}},
+		{"let x = 5; let y = x + 1.5; let z: float = y;", []string{}},
+		{"let x: int = \"a\";", []string{"value of x must be int, got string"}},
+		{"let x: int = 1; x = \"a\";", []string{"value of x must be int, got string"}},
+		{"let x = 1; x = \"a\"; let y: string = x;", []string{}},
+		{"let x: widget = 1;", []string{"unknown type: widget"}},
+		{"1 + \"a\";", []string{"type mismatch: int + string"}},
+		{"let s = \"a\"; s += 1;", []string{"type mismatch: string += int"}},
+		{"let f = fn(a: int, b: string) -> bool { true }; f(1, \"a\");", []string{}},
+		{"let f = fn(a: int, b: string) -> bool { true }; f(\"a\", \"b\");", []string{"argument a of f must be int, got string"}},
+		{"let f = fn(a, b) { a }; f(1);", []string{"wrong number of arguments to f. got=1, want at least 2"}},
+		{"let f = fn(a: int) { a }; f(...[1]);", []string{}},
+		{"let f = fn(a) -> int { \"a\" };", []string{"return value must be int, got string"}},
+		{"let f = fn(a) -> int { if (a) { return \"a\"; } 1 };", []string{"return value must be int, got string"}},
+		{"let f = fn(a: int) { a * 2 }; let s: string = f(1);", []string{"value of s must be string, got int"}},
+		{"let f = fn(a) { a }; let s: string = f(1);", []string{}},
+		{"math.abs(\"a\");", []string{"argument 1 of math.abs must be number, got string"}},
+		{"math.pow(1);", []string{"wrong number of arguments to math.pow. got=1, want=2"}},
+		{"math.nope(1);", []string{"function not found in module 'math': nope"}},
+		{"mod math: [abs]; let n: string = abs(1);", []string{"value of n must be string, got number"}},
+		{"let math = {abs: 1}; math.abs;", []string{}},
+		{"let n: int = \"abc\".upper();", []string{"value of n must be int, got string"}},
+		{"\"abc\".upper(1);", []string{"wrong number of arguments to upper. got=1, want=0"}},
+		{"\"abc\".nope();", []string{"string has no member 'nope'"}},
+		{"[1].push(2, 3);", []string{}},
+		{"type Point { x: int, y = 0 }; let p = Point(1); let s: string = p.x;", []string{"value of s must be string, got int"}},
+		{"type Point { x: int, y = 0 }; Point();", []string{"wrong number of arguments to Point. got=0, want 1 to 2"}},
+		{"type Point { x: int }; Point(\"a\");", []string{"argument x of Point must be int, got string"}},
+		{"type Point { x: int }; Point(1).z;", []string{"Point has no field 'z'"}},
+		{"enum Color { Red }; let c: Color = Color.Red; let n: int = c.value;", []string{}},
+		{"enum Color { Red }; let c: int = Color.Red;", []string{"value of c must be int, got Color"}},
+		{"enum Color { Red }; Color.Blue;", []string{"enum Color has no variant 'Blue'"}},
+		{"enum Shape { Circle(r) }; Shape.Circle(1, 2);", []string{"wrong number of arguments to Shape.Circle. got=2, want=1"}},
+		{"let v = 1; match (v) { n: widget => n, _ => 0 };", []string{"unknown type: widget"}},
+		{"let v = 1; match (v) { n: int => n + \"a\", _ => 0 };", []string{"type mismatch: int + string"}},
+		{"for (c in \"abc\") { let n: int = c; }", []string{"value of n must be int, got string"}},
+		{"1 is widget;", []string{"unknown type: widget"}},
+		{"let xs: array<int> = [1, 2]; let h: hash<string, array<int>> = {\"a\": xs};", []string{}},
+		{"let xs: array<int> = [\"a\"];", []string{"value of xs must be array<int>, got array<string>"}},
+		{"let xs: array<int> = []; let ys: array<string> = xs;", []string{"value of ys must be array<string>, got array<int>"}},
+		{"let xs: array<int> = [1, \"a\"]; let ys: array = xs;", []string{}},
+		{"let xs: array<int> = [1]; xs.push(\"a\");", []string{"argument 1 of push must be int, got string"}},
+		{"let xs: array<int> = [1]; arrays.push(xs, 2, \"a\");", []string{"argument 3 of arrays.push must be int, got string"}},
+		{"let xs = [1]; xs.push(\"a\");", []string{}},
+		{"let xs: array<int> = [1]; let s: string = xs.first();", []string{"value of s must be string, got int"}},
+		{"let xs: array<string> = [\"a\"]; let s: string = xs[0]; let n: int = xs.len();", []string{}},
+		{"let h: hash<string, int> = {}; let ks: array<int> = hashes.keys(h);", []string{"value of ks must be array<int>, got array<string>"}},
+		{"let h: hash<string, int> = {}; hashes.has(h, 1);", []string{"argument 2 of hashes.has must be string, got int"}},
+		{"let h: hash<string, int> = {}; for (k, v in h) { let s: string = v; }", []string{"value of s must be string, got int"}},
+		{"let words: array<string> = \"a b\".split(\" \");", []string{}},
+		{"fn first<T>(xs: array<T>) -> T { xs[0] } let n: int = first([1, 2]); let s: string = first([1]);", []string{"value of s must be string, got int"}},
+		{"fn same<T>(a: T, b: T) { a } same(1, 2); same(1, \"a\");", []string{"argument b of same must be int, got string"}},
+		{"fn wrap<T>(x: T) -> array<T> { [x] } let xs: array<string> = wrap(1);", []string{"value of xs must be array<string>, got array<int>"}},
+		{"fn id<T>(x: T) -> T { let y: T = x; y } let s: string = id(\"a\");", []string{}},
+		{"let x: T = 1;", []string{"unknown type: T"}},
+		{"let xs: array<int, int> = []; let n: int<string> = 1;", []string{"wrong number of type arguments for array. got=2, want=1", "wrong number of type arguments for int. got=1, want=0"}},
+		{"type Tree { children: array<Tree> }; let s: string = Tree([]).children;", []string{"value of s must be string, got array<Tree>"}},
+		{"interface Shape { area(); } type Circle { r: int }; impl Shape for Circle { fn area(self) { self.r } } let s: Shape = Circle(1); s.area();", []string{}},
+		{"interface Shape { area(); } type Circle { r: int }; let s: Shape = Circle(1); impl Circle { fn area(self) { 1 } }", []string{}},
+		{"interface Shape { area(); } type Circle { r: int }; let s: Shape = Circle(1);", []string{"value of s must be Shape, got Circle"}},
+		{"interface Shape { area(); } let f = fn(s: Shape) { s.area(1); s.size; }; f(1);", []string{"wrong number of arguments to area. got=1, want=0", "Shape has no method 'size'", "argument s of f must be Shape, got int"}},
+		{"interface Shape { area(); } type Circle { r: int }; impl Shape for Circle { fn size(self) { 1 } }", []string{"Circle does not implement Shape: missing method area"}},
+		{"interface Shape { area(); } type Circle { r: int }; impl Shape for Circle { fn area(self, by) { 1 } }", []string{"Circle does not implement Shape: method area takes 1 arguments, want 0"}},
+		{"interface Shape { area(); } type Circle { r: int }; impl Shape for Circle { fn area() { 1 } }", []string{"method area of Circle must take the value it's called on as its first parameter", "Circle does not implement Shape: method area is missing its receiver"}},
+		{"type Circle { r: int }; impl Circle { fn area(self) { let s: string = self.r; } }", []string{"value of s must be string, got int"}},
+		{"type Circle { r: int }; impl Circle { fn area(self, by: int) -> int { self.r * by } } let s: string = Circle(1).area(2); Circle(1).size();", []string{"Circle has no field 'size'"}},
+		{"type Circle { r }; impl Widget for Circle { fn area(self) { 1 } } impl Circle for Circle { }", []string{"unknown type: Widget", "Circle is not an interface"}},
+		{"let x = 1; impl int { fn f(self) { 1 } } impl Nope { }", []string{"cannot implement methods for int, only record types and enums have methods", "unknown type: Nope"}},
+		{"type Vec { x: int }; impl Vec { fn __mul__(self, k) { Vec(self.x * k) } fn __lt__(self, o) { true } } Vec(1) * 2; let b: bool = Vec(1) < 3; Vec(1) - 2;", []string{"type mismatch: Vec - int"}},
+		{"type Money { c: int }; impl Money { fn compare(self, other) { 0 } } Money(1) < 2;", []string{}},
+		{"fn* count(n: int) { yield n; } let it: iterator = count(1); let n: int = count(1);", []string{"value of n must be int, got iterator"}},
+		{"fn* count(n: int) -> int { yield n; return \"a\"; } count(1).take(\"a\"); count(1).nope();", []string{"return value must be int, got iterator", "argument 1 of take must be int, got string", "iterator has no member 'nope'"}},
+		{"fn* count() { yield 1 + \"a\"; } let xs: array = iter.toArray(count());", []string{"type mismatch: int + string"}},
+		{"fn add(a: int, b: int) -> int { a + b } let t: task = spawn add(1, \"2\"); let n: int = spawn add(1, 2); t.wait();", []string{"argument b of add must be int, got string", "value of n must be int, got task"}},
+		{"let c: channel = chan(1); let m: mutex = sync.mutex(); m.lock(); m.unlock(1); select { v = recv(c) => { v + 1 } _ => { 1 + \"a\" } }", []string{"wrong number of arguments to unlock. got=1, want=0", "type mismatch: int + string"}},
+		{"let t: timer = time.every(10, fn() { 1 }); t.cancel(); let b: string = t.cancel(); time.after(\"10\", fn() { 1 });", []string{"value of b must be string, got bool", "argument 1 of time.after must be int, got string"}},
+		{"let t: datetime = time.date(2026, 1, 1); let d: duration = t - t; let later: datetime = t + time.hours(1) * 2; let ratio: float = d / time.minutes(1); let b: bool = t < later; let n: int = t + d; t + 1; t + t;", []string{"value of n must be int, got datetime", "type mismatch: datetime + int"}},
+		{"let s: server = http.serve(0, http.router({\"/\": fn(req) { req.path }})); let u: string = s.url(); let b: int = s.stop(); http.serve(\"80\", fn(req) { 1 });", []string{"value of b must be int, got bool", "argument 1 of http.serve must be int, got string"}},
+		{"let re: regex = regex.compile(\"a+\"); let b: bool = re.match(\"aa\"); let xs: array<string> = re.findAll(\"aa\", 1); let n: int = re.pattern(); re.split(1);", []string{"value of n must be int, got string", "argument 1 of split must be string, got int"}},
+		{"let b: builder = strings.builder(); b.write(1, \"a\").writeLine(); let s: string = b.build(); let n: int = \"ab\".indexOf(\"b\"); let t: int = \"a\".padLeft(3); \"a\".repeat(\"2\");", []string{"value of t must be int, got string", "argument 1 of repeat must be int, got string"}},
+		{"let xs: array<int> = [3, 1].sorted(); let n: int = [1, 2].sum(); let m: int = [1, 2].max(); let s: string = [1, 2].join(\",\"); let c: array<array<int>> = xs.chunk(2); let bad: string = xs.removeAt(0); xs.insert(\"0\", 1);", []string{"value of bad must be string, got int", "argument 1 of insert must be int, got string"}},
+		{"let u: array<int> = [1].union([2]); let i: string = [1].intersection([2]); [\"a\"].difference([1]);", []string{"value of i must be string, got array<int>", "argument 1 of difference must be array<string>, got array<int>"}},
+		{"mod math: [E]; let pi: float = math.PI; let e: float = E; let n: int = math.floor(pi); let m: float = math.stats.mean([1, 2]); let s: string = math.sqrt(2); math.stats.percentile([1], \"50\"); math.TAU;", []string{"value of s must be string, got float", "argument 2 of math.stats.percentile must be number, got string", "function not found in module 'math': TAU"}},
+		{"type Money { c: int }; impl Comparable for Money { fn compare(self, other) { 0 } } let xs: array<Comparable> = [Money(1)]; let s: Stringer = Money(1);", []string{"value of s must be Stringer, got Money"}},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := parser.New(l, log, false)
+		program := p.ParseProgram()
+		if len(p.Errors) != 0 {
+			t.Fatalf("parser errors for %q: %s", tt.input, p.Errors[0].Message)
+		}
+
+		errs := New(l.Lines).Check(program)
+		if len(errs) != len(tt.expected) {
+			t.Errorf("wrong number of errors for %q. expected=%d, got=%d", tt.input, len(tt.expected), len(errs))
+			for _, err := range errs {
+				t.Logf("\t%s", err.Message)
+			}
+			continue
+		}
+		for i, err := range errs {
+			if err.IsWarning {
+				t.Errorf("expected an error, got a warning: %s", err.Message)
+			}
+			if err.Message != tt.expected[i] {
+				t.Errorf("wrong error. expected=%q, got=%q", tt.expected[i], err.Message)
+			}
+		}
+	}
+}
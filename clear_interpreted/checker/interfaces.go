@@ -0,0 +1,149 @@
+package checker
+
+import (
+	"fmt"
+
+	"github.com/ajtroup1/clear/ast"
+	"github.com/ajtroup1/clear/object"
+)
+
+// A method an interface requires, with the number of arguments it takes after the receiver
+type interfaceMethod struct {
+	name   string
+	params int
+}
+
+// The methods of a declared interface or builtin protocol, in declaration order
+// Declared interfaces hide the protocols of the same name, like variables do in the evaluator
+func (c *Checker) interfaceMethods(name string) ([]interfaceMethod, bool) {
+	if decl, ok := c.interfaces[name]; ok {
+		methods := []interfaceMethod{}
+		for _, method := range decl.Methods {
+			methods = append(methods, interfaceMethod{name: method.Name.Value, params: len(method.Parameters)})
+		}
+		return methods, true
+	}
+
+	if protocol, ok := object.Protocols[name]; ok {
+		methods := []interfaceMethod{}
+		for _, method := range protocol.Methods {
+			methods = append(methods, interfaceMethod{name: method.Name, params: len(method.Params)})
+		}
+		return methods, true
+	}
+
+	return nil, false
+}
+
+// Record types and enums hide the protocols of the same name
+func (c *Checker) isInterface(name string) bool {
+	if c.records[name] != nil || c.enums[name] != nil {
+		return false
+	}
+	_, ok := c.interfaceMethods(name)
+	return ok
+}
+
+// Describes why a type with the given methods doesn't satisfy an interface, or "" when it does
+// The messages are the evaluator's, so the same mistake reads the same either way
+func (c *Checker) unsatisfied(methods map[string]*ast.FunctionLiteral, iface string) string {
+	want, _ := c.interfaceMethods(iface)
+	for _, method := range want {
+		lit, ok := methods[method.name]
+		if !ok {
+			return "missing method " + method.name
+		}
+		if len(lit.Parameters) == 0 {
+			return fmt.Sprintf("method %s is missing its receiver", method.name)
+		}
+		if got := len(lit.Parameters) - 1; got != method.params {
+			return fmt.Sprintf("method %s takes %d arguments, want %d", method.name, got, method.params)
+		}
+	}
+	return ""
+}
+
+// Whether values of type actual always satisfy the interface
+// Methods are collected from every impl up front, since they may be attached after the value is used
+// Other interfaces are left to the evaluator
+func (c *Checker) satisfies(actual *typ, iface string) bool {
+	if c.isInterface(actual.name) {
+		return true
+	}
+	return c.unsatisfied(c.methods[actual.name], iface) == ""
+}
+
+func (c *Checker) checkInterface(node *ast.InterfaceStatement, s *typeScope) {
+	for _, method := range node.Methods {
+		for _, param := range method.ParameterTypes {
+			if param != nil {
+				c.resolveType(param, s)
+			}
+		}
+		if method.ReturnType != nil {
+			c.resolveType(method.ReturnType, s)
+		}
+	}
+}
+
+// Methods are checked like functions, with the receiver typed as the type they're attached to
+// Naming an interface reports a type missing its methods, counting the methods attached so far like the evaluator does
+func (c *Checker) checkImpl(node *ast.ImplStatement, s *typeScope) {
+	target := node.Target.Value
+	if c.records[target] == nil && c.enums[target] == nil {
+		if c.checkTypeName(target, node.Target.Token, s) {
+			c.error(fmt.Sprintf("cannot implement methods for %s, only record types and enums have methods", target), node.Target.Token)
+		}
+		return
+	}
+
+	if c.attached[target] == nil {
+		c.attached[target] = map[string]*ast.FunctionLiteral{}
+	}
+
+	receiver := named(target)
+	for _, method := range node.Methods {
+		lit, ok := method.Value.(*ast.FunctionLiteral)
+		if !ok {
+			continue
+		}
+		if len(lit.Parameters) == 0 {
+			c.error(fmt.Sprintf("method %s of %s must take the value it's called on as its first parameter", method.Name.Value, target), method.Name.Token)
+		}
+		c.attached[target][method.Name.Value] = lit
+		c.checkFunction(lit, s, receiver)
+	}
+
+	if node.Interface == nil {
+		return
+	}
+	if !c.isInterface(node.Interface.Value) {
+		if c.checkTypeName(node.Interface.Value, node.Interface.Token, s) {
+			c.error(node.Interface.Value+" is not an interface", node.Interface.Token)
+		}
+		return
+	}
+	if reason := c.unsatisfied(c.attached[target], node.Interface.Value); reason != "" {
+		c.error(fmt.Sprintf("%s does not implement %s: %s", target, node.Interface.Value, reason), node.Target.Token)
+	}
+}
+
+// Methods of user types are called like functions without their receiver
+// Their parameters aren't checked here, since the receiver takes up the first one
+func methodType(name string, lit *ast.FunctionLiteral) *fnType {
+	min := len(lit.Parameters) - 1
+	if min < 0 {
+		min = 0
+	}
+	return &fnType{name: name, result: anyT, min: min, max: -1}
+}
+
+// Whether the type defines a method for the operator, which may take any right operand
+// Comparable types are ordered with compare when they don't define __lt__ or __gt__
+func (c *Checker) overloads(t *typ, operator string) bool {
+	methods := c.methods[t.name]
+	if methods[object.OperatorMethods[operator]] != nil {
+		return true
+	}
+	return (operator == "<" || operator == ">") && methods["compare"] != nil
+}
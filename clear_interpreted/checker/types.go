@@ -0,0 +1,1129 @@
+package checker
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ajtroup1/clear/ast"
+	"github.com/ajtroup1/clear/modules"
+	"github.com/ajtroup1/clear/object"
+	"github.com/ajtroup1/clear/token"
+)
+
+/*
+	The type check infers a type for every expression it can, and reports values
+	that can't have the type they're annotated with, arguments that don't fit a
+	function's signature and operators used on mismatched types
+
+	It is gradual: anything it can't work out is `any`, which is never reported,
+	so unannotated code is only checked where its types are obvious
+	Types are named the same way they are in annotations ("int", "string", "Point", "array<int>", ...)
+
+	Generic functions, fn<T>(xs: array<T>) -> T, are checked with T standing in
+	for any type. At every call T is bound to the type of the first argument
+	it appears in, and the other arguments and the result follow from that
+*/
+
+const anyType = "any"
+
+// Type names that are always available, the same ones accepted by type patterns
+var builtinTypes = map[string]bool{
+	"int": true, "float": true, "number": true, "string": true, "bool": true,
+	"array": true, "hash": true, "range": true, "iterator": true, "channel": true, "task": true, "mutex": true, "timer": true, "datetime": true, "duration": true, "server": true, "regex": true, "builder": true, "fn": true, "null": true, anyType: true,
+}
+
+// The number of type arguments each builtin type takes, array<T> and hash<K, V>
+// Without type arguments, the contents of an array or hash aren't known
+var typeArities = map[string]int{
+	"array": 1,
+	"hash":  2,
+}
+
+// A static type. Type arguments are kept for arrays and hashes, array<int> is {name: "array", args: [int]}
+// Type parameters of generic functions have param set, and are replaced at every call
+// Types are never modified once they're made, so they can be shared freely
+type typ struct {
+	name  string
+	args  []*typ
+	param bool
+}
+
+var anyT = named(anyType)
+
+func named(name string, args ...*typ) *typ {
+	return &typ{name: name, args: args}
+}
+
+func (t *typ) String() string {
+	if len(t.args) == 0 {
+		return t.name
+	}
+
+	args := []string{}
+	for _, arg := range t.args {
+		args = append(args, arg.String())
+	}
+	return t.name + "<" + strings.Join(args, ", ") + ">"
+}
+
+// Type parameters are treated like `any` inside the function that declares them
+func (t *typ) isAny() bool {
+	return t.name == anyType || t.param
+}
+
+// The i-th type argument, `any` when it isn't known
+func (t *typ) arg(i int) *typ {
+	if i < len(t.args) {
+		return t.args[i]
+	}
+	return anyT
+}
+
+// The type of a value that can change, like an unannotated variable
+// Pushing onto an array changes what it holds, so only what it is stays known
+func erase(t *typ) *typ {
+	if len(t.args) == 0 {
+		return t
+	}
+	return named(t.name)
+}
+
+// Values of these types have the functions of a module as methods, like in the evaluator
+// Hashes are left out, since their own keys take priority over methods
+var methodModules = map[string]string{
+	"string":   "strings",
+	"array":    "arrays",
+	"iterator": "iter",
+	"task":     "sync",
+	"mutex":    "sync",
+	"timer":    "time",
+	"datetime": "time",
+	"duration": "time",
+	"server":   "http",
+	"regex":    "regex",
+	"builder":  "strings",
+}
+
+// Types that only have some of their module's functions as methods, like in the evaluator
+var methodSubsets = map[string][]string{
+	"builder": object.BuilderMethods,
+}
+
+// What is known about the parameters and result of something callable
+type fnType struct {
+	name     string   // used in messages
+	params   []*typ   // parameter types, which may contain type parameters
+	names    []string // parameter names, nil when they aren't known (builtins)
+	result   *typ
+	min      int    // arguments that have to be given
+	max      int    // -1 when extra arguments are allowed
+	variadic bool   // extra arguments have the type of the last parameter
+	bound    []*typ // arguments given before the call, the receiver of a method
+}
+
+type variable struct {
+	typ       *typ
+	fn        *fnType // set when the variable holds something callable with a known signature
+	declares  string  // set when the variable holds a record type or enum, its name
+	annotated bool    // annotated variables keep their type, others become `any` when reassigned differently
+	imported  bool    // imported from a module, so a module of the same name is still reachable
+}
+
+// Types are scoped like the evaluator: functions and match arms get their own scope
+type typeScope struct {
+	vars       map[string]*variable
+	typeParams map[string]bool // type parameters of the function this scope belongs to
+	outer      *typeScope
+	fn         *fnScope // the function being checked, nil at the top level
+}
+
+type fnScope struct {
+	result  *typ   // the annotated result type, nil when it isn't annotated
+	returns []*typ // types of every returned value, to infer the result when it isn't annotated
+}
+
+func newTypeScope(outer *typeScope, fn *fnScope) *typeScope {
+	return &typeScope{vars: map[string]*variable{}, typeParams: map[string]bool{}, outer: outer, fn: fn}
+}
+
+func (s *typeScope) lookup(name string) *variable {
+	for sc := s; sc != nil; sc = sc.outer {
+		if v, ok := sc.vars[name]; ok {
+			return v
+		}
+	}
+	return nil
+}
+
+func (s *typeScope) isTypeParam(name string) bool {
+	for sc := s; sc != nil; sc = sc.outer {
+		if sc.typeParams[name] {
+			return true
+		}
+	}
+	return false
+}
+
+func (c *Checker) checkTypes(program *ast.Program) {
+	s := newTypeScope(nil, nil)
+	for _, mod := range program.Modules {
+		c.declareImports(mod, s)
+	}
+	c.checkStatements(program.Statements, s)
+}
+
+// Unknown modules and functions are reported by the evaluator
+func (c *Checker) declareImports(mod *ast.ModuleStatement, s *typeScope) {
+	functions, ok := modules.Modules[mod.Name.Value]
+	if !ok {
+		return
+	}
+
+	constants := modules.Constants[mod.Name.Value]
+
+	if mod.ImportAll {
+		for name, builtin := range functions {
+			s.vars[name] = &variable{typ: named("fn"), fn: builtinFnType(name, builtin.Signature), imported: true}
+		}
+		for name, value := range constants {
+			s.vars[name] = &variable{typ: constantType(value), annotated: true, imported: true}
+		}
+		return
+	}
+	for _, name := range mod.Imports {
+		if builtin, ok := functions[name.Value]; ok {
+			s.vars[name.Value] = &variable{typ: named("fn"), fn: builtinFnType(name.Value, builtin.Signature), imported: true}
+		}
+		if value, ok := constants[name.Value]; ok {
+			s.vars[name.Value] = &variable{typ: constantType(value), annotated: true, imported: true}
+		}
+	}
+}
+
+// The type of a module constant, like float for math.PI
+func constantType(value object.Object) *typ {
+	switch value.Type() {
+	case object.INTEGER_OBJ:
+		return named("int")
+	case object.FLOAT_OBJ:
+		return named("float")
+	case object.STRING_OBJ:
+		return named("string")
+	case object.BOOLEAN_OBJ:
+		return named("bool")
+	}
+	return anyT
+}
+
+func builtinFnType(name string, sig *object.Signature) *fnType {
+	if sig == nil {
+		return nil
+	}
+	fn := &fnType{name: name, result: signatureType(sig.Return, sig.TypeParams), min: len(sig.Params), max: len(sig.Params), variadic: sig.Variadic}
+	for _, param := range sig.Params {
+		fn.params = append(fn.params, signatureType(param, sig.TypeParams))
+	}
+	if sig.Variadic {
+		fn.min--
+		fn.max = -1
+	}
+	return fn
+}
+
+// Parses a type written in a builtin's signature, like "array<T>" or "hash<string, any>"
+// Signatures are written by hand alongside the builtins, so they're assumed to be well formed
+func signatureType(src string, typeParams []string) *typ {
+	t, _ := parseSignatureType(strings.ReplaceAll(src, " ", ""), typeParams)
+	return t
+}
+
+// Returns the parsed type and whatever follows it
+func parseSignatureType(src string, typeParams []string) (*typ, string) {
+	end := strings.IndexAny(src, "<,>")
+	if end < 0 {
+		end = len(src)
+	}
+
+	t := &typ{name: src[:end]}
+	for _, name := range typeParams {
+		if name == t.name {
+			t.param = true
+		}
+	}
+
+	rest := src[end:]
+	if !strings.HasPrefix(rest, "<") {
+		return t, rest
+	}
+	rest = rest[1:]
+
+	for {
+		var arg *typ
+		arg, rest = parseSignatureType(rest, typeParams)
+		t.args = append(t.args, arg)
+		if !strings.HasPrefix(rest, ",") {
+			break
+		}
+		rest = rest[1:]
+	}
+
+	return t, strings.TrimPrefix(rest, ">")
+}
+
+// Returns the type of the last statement, which is the value of a block
+func (c *Checker) checkStatements(stmts []ast.Statement, s *typeScope) *typ {
+	last := anyT
+	for _, stmt := range stmts {
+		last = c.checkStatement(stmt, s)
+	}
+	return last
+}
+
+func (c *Checker) checkStatement(stmt ast.Statement, s *typeScope) *typ {
+	switch node := stmt.(type) {
+	case *ast.ExpressionStatement:
+		return c.infer(node.Expression, s)
+
+	case *ast.LetStatement:
+		c.checkLet(node, s)
+
+	case *ast.AssignStatement:
+		c.checkAssign(node, s)
+
+	case *ast.ReturnStatement:
+		typ := c.infer(node.ReturnValue, s)
+		if s.fn != nil {
+			s.fn.returns = append(s.fn.returns, typ)
+			if s.fn.result != nil && !c.assignable(typ, s.fn.result) {
+				c.error(fmt.Sprintf("return value must be %s, got %s", s.fn.result, typ), node.Token)
+			}
+		}
+
+	case *ast.BlockStatement:
+		return c.checkStatements(node.Statements, s)
+
+	case *ast.WhileStatement:
+		c.infer(node.Condition, s)
+		c.checkBlock(node.Body, s)
+
+	case *ast.ForStatement:
+		if node.Init != nil {
+			c.checkStatement(node.Init, s)
+		}
+		c.infer(node.Condition, s)
+		c.infer(node.Post, s)
+		c.checkBlock(node.Body, s)
+
+	case *ast.ForInStatement:
+		c.checkForIn(node, s)
+
+	case *ast.TypeStatement:
+		c.checkTypeStatement(node, s)
+
+	case *ast.EnumStatement:
+		for _, variant := range node.Variants {
+			c.infer(variant.Value, s)
+		}
+		s.vars[node.Name.Value] = &variable{typ: anyT, declares: node.Name.Value}
+
+	case *ast.InterfaceStatement:
+		c.checkInterface(node, s)
+
+	case *ast.ImplStatement:
+		c.checkImpl(node, s)
+	}
+
+	return anyT
+}
+
+func (c *Checker) checkBlock(block *ast.BlockStatement, s *typeScope) *typ {
+	if block == nil {
+		return anyT
+	}
+	return c.checkStatements(block.Statements, s)
+}
+
+func (c *Checker) checkLet(node *ast.LetStatement, s *typeScope) {
+	typ, fn := anyT, (*fnType)(nil)
+	if node.Value != nil {
+		typ, fn = c.inferFn(node.Value, s)
+	}
+
+	if node.Pattern != nil {
+		for _, name := range ast.PatternNames(node.Pattern) {
+			s.vars[name.Value] = &variable{typ: anyT}
+		}
+		return
+	}
+
+	// Functions are named after the variable they're declared as, for messages
+	if _, isLiteral := node.Value.(*ast.FunctionLiteral); isLiteral && fn != nil {
+		fn.name = node.Name.Value
+	}
+
+	v := &variable{typ: erase(typ), fn: fn, declares: c.declares(node.Value, s)}
+	if node.TypeName != nil {
+		annotation := c.resolveType(node.TypeName, s)
+		if !c.assignable(typ, annotation) {
+			c.error(fmt.Sprintf("value of %s must be %s, got %s", node.Name.Value, annotation, typ), node.Name.Token)
+		}
+		v.typ = annotation
+		v.annotated = true
+	}
+	s.vars[node.Name.Value] = v
+}
+
+// Annotated variables have to keep their type, others simply lose it
+func (c *Checker) checkAssign(node *ast.AssignStatement, s *typeScope) {
+	typ, fn := c.inferFn(node.Value, s)
+
+	v := s.lookup(node.Name.Value)
+	if v == nil {
+		return
+	}
+
+	if v.annotated {
+		if !c.assignable(typ, v.typ) {
+			c.error(fmt.Sprintf("value of %s must be %s, got %s", node.Name.Value, v.typ, typ), node.Name.Token)
+		}
+		return
+	}
+
+	if v.typ.String() != erase(typ).String() {
+		v.typ = anyT
+	}
+	v.fn = fn
+	v.declares = c.declares(node.Value, s)
+}
+
+// With a single loop variable, hashes bind their keys while everything else binds its elements
+func (c *Checker) checkForIn(node *ast.ForInStatement, s *typeScope) {
+	typ := c.infer(node.Iterable, s)
+
+	key, value := anyT, anyT
+	switch typ.name {
+	case "array":
+		key, value = named("int"), typ.arg(0)
+	case "hash":
+		key, value = typ.arg(0), typ.arg(1)
+		if node.Key == nil {
+			value = typ.arg(0)
+		}
+	case "string":
+		key, value = named("int"), named("string")
+	case "range":
+		key, value = named("int"), named("int")
+	}
+	if enum := c.declares(node.Iterable, s); c.enums[enum] != nil {
+		key, value = named("int"), named(enum)
+	}
+
+	if node.Key != nil {
+		s.vars[node.Key.Value] = &variable{typ: key}
+	}
+	s.vars[node.Value.Value] = &variable{typ: value}
+
+	c.checkBlock(node.Body, s)
+}
+
+func (c *Checker) checkTypeStatement(node *ast.TypeStatement, s *typeScope) {
+	constructor := &fnType{name: node.Name.Value, result: named(node.Name.Value), max: len(node.Fields)}
+	c.fields[node.Name.Value] = map[string]*typ{}
+
+	for _, field := range node.Fields {
+		typ := anyT
+		if field.TypeName != nil {
+			typ = c.resolveType(field.TypeName, s)
+		}
+		c.fields[node.Name.Value][field.Name.Value] = typ
+		if field.Default != nil {
+			if defaultType := c.infer(field.Default, s); !c.assignable(defaultType, typ) {
+				c.error(fmt.Sprintf("field %s of %s must be %s, got %s", field.Name.Value, node.Name.Value, typ, defaultType), field.Name.Token)
+			}
+		} else {
+			constructor.min = len(constructor.params) + 1
+		}
+		constructor.params = append(constructor.params, typ)
+		constructor.names = append(constructor.names, field.Name.Value)
+	}
+
+	s.vars[node.Name.Value] = &variable{typ: anyT, fn: constructor, declares: node.Name.Value}
+}
+
+// Reports unknown type names, returning whether the name is known
+func (c *Checker) checkTypeName(name string, tok token.Token, s *typeScope) bool {
+	if builtinTypes[name] || c.records[name] != nil || c.enums[name] != nil || c.isInterface(name) || s.isTypeParam(name) {
+		return true
+	}
+	if v := s.lookup(name); v != nil && v.declares != "" {
+		return true
+	}
+	c.error("unknown type: "+name, tok)
+	return false
+}
+
+// Resolves an annotation, reporting unknown type names and wrong numbers of type arguments
+// Whatever can't be resolved is `any`
+func (c *Checker) resolveType(annotation *ast.TypeExpr, s *typeScope) *typ {
+	if !c.checkTypeName(annotation.Name, annotation.Token, s) {
+		return anyT
+	}
+
+	// Builtin type names take priority, like in the evaluator
+	param := !builtinTypes[annotation.Name] && s.isTypeParam(annotation.Name)
+	if want := typeArities[annotation.Name]; len(annotation.Args) > 0 && (param || len(annotation.Args) != want) {
+		c.error(fmt.Sprintf("wrong number of type arguments for %s. got=%d, want=%d", annotation.Name, len(annotation.Args), want), annotation.Token)
+		return named(annotation.Name)
+	}
+
+	t := &typ{name: annotation.Name, param: param}
+	for _, arg := range annotation.Args {
+		t.args = append(t.args, c.resolveType(arg, s))
+	}
+	return t
+}
+
+// The record type or enum an expression refers to, if any
+func (c *Checker) declares(exp ast.Expression, s *typeScope) string {
+	if ident, ok := exp.(*ast.Identifier); ok {
+		if v := s.lookup(ident.Value); v != nil {
+			return v.declares
+		}
+	}
+	return ""
+}
+
+func (c *Checker) infer(exp ast.Expression, s *typeScope) *typ {
+	typ, _ := c.inferFn(exp, s)
+	return typ
+}
+
+// Infers the type of an expression, and its signature when it is something callable
+func (c *Checker) inferFn(exp ast.Expression, s *typeScope) (*typ, *fnType) {
+	switch node := exp.(type) {
+	case nil:
+		return anyT, nil
+	case *ast.IntegerLiteral:
+		return named("int"), nil
+	case *ast.FloatLiteral:
+		return named("float"), nil
+	case *ast.StringLiteral:
+		return named("string"), nil
+	case *ast.Boolean:
+		return named("bool"), nil
+
+	case *ast.ArrayLiteral:
+		elements := []*typ{}
+		for _, el := range node.Elements {
+			elements = append(elements, c.infer(el, s))
+		}
+		if el := commonType(elements); el != nil {
+			return named("array", el), nil
+		}
+		return named("array"), nil
+
+	case *ast.HashLiteral:
+		keys, values := []*typ{}, []*typ{}
+		for _, pair := range node.Pairs {
+			keys = append(keys, c.infer(pair.Key, s))
+			values = append(values, c.infer(pair.Value, s))
+		}
+		key, value := commonType(keys), commonType(values)
+		if key == nil && value == nil {
+			return named("hash"), nil
+		}
+		if key == nil {
+			key = anyT
+		}
+		if value == nil {
+			value = anyT
+		}
+		return named("hash", key, value), nil
+
+	case *ast.RangeExpression:
+		c.infer(node.Start, s)
+		c.infer(node.End, s)
+		c.infer(node.Step, s)
+		return named("range"), nil
+
+	case *ast.FunctionLiteral:
+		return named("fn"), c.checkFunction(node, s, nil)
+
+	case *ast.Identifier:
+		if v := s.lookup(node.Value); v != nil {
+			return v.typ, v.fn
+		}
+		return anyT, nil
+
+	case *ast.PrefixExpression:
+		right := c.infer(node.Right, s)
+		switch {
+		case node.Operator == "!":
+			return named("bool"), nil
+		case node.Operator == "-" && isNumeric(right):
+			return right, nil
+		}
+		return anyT, nil
+
+	case *ast.PostfixExpression:
+		return c.infer(node.Left, s), nil
+
+	case *ast.InfixExpression:
+		return c.inferInfix(node, s), nil
+
+	case *ast.IfExpression:
+		c.infer(node.Condition, s)
+		consequence := c.checkBlock(node.Consequence, s)
+		if node.Alternative == nil {
+			return anyT, nil
+		}
+		if alternative := c.checkBlock(node.Alternative, s); alternative.String() != consequence.String() {
+			return anyT, nil
+		}
+		return consequence, nil
+
+	case *ast.CallExpression:
+		return c.inferCall(node, s), nil
+
+	case *ast.IndexExpression:
+		left := c.infer(node.Left, s)
+		c.infer(node.Index, s)
+		switch left.name {
+		case "string":
+			return named("string"), nil
+		case "array":
+			return left.arg(0), nil
+		case "hash":
+			return left.arg(1), nil
+		}
+		return anyT, nil
+
+	case *ast.SliceExpression:
+		left := c.infer(node.Left, s)
+		c.infer(node.Start, s)
+		c.infer(node.End, s)
+		c.infer(node.Step, s)
+		if left.name == "string" || left.name == "array" {
+			return left, nil
+		}
+		return anyT, nil
+
+	case *ast.MemberExpression:
+		return c.inferMember(node, s)
+
+	case *ast.MatchExpression:
+		c.checkMatchTypes(node, s)
+		return anyT, nil
+
+	case *ast.SpreadExpression:
+		c.infer(node.Value, s)
+		return anyT, nil
+
+	case *ast.YieldExpression:
+		// Yield evaluates to null once the generator is resumed
+		c.infer(node.Value, s)
+		return named("null"), nil
+
+	case *ast.SpawnExpression:
+		// The call is checked as usual, but what it returns is only available by waiting on the task
+		c.infer(node.Call, s)
+		return named("task"), nil
+
+	case *ast.SelectExpression:
+		c.checkSelectTypes(node, s)
+		return anyT, nil
+	}
+
+	return anyT, nil
+}
+
+// The type every one of types has, or nil when they differ or aren't known
+func commonType(types []*typ) *typ {
+	if len(types) == 0 || types[0].isAny() {
+		return nil
+	}
+	for _, t := range types[1:] {
+		if t.String() != types[0].String() {
+			return nil
+		}
+	}
+	return types[0]
+}
+
+// Only reports mixed types the evaluator is certain to reject
+func (c *Checker) inferInfix(node *ast.InfixExpression, s *typeScope) *typ {
+	left := c.infer(node.Left, s)
+
+	if node.Operator == "is" {
+		if ident, ok := node.Right.(*ast.Identifier); ok {
+			c.checkTypeName(ident.Value, ident.Token, s)
+		}
+		return named("bool")
+	}
+
+	right := c.infer(node.Right, s)
+	operator := node.Operator
+	if isCompoundOperator(operator) {
+		operator = strings.TrimSuffix(operator, "=")
+	}
+
+	switch operator {
+	case "==", "!=", "===", "!==":
+		return named("bool")
+	}
+	comparison := operator == "<" || operator == ">" || operator == "<=" || operator == ">="
+
+	switch {
+	case left.isAny() || right.isAny():
+		if comparison {
+			return named("bool")
+		}
+		return anyT
+	case isNumeric(left) && isNumeric(right):
+		switch {
+		case comparison:
+			return named("bool")
+		case left.name == "int" && right.name == "int":
+			return named("int")
+		case left.name == "float" || right.name == "float":
+			return named("float")
+		}
+		return named("number")
+	case c.overloads(left, operator):
+		if comparison {
+			return named("bool")
+		}
+		return anyT
+	case isTemporal(left) || isTemporal(right):
+		if result := temporalResult(left, operator, right); result != nil {
+			return result
+		}
+		if left.name != right.name {
+			c.error(fmt.Sprintf("type mismatch: %s %s %s", left, node.Operator, right), node.Token)
+		}
+		return anyT
+	case left.name != right.name:
+		c.error(fmt.Sprintf("type mismatch: %s %s %s", left, node.Operator, right), node.Token)
+		return anyT
+	case left.name == "string" && operator == "+":
+		return named("string")
+	}
+
+	return anyT
+}
+
+// Type parameters are bound by the arguments of each call, so the result of
+// first<T>(xs: array<T>) -> T is int when xs is an array<int>
+func (c *Checker) inferCall(node *ast.CallExpression, s *typeScope) *typ {
+	_, fn := c.inferFn(node.Function, s)
+
+	args := []*typ{}
+	spread := false
+	for _, arg := range node.Arguments {
+		if _, ok := arg.(*ast.SpreadExpression); ok {
+			spread = true
+		}
+		args = append(args, c.infer(arg, s))
+	}
+
+	if fn == nil {
+		return anyT
+	}
+
+	// Spread arguments could be any number of values of any type
+	bindings := map[string]*typ{}
+	if spread {
+		return substitute(fn.result, bindings)
+	}
+
+	// Bound arguments (a method's receiver) count towards the parameters, but not in messages
+	bound := len(fn.bound)
+	given := append(append([]*typ{}, fn.bound...), args...)
+
+	if len(given) < fn.min || (fn.max >= 0 && len(given) > fn.max) {
+		want := fmt.Sprintf("want=%d", fn.min-bound)
+		switch {
+		case fn.max < 0:
+			want = fmt.Sprintf("want at least %d", fn.min-bound)
+		case fn.min != fn.max:
+			want = fmt.Sprintf("want %d to %d", fn.min-bound, fn.max-bound)
+		}
+		c.error(fmt.Sprintf("wrong number of arguments to %s. got=%d, %s", fn.name, len(args), want), node.Token)
+		return substitute(fn.result, bindings)
+	}
+
+	for i, arg := range given {
+		var param *typ
+		switch {
+		case i < len(fn.params):
+			param = fn.params[i]
+		case fn.variadic && len(fn.params) > 0:
+			param = fn.params[len(fn.params)-1]
+		default:
+			continue
+		}
+
+		if !c.unify(param, arg, bindings) && i >= bound {
+			name := fmt.Sprintf("%d", i-bound+1)
+			if i < len(fn.names) {
+				name = fn.names[i]
+			}
+			c.error(fmt.Sprintf("argument %s of %s must be %s, got %s", name, fn.name, substitute(param, bindings), arg), node.Token)
+		}
+	}
+
+	return substitute(fn.result, bindings)
+}
+
+func (c *Checker) inferMember(node *ast.MemberExpression, s *typeScope) (*typ, *fnType) {
+	name := node.Property.Value
+
+	if module, ok := c.moduleName(node.Object, s); ok {
+		if builtin, ok := modules.Modules[module][name]; ok {
+			return named("fn"), builtinFnType(module+"."+name, builtin.Signature)
+		}
+		if value, ok := modules.Constants[module][name]; ok {
+			return constantType(value), nil
+		}
+		if _, ok := modules.Modules[module+"."+name]; ok {
+			return anyT, nil
+		}
+		c.error(fmt.Sprintf("function not found in module '%s': %s", module, name), node.Property.Token)
+		return anyT, nil
+	}
+
+	typ := c.infer(node.Object, s)
+
+	if enum := c.enums[c.declares(node.Object, s)]; enum != nil {
+		return c.inferEnumMember(enum, node.Property)
+	}
+
+	if record := c.records[typ.name]; record != nil {
+		for _, field := range record.Fields {
+			if field.Name.Value != name {
+				continue
+			}
+			if fieldType, ok := c.fields[typ.name][name]; ok {
+				return fieldType, nil
+			}
+			return anyT, nil
+		}
+		if method, ok := c.methods[typ.name][name]; ok {
+			return named("fn"), methodType(name, method)
+		}
+		c.error(fmt.Sprintf("%s has no field '%s'", typ, name), node.Property.Token)
+		return anyT, nil
+	}
+
+	if enum := c.enums[typ.name]; enum != nil {
+		// Fields depend on the variant, which isn't known, so only name and value have a type
+		for _, variant := range enum.Variants {
+			for _, field := range variant.Fields {
+				if field.Value == name {
+					return anyT, nil
+				}
+			}
+		}
+		switch name {
+		case "name":
+			return named("string"), nil
+		case "value":
+			return named("int"), nil
+		}
+		if method, ok := c.methods[typ.name][name]; ok {
+			return named("fn"), methodType(name, method)
+		}
+		return anyT, nil
+	}
+
+	if methods, ok := c.interfaceMethods(typ.name); ok && c.isInterface(typ.name) {
+		// Only the methods are known, the value could be any type that has them
+		for _, method := range methods {
+			if method.name == name {
+				return named("fn"), &fnType{name: name, result: anyT, min: method.params, max: method.params}
+			}
+		}
+		c.error(fmt.Sprintf("%s has no method '%s'", typ, name), node.Property.Token)
+		return anyT, nil
+	}
+
+	if module, ok := methodModules[typ.name]; ok {
+		builtin, ok := modules.Modules[module][name]
+		if names, subset := methodSubsets[typ.name]; subset && !slices.Contains(names, name) {
+			ok = false
+		}
+		if !ok {
+			c.error(fmt.Sprintf("%s has no member '%s'", typ.name, name), node.Property.Token)
+			return anyT, nil
+		}
+		return named("fn"), methodFnType(name, builtin.Signature, typ)
+	}
+
+	return anyT, nil
+}
+
+func (c *Checker) inferEnumMember(enum *ast.EnumStatement, property *ast.Identifier) (*typ, *fnType) {
+	for _, variant := range enum.Variants {
+		if variant.Name.Value != property.Value {
+			continue
+		}
+		if variant.Fields == nil {
+			return named(enum.Name.Value), nil
+		}
+		constructor := &fnType{name: enum.Name.Value + "." + variant.Name.Value, result: named(enum.Name.Value), min: len(variant.Fields), max: len(variant.Fields)}
+		for _, field := range variant.Fields {
+			constructor.params = append(constructor.params, anyT)
+			constructor.names = append(constructor.names, field.Value)
+		}
+		return named("fn"), constructor
+	}
+
+	switch property.Value {
+	case "variants":
+		return named("array", named(enum.Name.Value)), nil
+	case "from":
+		return named("fn"), &fnType{name: enum.Name.Value + ".from", params: []*typ{anyT}, result: anyT, min: 1, max: 1}
+	}
+
+	c.error(fmt.Sprintf("enum %s has no variant '%s'", enum.Name.Value, property.Value), property.Token)
+	return anyT, nil
+}
+
+// The receiver is passed as the first argument, so it is bound before the call
+// That also binds the type parameters it contains, T is int for the push of an array<int>
+func methodFnType(name string, sig *object.Signature, receiver *typ) *fnType {
+	fn := builtinFnType(name, sig)
+	if fn != nil {
+		fn.bound = []*typ{receiver}
+	}
+	return fn
+}
+
+// The dotted name of the module an expression refers to, like math or math.stats
+// Variables hide modules of the same name, unless they were imported from it
+func (c *Checker) moduleName(exp ast.Expression, s *typeScope) (string, bool) {
+	switch node := exp.(type) {
+	case *ast.Identifier:
+		if v := s.lookup(node.Value); v != nil && !v.imported {
+			return "", false
+		}
+		_, ok := modules.Modules[node.Value]
+		return node.Value, ok
+	case *ast.MemberExpression:
+		outer, ok := c.moduleName(node.Object, s)
+		if !ok {
+			return "", false
+		}
+		name := outer + "." + node.Property.Value
+		_, ok = modules.Modules[name]
+		return name, ok
+	}
+	return "", false
+}
+
+// Parameters are declared with their annotated types, anything else is `any`
+// The result is the annotated one, or inferred when every value the function returns has the same type
+// Methods pass the type they're attached to as the receiver, which their first parameter is unless annotated
+func (c *Checker) checkFunction(node *ast.FunctionLiteral, s *typeScope, receiver *typ) *fnType {
+	scope := &fnScope{}
+	inner := newTypeScope(s, scope)
+	for _, param := range node.TypeParameters {
+		inner.typeParams[param.Value] = true
+	}
+	if node.ReturnType != nil {
+		scope.result = c.resolveType(node.ReturnType, inner)
+	}
+	// A generator returns the iterator over what it yields, so that's what has to fit the annotation
+	if node.Generator {
+		if scope.result != nil && !c.assignable(named("iterator"), scope.result) {
+			c.error(fmt.Sprintf("return value must be %s, got iterator", scope.result), node.Token)
+		}
+		scope.result = nil
+	}
+
+	fn := &fnType{name: "function", min: len(node.Parameters), max: -1}
+	for i, param := range node.Parameters {
+		v := &variable{typ: anyT}
+		if i < len(node.ParameterTypes) && node.ParameterTypes[i] != nil {
+			v.typ = c.resolveType(node.ParameterTypes[i], inner)
+			v.annotated = true
+		} else if i == 0 && receiver != nil {
+			v.typ = receiver
+		}
+		inner.vars[param.Value] = v
+		fn.params = append(fn.params, v.typ)
+		fn.names = append(fn.names, param.Value)
+	}
+
+	last := c.checkBlock(node.Body, inner)
+
+	// The last expression is returned when the function doesn't return early
+	stmts := node.Body.Statements
+	if len(stmts) > 0 {
+		if exp, ok := stmts[len(stmts)-1].(*ast.ExpressionStatement); ok {
+			scope.returns = append(scope.returns, last)
+			if scope.result != nil && !c.assignable(last, scope.result) {
+				c.error(fmt.Sprintf("return value must be %s, got %s", scope.result, last), exp.Token)
+			}
+		}
+	}
+
+	fn.result = scope.result
+	if node.Generator {
+		fn.result = named("iterator")
+	}
+	if fn.result == nil {
+		fn.result = anyT
+		if common := commonType(scope.returns); common != nil {
+			fn.result = common
+		}
+	}
+
+	return fn
+}
+
+// Each arm gets its own scope, a plain binding has the type of the subject
+func (c *Checker) checkMatchTypes(node *ast.MatchExpression, s *typeScope) {
+	subject := c.infer(node.Subject, s)
+
+	for _, arm := range node.Arms {
+		inner := newTypeScope(s, s.fn)
+		for _, name := range ast.PatternNames(arm.Pattern) {
+			inner.vars[name.Value] = &variable{typ: anyT}
+		}
+
+		switch pattern := arm.Pattern.(type) {
+		case *ast.BindingPattern:
+			inner.vars[pattern.Name.Value].typ = subject
+		case *ast.TypePattern:
+			typ := c.resolveType(pattern.TypeName, s)
+			if binding, ok := pattern.Pattern.(*ast.BindingPattern); ok {
+				inner.vars[binding.Name.Value].typ = typ
+			}
+		}
+
+		c.infer(arm.Guard, inner)
+		c.checkBlock(arm.Body, inner)
+	}
+}
+
+// Each case gets its own scope, the name a received value is bound to could be anything sent on the channel
+func (c *Checker) checkSelectTypes(node *ast.SelectExpression, s *typeScope) {
+	for _, sc := range node.Cases {
+		inner := newTypeScope(s, s.fn)
+		if sc.Operation != nil {
+			c.infer(sc.Operation, s)
+		}
+		if sc.Name != nil {
+			inner.vars[sc.Name.Value] = &variable{typ: anyT}
+		}
+		c.checkBlock(sc.Body, inner)
+	}
+}
+
+// Whether a value of type actual can be used where expected is required
+// `any` fits everything, and `number` fits int and float (and the other way around, since it may be either)
+// Type arguments have to fit as well, an array<int> isn't an array<string>, but both are an array
+// Record types and enums fit the interfaces their methods satisfy
+func (c *Checker) assignable(actual, expected *typ) bool {
+	switch {
+	case actual.isAny(), expected.isAny():
+		return true
+	case expected.name == "number":
+		return isNumeric(actual)
+	case actual.name == "number":
+		return expected.name == "int" || expected.name == "float"
+	case actual.name != expected.name && c.isInterface(expected.name):
+		return c.satisfies(actual, expected.name)
+	case actual.name != expected.name:
+		return false
+	}
+
+	for i, arg := range expected.args {
+		if !c.assignable(actual.arg(i), arg) {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches an argument against a parameter, binding the type parameters the parameter contains
+// The first known type a type parameter is bound to sticks, later arguments have to fit it
+func (c *Checker) unify(param, arg *typ, bindings map[string]*typ) bool {
+	if param.param {
+		bound, ok := bindings[param.name]
+		if !ok || bound.isAny() {
+			bindings[param.name] = arg
+			return true
+		}
+		return c.assignable(arg, bound)
+	}
+	if arg.isAny() || param.name != arg.name || len(param.args) == 0 {
+		return c.assignable(arg, param)
+	}
+
+	ok := true
+	for i, p := range param.args {
+		if !c.unify(p, arg.arg(i), bindings) {
+			ok = false
+		}
+	}
+	return ok
+}
+
+// Replaces the type parameters in t with what they're bound to, or `any` when they aren't bound
+func substitute(t *typ, bindings map[string]*typ) *typ {
+	if t.param {
+		if bound, ok := bindings[t.name]; ok {
+			return bound
+		}
+		return anyT
+	}
+	if len(t.args) == 0 {
+		return t
+	}
+
+	args := []*typ{}
+	for _, arg := range t.args {
+		args = append(args, substitute(arg, bindings))
+	}
+	return named(t.name, args...)
+}
+
+func isNumeric(t *typ) bool {
+	return t.name == "int" || t.name == "float" || t.name == "number"
+}
+
+func isTemporal(t *typ) bool {
+	return t.name == "datetime" || t.name == "duration"
+}
+
+// The result of arithmetic between datetimes and durations, the same combinations the evaluator allows
+// Nil when the operator can't be used on them
+func temporalResult(left *typ, operator string, right *typ) *typ {
+	comparison := operator == "<" || operator == ">"
+
+	switch {
+	case left.name == "datetime" && right.name == "duration" && (operator == "+" || operator == "-"):
+		return named("datetime")
+	case left.name == "duration" && right.name == "datetime" && operator == "+":
+		return named("datetime")
+	case left.name == "datetime" && right.name == "datetime" && operator == "-":
+		return named("duration")
+	case left.name == right.name && comparison:
+		return named("bool")
+	case left.name == "duration" && right.name == "duration":
+		switch operator {
+		case "+", "-", "%":
+			return named("duration")
+		case "/":
+			return named("float")
+		}
+	case left.name == "duration" && isNumeric(right) && (operator == "*" || operator == "/"):
+		return named("duration")
+	case isNumeric(left) && right.name == "duration" && operator == "*":
+		return named("duration")
+	}
+	return nil
+}
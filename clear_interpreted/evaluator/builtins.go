@@ -0,0 +1,111 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/ajtroup1/clear/object"
+)
+
+// Functions available everywhere without importing a module
+// Variables and modules with the same name take priority
+var builtins = map[string]*object.Builtin{
+	// Makes an array or hash (and everything inside it) immutable, returning the same value
+	// Other values are already immutable, so they are returned unchanged
+	"freeze": {
+		Signature: &object.Signature{Params: []string{"any"}, Return: "any"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+			return object.Freeze(args[0])
+		},
+	},
+
+	"isFrozen": {
+		Signature: &object.Signature{Params: []string{"any"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+			return nativeBoolToBooleanObject(object.IsFrozen(args[0]))
+		},
+	},
+
+	// Makes a channel for passing values between tasks, holding up to the capacity before senders wait
+	// Without a capacity every send waits for a receiver
+	"chan": {
+		Signature: &object.Signature{Params: []string{"int"}, Return: "channel", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=0 or 1", len(args))}
+			}
+			capacity := int64(0)
+			if len(args) == 1 {
+				n, ok := args[0].(*object.Integer)
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("capacity must be INTEGER, got %s", args[0].Type())}
+				}
+				if n.Value < 0 {
+					return &object.Error{Message: fmt.Sprintf("capacity can't be negative, got %d", n.Value)}
+				}
+				capacity = n.Value
+			}
+			return object.NewChannel(int(capacity))
+		},
+	},
+
+	// Waits until the value is received or buffered
+	"send": {
+		Signature: &object.Signature{Params: []string{"channel", "any"}, Return: "null"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
+			}
+			ch, ok := args[0].(*object.Channel)
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("first argument must be CHANNEL, got %s", args[0].Type())}
+			}
+			if !ch.Send(args[1]) {
+				return &object.Error{Message: "send on closed channel"}
+			}
+			return NULL
+		},
+	},
+
+	// Waits for the next value, giving null once the channel is closed and empty
+	"recv": {
+		Signature: &object.Signature{Params: []string{"channel"}, Return: "any"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+			ch, ok := args[0].(*object.Channel)
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("argument must be CHANNEL, got %s", args[0].Type())}
+			}
+			value, ok := ch.Recv()
+			if !ok {
+				return NULL
+			}
+			return value
+		},
+	},
+
+	// Stops the channel taking values, receivers still get the ones already sent
+	"close": {
+		Signature: &object.Signature{Params: []string{"channel"}, Return: "null"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+			ch, ok := args[0].(*object.Channel)
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("argument must be CHANNEL, got %s", args[0].Type())}
+			}
+			if !ch.Close() {
+				return &object.Error{Message: "close of closed channel"}
+			}
+			return NULL
+		},
+	},
+}
@@ -0,0 +1,119 @@
+package evaluator
+
+import (
+	"reflect"
+
+	"github.com/ajtroup1/clear/ast"
+	"github.com/ajtroup1/clear/object"
+)
+
+// Evaluates the function and its arguments right away, then runs the call on a goroutine
+// The task it returns gives the result to whoever waits on it. Tasks still running when the program ends are stopped
+// Values aren't copied for the task, so arrays and hashes it shares with the program lock themselves while they change
+func evalSpawnExpression(node *ast.SpawnExpression, env *object.Environment) object.Object {
+	function := Eval(node.Call.Function, env)
+	if isError(function) {
+		return function
+	}
+
+	args := evalExpressions(node.Call.Arguments, env)
+	if len(args) == 1 && isError(args[0]) {
+		return args[0]
+	}
+
+	task := object.NewTask()
+	task.Position = object.Position{Line: node.Token.Line, Col: node.Token.Col}
+	go func() {
+		// A panic can't be recovered by the program's goroutine, so the task reports it as its result instead
+		defer func() {
+			if r := recover(); r != nil {
+				task.Finish(newError("internal error: %v", node.Token.Line, node.Token.Col, r))
+			}
+		}()
+
+		result := applyFunction(function, args)
+		// Builtins don't know where they were called from, so their errors are placed at the call
+		if err, ok := result.(*object.Error); ok && err.Line() == 0 {
+			result = newError("%s", node.Call.Token.Line, node.Call.Token.Col, err.Message)
+		}
+		if result == nil {
+			result = NULL
+		}
+		task.Finish(result)
+	}()
+
+	return task
+}
+
+// Channels are evaluated first, then the select waits for whichever case is ready first, picking at random when several are
+// Receiving from a closed channel is ready straight away and gives null, sending to one is an error
+func evalSelectExpression(node *ast.SelectExpression, env *object.Environment) object.Object {
+	cases := []reflect.SelectCase{}
+	owners := []*ast.SelectCase{}   // the case each reflect case belongs to
+	channels := []*object.Channel{} // the channel each reflect case waits on, nil for the default
+	closes := []bool{}              // whether the reflect case is the channel being closed
+
+	for _, c := range node.Cases {
+		if c.Operation == nil {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+			owners, closes = append(owners, c), append(closes, false)
+			channels = append(channels, nil)
+			continue
+		}
+
+		target := Eval(c.Operation.Arguments[0], env)
+		if isError(target) {
+			return target
+		}
+		ch, ok := target.(*object.Channel)
+		if !ok {
+			return newError("select cases need a CHANNEL, got %s", c.Operation.Token.Line, c.Operation.Token.Col, target.Type())
+		}
+		values, closed := ch.Chans()
+
+		if c.Receives() {
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(values)})
+		} else {
+			// A buffered channel with room would otherwise take the value even though it's closed
+			if ch.IsClosed() {
+				return newError("send on closed channel", c.Operation.Token.Line, c.Operation.Token.Col)
+			}
+			value := Eval(c.Operation.Arguments[1], env)
+			if isError(value) {
+				return value
+			}
+			cases = append(cases, reflect.SelectCase{Dir: reflect.SelectSend, Chan: reflect.ValueOf(values), Send: reflect.ValueOf(&value).Elem()})
+		}
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(closed)})
+		owners, closes = append(owners, c, c), append(closes, false, true)
+		channels = append(channels, ch, ch)
+	}
+
+	chosen, received, _ := reflect.Select(cases)
+	c := owners[chosen]
+	inner := object.NewArmEnvironment(env)
+
+	if c.Operation != nil && c.Receives() {
+		var value object.Object = NULL
+		if closes[chosen] {
+			// Values sent before the channel was closed are still received
+			if drained, ok := channels[chosen].Drain(); ok {
+				value = drained
+			}
+		} else if obj, ok := received.Interface().(object.Object); ok {
+			value = obj
+		}
+		if c.Name != nil {
+			inner.Set(c.Name.Value, value)
+		}
+	}
+	if c.Operation != nil && !c.Receives() && closes[chosen] {
+		return newError("send on closed channel", c.Operation.Token.Line, c.Operation.Token.Col)
+	}
+
+	result := Eval(c.Body, inner)
+	if result == nil {
+		return NULL
+	}
+	return result
+}
@@ -0,0 +1,125 @@
+package evaluator
+
+import (
+	"time"
+
+	"github.com/ajtroup1/clear/object"
+)
+
+// Arithmetic and ordering between DateTimes and Durations, and scaling Durations by numbers
+//   - DateTime ± Duration and Duration + DateTime give a DateTime
+//   - DateTime - DateTime gives the Duration between them
+//   - Duration ± Duration and Duration % Duration give a Duration
+//   - Duration * number, number * Duration and Duration / number give a Duration
+//   - Duration / Duration gives how many times one fits in the other, as a float
+//
+// The second return value is false when neither operand is a DateTime or Duration,
+// or the operator is left to the rest of evalInfixExpression (like == and !=)
+func evalTimeInfixExpression(operator string, left, right object.Object, literal string, pos object.Position, env *object.Environment) (object.Object, bool) {
+	if !isTemporal(left) && !isTemporal(right) {
+		return nil, false
+	}
+
+	base := operator
+	if isCompoundOperator(operator) {
+		base = operator[:1]
+	}
+
+	var result object.Object
+	switch l := left.(type) {
+	case *object.DateTime:
+		switch r := right.(type) {
+		case *object.Duration:
+			switch base {
+			case "+":
+				result = &object.DateTime{Value: l.Value.Add(r.Value)}
+			case "-":
+				result = &object.DateTime{Value: l.Value.Add(-r.Value)}
+			}
+		case *object.DateTime:
+			switch base {
+			case "-":
+				result = &object.Duration{Value: l.Value.Sub(r.Value)}
+			case "<":
+				result = nativeBoolToBooleanObject(l.Value.Before(r.Value))
+			case ">":
+				result = nativeBoolToBooleanObject(l.Value.After(r.Value))
+			}
+		}
+
+	case *object.Duration:
+		switch r := right.(type) {
+		case *object.Duration:
+			switch base {
+			case "+":
+				result = &object.Duration{Value: l.Value + r.Value}
+			case "-":
+				result = &object.Duration{Value: l.Value - r.Value}
+			case "/", "%":
+				if r.Value == 0 {
+					return newError("division by zero: %s %s %s", pos.Line, pos.Col, l.Inspect(), base, r.Inspect()), true
+				}
+				if base == "/" {
+					result = &object.Float{Value: float64(l.Value) / float64(r.Value)}
+				} else {
+					result = &object.Duration{Value: l.Value % r.Value}
+				}
+			case "<":
+				result = nativeBoolToBooleanObject(l.Value < r.Value)
+			case ">":
+				result = nativeBoolToBooleanObject(l.Value > r.Value)
+			}
+		case *object.DateTime:
+			if base == "+" {
+				result = &object.DateTime{Value: r.Value.Add(l.Value)}
+			}
+		case *object.Integer, *object.Float:
+			factor := toFloat(r)
+			switch base {
+			case "*":
+				result = &object.Duration{Value: time.Duration(float64(l.Value) * factor)}
+			case "/":
+				if factor == 0 {
+					return newError("division by zero: %s %s %s", pos.Line, pos.Col, l.Inspect(), base, r.Inspect()), true
+				}
+				result = &object.Duration{Value: time.Duration(float64(l.Value) / factor)}
+			}
+		}
+
+	case *object.Integer, *object.Float:
+		if r, ok := right.(*object.Duration); ok && base == "*" {
+			result = &object.Duration{Value: time.Duration(toFloat(l) * float64(r.Value))}
+		}
+	}
+
+	// Everything else, including equality, is reported or handled like for any other type
+	if result == nil {
+		return nil, false
+	}
+
+	if isCompoundOperator(operator) {
+		if env.IsConst(literal) {
+			return newError("cannot assign to constant: %s", pos.Line, pos.Col, literal), true
+		}
+		env.Assign(literal, result)
+	}
+	return result, true
+}
+
+func isTemporal(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.DateTime, *object.Duration:
+		return true
+	}
+	return false
+}
+
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.Float:
+		return obj.Value
+	}
+	return 0
+}
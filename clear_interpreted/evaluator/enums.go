@@ -0,0 +1,176 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/ajtroup1/clear/ast"
+	"github.com/ajtroup1/clear/object"
+)
+
+// Declares an enum, binding it like any other value so its variants can be accessed as members
+// Variants count up from 0 (or from the last explicit value), and every value must be unique
+func evalEnumStatement(node *ast.EnumStatement, env *object.Environment) object.Object {
+	enum := &object.Enum{
+		Name:     node.Name.Value,
+		Position: object.Position{Line: node.Token.Line, Col: node.Token.Col},
+	}
+
+	next := int64(0)
+	for _, decl := range node.Variants {
+		line, col := decl.Name.Token.Line, decl.Name.Token.Col
+		if _, exists := enum.Variant(decl.Name.Value); exists {
+			return newError("duplicate variant %s in enum %s", line, col, decl.Name.Value, enum.Name)
+		}
+
+		if decl.Value != nil {
+			value := Eval(decl.Value, env)
+			if isError(value) {
+				return value
+			}
+			integer, ok := value.(*object.Integer)
+			if !ok {
+				return newError("value of variant %s must be INTEGER, got %s", line, col, decl.Name.Value, value.Type())
+			}
+			next = integer.Value
+		}
+		if other, exists := enum.VariantOf(next); exists {
+			return newError("variants %s and %s of enum %s have the same value: %d", line, col, other.Name, decl.Name.Value, enum.Name, next)
+		}
+
+		variant := &object.EnumVariant{
+			Enum:     enum,
+			Name:     decl.Name.Value,
+			Value:    next,
+			Position: object.Position{Line: line, Col: col},
+		}
+		if decl.Fields == nil {
+			variant.Unit = &object.EnumValue{Variant: variant, Position: variant.Position}
+		} else {
+			for _, field := range decl.Fields {
+				variant.Fields = append(variant.Fields, field.Value)
+			}
+		}
+
+		enum.Variants = append(enum.Variants, variant)
+		next++
+	}
+
+	env.Set(node.Name.Value, enum)
+	return nil
+}
+
+// Calling a variant with fields creates a value of it, every field is required
+func constructEnumValue(variant *object.EnumVariant, args []object.Object) object.Object {
+	if len(args) != len(variant.Fields) {
+		return newError("wrong number of arguments to %s.%s. got=%d, want=%d", 0, 0, variant.Enum.Name, variant.Name, len(args), len(variant.Fields))
+	}
+
+	values := make([]object.Object, len(args))
+	copy(values, args)
+	return &object.EnumValue{Variant: variant, Values: values}
+}
+
+// Members of an enum are its variants, along with
+//   - variants: every variant in declaration order
+//   - from(x): the variant with the integer value or name x, or null if there is none
+//
+// Variants take priority, so a variant named `from` hides the conversion
+func evalEnumMember(enum *object.Enum, name string, line, col int) object.Object {
+	if variant, ok := enum.Variant(name); ok {
+		return variant.Member()
+	}
+
+	switch name {
+	case "variants":
+		elements := []object.Object{}
+		for _, variant := range enum.Variants {
+			elements = append(elements, variant.Member())
+		}
+		return &object.Array{Elements: elements}
+
+	case "from":
+		return &object.Builtin{
+			Fn: func(args ...object.Object) object.Object {
+				if len(args) != 1 {
+					return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+				}
+
+				var variant *object.EnumVariant
+				var ok bool
+				switch arg := args[0].(type) {
+				case *object.Integer:
+					variant, ok = enum.VariantOf(arg.Value)
+				case *object.String:
+					variant, ok = enum.Variant(arg.Value)
+				default:
+					return &object.Error{Message: fmt.Sprintf("argument to `from` must be INTEGER or STRING, got %s", args[0].Type())}
+				}
+				if !ok {
+					return NULL
+				}
+				return variant.Member()
+			},
+		}
+	}
+
+	return newError("enum %s has no variant '%s'", line, col, enum.Name, name)
+}
+
+// Members of an enum value are its fields and the methods of its enum, along with
+//   - name: the name of its variant
+//   - value: the integer value of its variant
+//
+// Fields take priority over methods, the same way hash keys do, and methods over name and value
+func evalEnumValueMember(value *object.EnumValue, name string, line, col int) object.Object {
+	if field, ok := value.Get(name); ok {
+		return field
+	}
+	if method, ok := lookupUserMethod(value, name); ok {
+		return method
+	}
+
+	switch name {
+	case "name":
+		return &object.String{Value: value.Variant.Name}
+	case "value":
+		return &object.Integer{Value: value.Variant.Value}
+	}
+
+	return newError("%s.%s has no field '%s'", line, col, value.Variant.Enum.Name, value.Variant.Name, name)
+}
+
+// Matches Color.Red or Shape.Circle(r)
+// Naming an enum or variant that doesn't exist is an error rather than a failed match,
+// since the arm could never match anything
+func matchVariantPattern(pattern *ast.VariantPattern, value object.Object, env *object.Environment) (bool, *object.Error) {
+	line, col := pattern.Token.Line, pattern.Token.Col
+
+	obj, ok := env.Get(pattern.Enum.Value)
+	if !ok {
+		return false, newError("identifier not found: %s", line, col, pattern.Enum.Value)
+	}
+	enum, ok := obj.(*object.Enum)
+	if !ok {
+		return false, newError("%s is not an enum, got %s", line, col, pattern.Enum.Value, obj.Type())
+	}
+	variant, ok := enum.Variant(pattern.Variant.Value)
+	if !ok {
+		return false, newError("enum %s has no variant '%s'", pattern.Variant.Token.Line, pattern.Variant.Token.Col, enum.Name, pattern.Variant.Value)
+	}
+	if pattern.Fields != nil && len(pattern.Fields) != len(variant.Fields) {
+		return false, newError("variant %s.%s has %d fields, pattern has %d", line, col, enum.Name, variant.Name, len(variant.Fields), len(pattern.Fields))
+	}
+
+	enumValue, ok := value.(*object.EnumValue)
+	if !ok || enumValue.Variant != variant {
+		return false, nil
+	}
+
+	for i, field := range pattern.Fields {
+		if matched, err := matchPattern(field, enumValue.Values[i], env); !matched || err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
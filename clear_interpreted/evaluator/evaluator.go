@@ -2,17 +2,27 @@ package evaluator
 
 import (
 	"fmt"
-	"strings"
+	"math"
+	"slices"
 
 	"github.com/ajtroup1/clear/ast"
 	"github.com/ajtroup1/clear/logger"
 	"github.com/ajtroup1/clear/object"
+	"github.com/ajtroup1/clear/token"
 )
 
 var (
 	Logger *logger.Logger
 	Debug  bool
 	Lines  []string
+
+	// When enabled, integer arithmetic that overflows int64 results in a
+	// runtime error instead of silently wrapping around
+	CheckedArithmetic bool
+
+	// When enabled, indexing or slicing out of range results in a
+	// runtime error instead of null (or a clamped slice)
+	StrictIndexing bool
 )
 
 func Init(l *logger.Logger, debug bool, lines []string) {
@@ -27,9 +37,9 @@ func Init(l *logger.Logger, debug bool, lines []string) {
 
 // Define a const to easily access object types throughout the evaluator
 var (
-	NULL  = &object.Null{}
-	TRUE  = &object.Boolean{Value: true}
-	FALSE = &object.Boolean{Value: false}
+	NULL  = object.NULL
+	TRUE  = object.TRUE
+	FALSE = object.FALSE
 )
 
 // Core evaluation function
@@ -63,34 +73,63 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(val) {
 			return val
 		}
-		env.Set(node.Name.Value, val)
+		if node.Pattern != nil {
+			return evalDestructuring(node, val, env)
+		}
+		if env.IsLocalConst(node.Name.Value) {
+			return newError("cannot redeclare constant: %s", node.Name.Token.Line, node.Name.Token.Col, node.Name.Value)
+		}
+		if node.TypeName != nil {
+			if err := checkType(val, node.TypeName, env, "value of "+node.Name.Value); err != nil {
+				return err
+			}
+		}
+		if node.Constant {
+			env.SetConst(node.Name.Value, val)
+		} else {
+			env.Set(node.Name.Value, val)
+		}
 
 	case *ast.AssignStatement:
+		if env.IsConst(node.Name.Value) {
+			return newError("cannot assign to constant: %s", node.Name.Token.Line, node.Name.Token.Col, node.Name.Value)
+		}
+
 		val := Eval(node.Value, env)
 		if isError(val) {
 			return val
 		}
 
-		env.Set(node.Name.Value, val)
+		env.Assign(node.Name.Value, val)
 		return val
 
 	case *ast.WhileStatement:
-		condition := Eval(node.Condition, env)
-		if isError(condition) {
-			return condition
-		}
-
 		return evalWhileStatement(node, env)
 
 	case *ast.ForStatement:
 		return evalForStatement(node, env)
 
+	case *ast.ForInStatement:
+		return evalForInStatement(node, env)
+
 	case *ast.ContinueStatement:
 		return &object.Continue{Position: object.Position{Line: node.Token.Line, Col: node.Token.Col}}
 
 	case *ast.BreakStatement:
 		return &object.Break{Position: object.Position{Line: node.Token.Line, Col: node.Token.Col}}
 
+	case *ast.TypeStatement:
+		return evalTypeStatement(node, env)
+
+	case *ast.EnumStatement:
+		return evalEnumStatement(node, env)
+
+	case *ast.InterfaceStatement:
+		return evalInterfaceStatement(node, env)
+
+	case *ast.ImplStatement:
+		return evalImplStatement(node, env)
+
 	// Eval Expressions
 	case *ast.IntegerLiteral:
 		return &object.Integer{Value: node.Value, Position: object.Position{Line: node.Token.Line, Col: node.Token.Col}}
@@ -109,9 +148,13 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(right) {
 			return right
 		}
-		return evalPrefixExpression(node.Operator, right)
+		return evalPrefixExpression(node.Operator, right, object.Position{Line: node.Token.Line, Col: node.Token.Col})
 
 	case *ast.InfixExpression:
+		if node.Operator == "is" {
+			return evalIsExpression(node, env)
+		}
+
 		left := Eval(node.Left, env)
 		if isError(left) {
 			return left
@@ -122,7 +165,8 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return right
 		}
 
-		return evalInfixExpression(node.Operator, left, right, node.Left.TokenLiteral(), env)
+		pos := object.Position{Line: node.Token.Line, Col: node.Token.Col}
+		return evalInfixExpression(node.Operator, left, right, node.Left.TokenLiteral(), pos, env)
 
 	case *ast.PostfixExpression:
 		left := Eval(node.Left, env)
@@ -130,7 +174,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return left
 		}
 
-		return evalPostfixExpression(node.Operator, left)
+		return evalPostfixExpression(node.Operator, left, object.Position{Line: node.Token.Line, Col: node.Token.Col})
 
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
@@ -139,9 +183,16 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		return evalIdentifier(node, env)
 
 	case *ast.FunctionLiteral:
-		params := node.Parameters
-		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body, Position: object.Position{Line: node.Token.Line, Col: node.Token.Col}}
+		return &object.Function{
+			TypeParameters: node.TypeParameters,
+			Parameters:     node.Parameters,
+			ParameterTypes: node.ParameterTypes,
+			ReturnType:     node.ReturnType,
+			Env:            env,
+			Body:           node.Body,
+			Generator:      node.Generator,
+			Position:       object.Position{Line: node.Token.Line, Col: node.Token.Col},
+		}
 
 	case *ast.CallExpression:
 		function := Eval(node.Function, env)
@@ -154,7 +205,12 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return args[0]
 		}
 
-		return applyFunction(function, args)
+		result := applyFunction(function, args)
+		// Builtins don't know where they were called from, so their errors are placed at the call
+		if err, ok := result.(*object.Error); ok && err.Line() == 0 {
+			return newError("%s", node.Token.Line, node.Token.Col, err.Message)
+		}
+		return result
 
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
@@ -166,6 +222,9 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 	case *ast.HashLiteral:
 		return evalHashLiteral(node, env)
 
+	case *ast.RangeExpression:
+		return evalRangeExpression(node, env)
+
 	case *ast.IndexExpression:
 		left := Eval(node.Left, env)
 		if isError(left) {
@@ -175,89 +234,456 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if isError(index) {
 			return index
 		}
-		return evalIndexExpression(left, index)
+		return evalIndexExpression(left, index, object.Position{Line: node.Token.Line, Col: node.Token.Col})
+
+	case *ast.SliceExpression:
+		return evalSliceExpression(node, env)
+
+	case *ast.MemberExpression:
+		return evalMemberExpression(node, env)
+
+	case *ast.MatchExpression:
+		return evalMatchExpression(node, env)
+
+	case *ast.YieldExpression:
+		return evalYieldExpression(node, env)
+
+	case *ast.SpawnExpression:
+		return evalSpawnExpression(node, env)
+
+	case *ast.SelectExpression:
+		return evalSelectExpression(node, env)
+
+	case *ast.SpreadExpression:
+		return newError("spread (...) is only allowed in array literals, hash literals and call arguments", node.Token.Line, node.Token.Col)
 	}
 
 	return nil
 }
 
-func evalWhileStatement(stmt *ast.WhileStatement, env *object.Environment) object.Object {
-	var result object.Object
+// Decides what a loop does after evaluating its body once
+//   - Errors and return values stop the loop and are passed up as its result
+//   - Break stops the loop
+//   - Continue, or any other result, moves on to the next iteration
+func loopControl(result object.Object) (bool, object.Object) {
+	if result == nil {
+		return false, nil
+	}
+
+	switch result.Type() {
+	case object.ERROR_OBJ, object.RETURN_VALUE_OBJ:
+		return true, result
+	case object.BREAK_OBJ:
+		return true, NULL
+	default:
+		return false, nil
+	}
+}
 
-	for isTruthy(Eval(stmt.Condition, env)) {
-		result = evalBlockStatement(stmt.Body, env)
-		if result.Type() == object.BREAK_OBJ {
+func evalWhileStatement(stmt *ast.WhileStatement, env *object.Environment) object.Object {
+	for {
+		condition := Eval(stmt.Condition, env)
+		if isError(condition) {
+			return condition
+		}
+		if !object.IsTruthy(condition) {
 			break
 		}
+
+		if done, result := loopControl(evalBlockStatement(stmt.Body, env)); done {
+			return result
+		}
 	}
 
-	return result
+	return NULL
 }
 
 func evalForStatement(stmt *ast.ForStatement, env *object.Environment) object.Object {
-	var result object.Object
-
 	if stmt.Init != nil {
-		result = Eval(stmt.Init, env)
+		result := Eval(stmt.Init, env)
 		if isError(result) {
 			return result
 		}
 	}
 
-	for isTruthy(Eval(stmt.Condition, env)) {
-		result = evalBlockStatement(stmt.Body, env)
-		if isError(result) {
-			return result
+	for {
+		condition := Eval(stmt.Condition, env)
+		if isError(condition) {
+			return condition
 		}
-
-		if result.Type() == object.BREAK_OBJ {
+		if !object.IsTruthy(condition) {
 			break
 		}
-		if result.Type() == object.CONTINUE_OBJ {
-			continue
+
+		if done, result := loopControl(evalBlockStatement(stmt.Body, env)); done {
+			return result
 		}
 
 		if stmt.Post != nil {
-			result = Eval(stmt.Post, env)
+			result := Eval(stmt.Post, env)
 			if isError(result) {
 				return result
 			}
+
+			// Postfix operators don't assign on their own, so `i++` is applied here
+			if postfix, ok := stmt.Post.(*ast.PostfixExpression); ok {
+				if env.IsConst(postfix.Left.TokenLiteral()) {
+					return newError("cannot assign to constant: %s", postfix.Token.Line, postfix.Token.Col, postfix.Left.TokenLiteral())
+				}
+				env.Assign(postfix.Left.TokenLiteral(), result)
+			}
 		}
+	}
 
-		env.Set(stmt.Post.TokenLiteral(), result)
+	return NULL
+}
+
+// Loop variables are bound in the current environment, the same as the
+// initializer of a C-style for loop, so the body can assign to outer variables
+func evalForInStatement(stmt *ast.ForInStatement, env *object.Environment) object.Object {
+	iterable := Eval(stmt.Iterable, env)
+	if isError(iterable) {
+		return iterable
+	}
+
+	// With a single loop variable, hashes bind their keys while
+	// everything else binds its elements
+	_, isHash := iterable.(*object.Hash)
+
+	var result object.Object = NULL
+	err := iterate(iterable, stmt.Token, func(key, value object.Object) bool {
+		switch {
+		case stmt.Key != nil:
+			env.Set(stmt.Key.Value, key)
+			env.Set(stmt.Value.Value, value)
+		case isHash:
+			env.Set(stmt.Value.Value, key)
+		default:
+			env.Set(stmt.Value.Value, value)
+		}
+
+		done, value := loopControl(evalBlockStatement(stmt.Body, env))
+		if done {
+			result = value
+		}
+		return !done
+	})
+	if err != nil {
+		return err
 	}
 
 	return result
 }
 
-func evalIndexExpression(left, index object.Object) object.Object {
+// Whether iterate can go over the value, which is also true of values implementing Iterable
+func isIterable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Array, *object.Hash, *object.String, *object.Range, *object.Enum, *object.Iterator, *object.Channel:
+		return true
+	}
+	return object.MethodsOf(obj)["iter"] != nil
+}
+
+// Calls fn with every key / value pair of an iterable object until fn returns false
+//   - Arrays and strings yield (index, element)
+//   - Hashes yield (key, value) in insertion order
+//   - Ranges yield (index, integer)
+//   - Enums yield (index, variant) in declaration order
+//   - Iterators yield (index, value), asking for each value as the loop gets to it
+//   - Channels yield (index, value), receiving until the channel is closed
+func iterate(iterable object.Object, tok token.Token, fn func(key, value object.Object) bool) *object.Error {
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		// Iterate over a snapshot so pushing inside the loop can't make it endless
+		elements := iterable.Items()
+		for i, el := range elements {
+			if !fn(&object.Integer{Value: int64(i)}, el) {
+				break
+			}
+		}
+	case *object.Hash:
+		for _, pair := range iterable.OrderedPairs() {
+			if !fn(pair.Key, pair.Value) {
+				break
+			}
+		}
+	case *object.String:
+		i := 0
+		for _, ch := range iterable.Value {
+			if !fn(&object.Integer{Value: int64(i)}, &object.String{Value: string(ch)}) {
+				break
+			}
+			i++
+		}
+	case *object.Range:
+		var i int64
+		iterable.Each(func(n int64) bool {
+			keepGoing := fn(&object.Integer{Value: i}, &object.Integer{Value: n})
+			i++
+			return keepGoing
+		})
+	case *object.Enum:
+		for i, variant := range iterable.Variants {
+			if !fn(&object.Integer{Value: int64(i)}, variant.Member()) {
+				break
+			}
+		}
+	case *object.Iterator:
+		// Values are only produced as the loop asks for them, so iterators can be endless
+		for i := int64(0); ; i++ {
+			value := iterable.Next()
+			if value == nil {
+				break
+			}
+			if err, isErr := value.(*object.Error); isErr {
+				return err
+			}
+			if !fn(&object.Integer{Value: i}, value) {
+				break
+			}
+		}
+	case *object.Channel:
+		for i := int64(0); ; i++ {
+			value, ok := iterable.Recv()
+			if !ok || !fn(&object.Integer{Value: i}, value) {
+				break
+			}
+		}
+	default:
+		// Values implementing Iterable are iterated over through what their iter method returns
+		if result, ok := callMethod(iterable, "iter"); ok {
+			if err, isErr := result.(*object.Error); isErr {
+				return err
+			}
+			return iterate(result, tok, fn)
+		}
+		return newError("cannot iterate over %s", tok.Line, tok.Col, iterable.Type())
+	}
+
+	return nil
+}
+
+func evalRangeExpression(node *ast.RangeExpression, env *object.Environment) object.Object {
+	bounds := []ast.Expression{node.Start, node.End}
+	if node.Step != nil {
+		bounds = append(bounds, node.Step)
+	}
+
+	values := []int64{}
+	for _, bound := range bounds {
+		evaluated := Eval(bound, env)
+		if isError(evaluated) {
+			return evaluated
+		}
+		integer, ok := evaluated.(*object.Integer)
+		if !ok {
+			return newError("range bounds must be INTEGER, got %s", node.Token.Line, node.Token.Col, evaluated.Type())
+		}
+		values = append(values, integer.Value)
+	}
+
+	start, end := values[0], values[1]
+
+	// Without an explicit step, ranges count towards their end
+	step := int64(1)
+	if start > end {
+		step = -1
+	}
+	if len(values) == 3 {
+		step = values[2]
+	}
+	if step == 0 {
+		return newError("range step cannot be 0", node.Token.Line, node.Token.Col)
+	}
+
+	return &object.Range{
+		Start:     start,
+		End:       end,
+		Step:      step,
+		Inclusive: node.Inclusive,
+		Position:  object.Position{Line: node.Token.Line, Col: node.Token.Col},
+	}
+}
+
+func evalIndexExpression(left, index object.Object, pos object.Position) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
-		return evalArrayIndexExpression(left, index)
+		return evalArrayIndexExpression(left, index, pos)
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalStringIndexExpression(left, index, pos)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
 	default:
+		// Values whose type defines __index__ are indexed by calling it
+		if result, ok := callMethod(left, "__index__", index); ok {
+			return result
+		}
 		return newError("index operator not supported: %s", left.Line(), left.Col(), left.Type())
 	}
 }
 
-func evalArrayIndexExpression(array, index object.Object) object.Object {
-	arrayObject := array.(*object.Array)
+// Negative indexes count from the end, so arr[-1] is the last element
+// Returns -1 when the index is out of range
+func resolveIndex(idx int64, length int) int64 {
+	if idx < 0 {
+		idx += int64(length)
+	}
+	if idx < 0 || idx >= int64(length) {
+		return -1
+	}
+	return idx
+}
+
+func evalArrayIndexExpression(array, index object.Object, pos object.Position) object.Object {
+	elements := array.(*object.Array).Items()
+	idx := index.(*object.Integer).Value
+	resolved := resolveIndex(idx, len(elements))
+	if resolved < 0 {
+		if StrictIndexing {
+			return newError("index out of range: %d (length %d)", pos.Line, pos.Col, idx, len(elements))
+		}
+		return NULL
+	}
+	return elements[resolved]
+}
+
+// Strings are indexed by character rather than by byte
+func evalStringIndexExpression(str, index object.Object, pos object.Position) object.Object {
+	chars := []rune(str.(*object.String).Value)
 	idx := index.(*object.Integer).Value
-	max := int64(len(arrayObject.Elements) - 1)
-	if idx < 0 || idx > max {
+	resolved := resolveIndex(idx, len(chars))
+	if resolved < 0 {
+		if StrictIndexing {
+			return newError("index out of range: %d (length %d)", pos.Line, pos.Col, idx, len(chars))
+		}
 		return NULL
 	}
-	return arrayObject.Elements[idx]
+	return &object.String{Value: string(chars[resolved])}
+}
+
+func evalSliceExpression(node *ast.SliceExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	// Evaluate the optional start, end and step, leaving missing parts nil
+	parts := []*int64{nil, nil, nil}
+	for i, exp := range []ast.Expression{node.Start, node.End, node.Step} {
+		if exp == nil {
+			continue
+		}
+		evaluated := Eval(exp, env)
+		if isError(evaluated) {
+			return evaluated
+		}
+		integer, ok := evaluated.(*object.Integer)
+		if !ok {
+			return newError("slice indices must be INTEGER, got %s", node.Token.Line, node.Token.Col, evaluated.Type())
+		}
+		value := integer.Value
+		parts[i] = &value
+	}
+
+	var length int
+	var items []object.Object
+	switch left := left.(type) {
+	case *object.Array:
+		items = left.Items()
+		length = len(items)
+	case *object.String:
+		length = len([]rune(left.Value))
+	default:
+		return newError("slice operator not supported: %s", node.Token.Line, node.Token.Col, left.Type())
+	}
+
+	indices, err := sliceIndices(length, parts[0], parts[1], parts[2], node.Token)
+	if err != nil {
+		return err
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		elements := make([]object.Object, 0, len(indices))
+		for _, i := range indices {
+			elements = append(elements, items[i])
+		}
+		return &object.Array{Elements: elements}
+	default:
+		chars := []rune(left.(*object.String).Value)
+		sliced := make([]rune, 0, len(indices))
+		for _, i := range indices {
+			sliced = append(sliced, chars[i])
+		}
+		return &object.String{Value: string(sliced)}
+	}
+}
+
+// Resolves the indexes a slice selects, following the same rules as Python
+//   - Negative bounds count from the end
+//   - Missing bounds default to the start / end depending on the direction of the step
+//   - Bounds past either end are clamped, unless strict indexing is enabled
+func sliceIndices(length int, start, end, step *int64, tok token.Token) ([]int, *object.Error) {
+	n := int64(length)
+
+	stepVal := int64(1)
+	if step != nil {
+		stepVal = *step
+	}
+	if stepVal == 0 {
+		return nil, newError("slice step cannot be 0", tok.Line, tok.Col)
+	}
+
+	resolve := func(bound *int64, def int64) (int64, *object.Error) {
+		if bound == nil {
+			return def, nil
+		}
+		val := *bound
+		if StrictIndexing && (val < -n || val > n) {
+			return 0, newError("slice bound out of range: %d (length %d)", tok.Line, tok.Col, val, length)
+		}
+		if val < 0 {
+			val += n
+		}
+		if stepVal > 0 {
+			return min(max(val, 0), n), nil
+		}
+		return min(max(val, -1), n-1), nil
+	}
+
+	var from, to int64
+	var err *object.Error
+	if stepVal > 0 {
+		if from, err = resolve(start, 0); err != nil {
+			return nil, err
+		}
+		if to, err = resolve(end, n); err != nil {
+			return nil, err
+		}
+	} else {
+		if from, err = resolve(start, n-1); err != nil {
+			return nil, err
+		}
+		if to, err = resolve(end, -1); err != nil {
+			return nil, err
+		}
+	}
+
+	indices := []int{}
+	for i := from; (stepVal > 0 && i < to) || (stepVal < 0 && i > to); i += stepVal {
+		indices = append(indices, int(i))
+	}
+
+	return indices, nil
 }
 
 func evalHashIndexExpression(hash, index object.Object) object.Object {
 	hashObject := hash.(*object.Hash)
-	key, ok := index.(object.Hashable)
+	key, ok := object.HashKeyOf(index)
 	if !ok {
 		return newError("unusable as hash key: %s", index.Line(), index.Col(), index.Type())
 	}
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	pair, ok := hashObject.Get(key)
 	if !ok {
 		return NULL
 	}
@@ -272,12 +698,22 @@ func evalModuleStatement(stmt *ast.ModuleStatement, env *object.Environment) obj
 
 	// fmt.Printf("//env module: %v\n", env.Modules)
 
+	// Constants like math.PI are imported the same way as functions, and can't be reassigned
+	constants, _ := env.GetModuleConstants(stmt.Name.Value)
+
 	if stmt.ImportAll {
 		for name, fn := range module {
 			env.Set(name, fn)
 		}
+		for name, value := range constants {
+			env.SetConst(name, value)
+		}
 	} else {
 		for _, importName := range stmt.Imports {
+			if value, ok := constants[importName.Value]; ok {
+				env.SetConst(importName.Value, value)
+				continue
+			}
 			fn, exists := module[importName.Value]
 			if !exists {
 				return newError("function %s not found in module %s", importName.Token.Line, importName.Token.Col, importName.Value, stmt.Name.Value)
@@ -291,8 +727,15 @@ func evalModuleStatement(stmt *ast.ModuleStatement, env *object.Environment) obj
 }
 
 // Simply iterate over all statements in the program and evaluate them
-func evalProgram(program *ast.Program, env *object.Environment) object.Object {
-	var result object.Object
+// Timers and servers still pending when the program fails are stopped
+func evalProgram(program *ast.Program, env *object.Environment) (result object.Object) {
+	var pos object.Position
+	defer func() {
+		if isError(result) {
+			object.StopPending()
+		}
+	}()
+	defer recoverAt(&pos, &result)
 
 	for _, stmt := range program.Modules {
 		result = evalModuleStatement(stmt, env)
@@ -302,38 +745,45 @@ func evalProgram(program *ast.Program, env *object.Environment) object.Object {
 	}
 
 	for _, statement := range program.Statements {
+		pos = statementPosition(statement, pos)
 		result = Eval(statement, env)
 
-		switch result := result.(type) {
-		case *object.ReturnValue:
-			return result.Value
-		case *object.Error:
+		if returnValue, ok := result.(*object.ReturnValue); ok {
+			result = returnValue.Value
+			break
+		}
+		if isError(result) {
 			return result
 		}
 	}
 
+	// Timers that are still pending keep the program running until they have all stopped
+	if err := object.RunEvents(nil); err != nil {
+		return err
+	}
 	return result
 }
 
 func evalBlockStatement(
 	block *ast.BlockStatement,
 	env *object.Environment,
-) object.Object {
-	var result object.Object
+) (result object.Object) {
+	var pos object.Position
+	defer recoverAt(&pos, &result)
 
 	for _, statement := range block.Statements {
+		pos = statementPosition(statement, pos)
 		result = Eval(statement, env)
 
+		// Break and continue skip the rest of the block, and are passed up
+		// until they reach the enclosing loop
 		if result != nil {
 			rt := result.Type()
 			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
 				return result
 			}
-			if rt == object.BREAK_OBJ {
-				break
-			}
-			if rt == object.CONTINUE_OBJ {
-				continue
+			if rt == object.BREAK_OBJ || rt == object.CONTINUE_OBJ {
+				return result
 			}
 		}
 	}
@@ -341,6 +791,46 @@ func evalBlockStatement(
 	return result
 }
 
+// Any unexpected Go panic is recovered and reported as an internal error
+// at the position of the statement that was being evaluated
+// Each program and block keeps that position itself, so tasks running at the same time don't see each other's
+func recoverAt(pos *object.Position, result *object.Object) {
+	if r := recover(); r != nil {
+		*result = newError("internal error: %v", pos.Line, pos.Col, r)
+	}
+}
+
+// The position of the statement about to be evaluated, or the last one when it doesn't have one
+func statementPosition(stmt ast.Statement, last object.Position) object.Position {
+	var tok token.Token
+
+	switch stmt := stmt.(type) {
+	case *ast.LetStatement:
+		tok = stmt.Token
+	case *ast.AssignStatement:
+		tok = stmt.Token
+	case *ast.ReturnStatement:
+		tok = stmt.Token
+	case *ast.ExpressionStatement:
+		tok = stmt.Token
+	case *ast.WhileStatement:
+		tok = stmt.Token
+	case *ast.ForStatement:
+		tok = stmt.Token
+	case *ast.ForInStatement:
+		tok = stmt.Token
+	case *ast.BlockStatement:
+		tok = stmt.Token
+	default:
+		return last
+	}
+
+	if tok.Line > 0 {
+		return object.Position{Line: tok.Line, Col: tok.Col}
+	}
+	return last
+}
+
 func nativeBoolToBooleanObject(input bool) *object.Boolean {
 	if input {
 		return TRUE
@@ -361,12 +851,12 @@ func evalStringInfixExpression(
 	return &object.String{Value: leftVal + rightVal}
 }
 
-func evalPrefixExpression(operator string, right object.Object) object.Object {
+func evalPrefixExpression(operator string, right object.Object, pos object.Position) object.Object {
 	switch operator {
 	case "!":
 		return evalBangOperatorExpression(right)
 	case "-":
-		return evalMinusPrefixOperatorExpression(right)
+		return evalMinusPrefixOperatorExpression(right, pos)
 	default:
 		return newError("unknown operator: %s%s", right.Line(), right.Col(), operator, right.Type())
 	}
@@ -376,34 +866,48 @@ func evalInfixExpression(
 	operator string,
 	left, right object.Object,
 	literal string,
+	pos object.Position,
 	env *object.Environment,
 ) object.Object {
+	if result, ok := evalOperatorMethod(operator, left, right, literal, pos, env); ok {
+		return result
+	}
+	if result, ok := evalTimeInfixExpression(operator, left, right, literal, pos, env); ok {
+		return result
+	}
+
 	switch {
+	case operator == "===":
+		return nativeBoolToBooleanObject(object.Same(left, right))
+	case operator == "!==":
+		return nativeBoolToBooleanObject(!object.Same(left, right))
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		if isCompoundOperator(operator) {
-			return evalCompoundAssignment(operator, left, right, env, literal)
+			return evalCompoundAssignment(operator, left, right, env, literal, pos)
 		}
-		return evalIntegerInfixExpression(operator, left, right)
+		return evalIntegerInfixExpression(operator, left, right, pos)
 	case (left.Type() == object.FLOAT_OBJ && right.Type() == object.INTEGER_OBJ) || (left.Type() == object.INTEGER_OBJ && right.Type() == object.FLOAT_OBJ):
 		if isCompoundOperator(operator) {
-			return evalCompoundAssignment(operator, left, right, env, literal)
+			return evalCompoundAssignment(operator, left, right, env, literal, pos)
 		}
-		return evalFloatInfixExpression(operator, left, right)
+		return evalFloatInfixExpression(operator, left, right, pos)
 	case left.Type() == object.FLOAT_OBJ && right.Type() == object.FLOAT_OBJ:
 		if isCompoundOperator(operator) {
-			return evalCompoundAssignment(operator, left, right, env, literal)
+			return evalCompoundAssignment(operator, left, right, env, literal, pos)
 		}
-		return evalFloatInfixExpression(operator, left, right)
+		return evalFloatInfixExpression(operator, left, right, pos)
+	case (operator == "<" || operator == ">") && object.MethodsOf(left)["compare"] != nil:
+		return evalCompareMethod(operator, left, right, pos)
 	case operator == "==":
-		return nativeBoolToBooleanObject(left == right)
+		return nativeBoolToBooleanObject(object.Equals(left, right))
 	case operator == "!=":
-		return nativeBoolToBooleanObject(left != right)
+		return nativeBoolToBooleanObject(!object.Equals(left, right))
 	case operator == "-=":
-		return evalInfixExpression("-", left, right, literal, env)
+		return evalInfixExpression("-", left, right, literal, pos, env)
 	case operator == "*=":
-		return evalInfixExpression("*", left, right, literal, env)
+		return evalInfixExpression("*", left, right, literal, pos, env)
 	case operator == "/=":
-		return evalInfixExpression("/", left, right, literal, env)
+		return evalInfixExpression("/", left, right, literal, pos, env)
 	case left.Type() != right.Type():
 		return newError("type mismatch: %s %s %s", left.Line(), left.Col(),
 			left.Type(), operator, right.Type())
@@ -429,25 +933,36 @@ func evalCompoundAssignment(
 	left, right object.Object,
 	env *object.Environment,
 	literal string,
+	pos object.Position,
 ) object.Object {
+	if env.IsConst(literal) {
+		return newError("cannot assign to constant: %s", pos.Line, pos.Col, literal)
+	}
+
 	switch {
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
-		result := evalIntegerInfixExpression(operator, left, right)
-		env.Set(literal, result)
+		result := evalIntegerInfixExpression(operator, left, right, pos)
+		if isError(result) {
+			return result
+		}
+		env.Assign(literal, result)
 		return result
 	case (left.Type() == object.FLOAT_OBJ && right.Type() == object.INTEGER_OBJ) || (left.Type() == object.INTEGER_OBJ && right.Type() == object.FLOAT_OBJ):
-		result := evalFloatInfixExpression(operator, left, right)
-		env.Set(literal, result)
+		result := evalFloatInfixExpression(operator, left, right, pos)
+		if isError(result) {
+			return result
+		}
+		env.Assign(literal, result)
 		return result
 	// case left.Type() == object.FLOAT_OBJ && right.Type() == object.FLOAT_OBJ:
-	// 	return evalFloatInfixExpression(operator, left, right)
+	// 	return evalFloatInfixExpression(operator, left, right, pos)
 	default:
 		return newError("unknown operator: %s %s %s", left.Line(), left.Col(),
 			left.Type(), operator, right.Type())
 	}
 }
 
-func evalPostfixExpression(operator string, left object.Object) object.Object {
+func evalPostfixExpression(operator string, left object.Object, pos object.Position) object.Object {
 	if left.Type() != object.INTEGER_OBJ && left.Type() != object.FLOAT_OBJ {
 		return newError("unknown operator: %s%s", left.Line(), left.Col(), operator, left.Type())
 	}
@@ -458,8 +973,14 @@ func evalPostfixExpression(operator string, left object.Object) object.Object {
 
 		switch operator {
 		case "++":
+			if CheckedArithmetic && leftVal == math.MaxInt64 {
+				return newError("integer overflow: %d%s", pos.Line, pos.Col, leftVal, operator)
+			}
 			return &object.Integer{Value: leftVal + 1}
 		case "--":
+			if CheckedArithmetic && leftVal == math.MinInt64 {
+				return newError("integer overflow: %d%s", pos.Line, pos.Col, leftVal, operator)
+			}
 			return &object.Integer{Value: leftVal - 1}
 		default:
 			return newError("unknown operator: %s%s", left.Line(), left.Col(), operator, left.Type())
@@ -483,25 +1004,19 @@ func evalPostfixExpression(operator string, left object.Object) object.Object {
 }
 
 func evalBangOperatorExpression(right object.Object) object.Object {
-	switch right {
-	case TRUE:
-		return FALSE
-	case FALSE:
-		return TRUE
-	case NULL:
-		return TRUE
-	default:
-		return FALSE
-	}
+	return nativeBoolToBooleanObject(!object.IsTruthy(right))
 }
 
-func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
+func evalMinusPrefixOperatorExpression(right object.Object, pos object.Position) object.Object {
 	if right.Type() != object.INTEGER_OBJ && right.Type() != object.FLOAT_OBJ {
 		return newError("unknown operator: -%s", right.Line(), right.Col(), right.Type())
 	}
 
 	if right.Type() == object.INTEGER_OBJ {
 		value := right.(*object.Integer).Value
+		if CheckedArithmetic && value == math.MinInt64 {
+			return newError("integer overflow: -(%d)", pos.Line, pos.Col, value)
+		}
 		return &object.Integer{Value: -value}
 	}
 	if right.Type() == object.FLOAT_OBJ {
@@ -515,10 +1030,25 @@ func evalMinusPrefixOperatorExpression(right object.Object) object.Object {
 func evalIntegerInfixExpression(
 	operator string,
 	left, right object.Object,
+	pos object.Position,
 ) object.Object {
 	leftVal := left.(*object.Integer).Value
 	rightVal := right.(*object.Integer).Value
 
+	switch operator {
+	case "/", "/=", "%":
+		if rightVal == 0 {
+			return newError("division by zero: %d %s %d", pos.Line, pos.Col, leftVal, operator, rightVal)
+		}
+	}
+
+	switch operator {
+	case "+", "-", "*", "/", "+=", "-=", "*=", "/=":
+		if CheckedArithmetic && integerOverflows(operator, leftVal, rightVal) {
+			return newError("integer overflow: %d %s %d", pos.Line, pos.Col, leftVal, operator, rightVal)
+		}
+	}
+
 	switch operator {
 	case "+":
 		return &object.Integer{Value: leftVal + rightVal}
@@ -528,6 +1058,8 @@ func evalIntegerInfixExpression(
 		return &object.Integer{Value: leftVal * rightVal}
 	case "/":
 		return &object.Integer{Value: leftVal / rightVal}
+	case "%":
+		return &object.Integer{Value: leftVal % rightVal}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
@@ -550,9 +1082,34 @@ func evalIntegerInfixExpression(
 	}
 }
 
+// Reports whether applying the operator to the operands overflows int64
+func integerOverflows(operator string, left, right int64) bool {
+	switch operator {
+	case "+", "+=":
+		result := left + right
+		return (left >= 0) == (right >= 0) && (result >= 0) != (left >= 0)
+	case "-", "-=":
+		result := left - right
+		return (left >= 0) != (right >= 0) && (result >= 0) != (left >= 0)
+	case "*", "*=":
+		if left == 0 || right == 0 {
+			return false
+		}
+		if (left == -1 && right == math.MinInt64) || (right == -1 && left == math.MinInt64) {
+			return true
+		}
+		return (left*right)/right != left
+	case "/", "/=":
+		return left == math.MinInt64 && right == -1
+	default:
+		return false
+	}
+}
+
 func evalFloatInfixExpression(
 	operator string,
 	left, right object.Object,
+	pos object.Position,
 ) object.Object {
 	var leftVal, rightVal float64
 
@@ -574,6 +1131,14 @@ func evalFloatInfixExpression(
 		return newError("type mismatch: %s %s %s", left.Line(), left.Col(), left.Type(), operator, right.Type())
 	}
 
+	// Dividing by zero is an error like it is for integers, rather than giving an infinity or NaN
+	switch operator {
+	case "/", "/=", "%":
+		if rightVal == 0 {
+			return newError("division by zero: %s %s %s", pos.Line, pos.Col, left.Inspect(), operator, right.Inspect())
+		}
+	}
+
 	switch operator {
 	case "+":
 		return &object.Float{Value: leftVal + rightVal}
@@ -583,6 +1148,8 @@ func evalFloatInfixExpression(
 		return &object.Float{Value: leftVal * rightVal}
 	case "/":
 		return &object.Float{Value: leftVal / rightVal}
+	case "%":
+		return &object.Float{Value: math.Mod(leftVal, rightVal)}
 	case "<":
 		return nativeBoolToBooleanObject(leftVal < rightVal)
 	case ">":
@@ -614,7 +1181,7 @@ func evalIfExpression(
 		return condition
 	}
 
-	if isTruthy(condition) {
+	if object.IsTruthy(condition) {
 		return Eval(ie.Consequence, env)
 	} else if ie.Alternative != nil {
 		return Eval(ie.Alternative, env)
@@ -631,40 +1198,407 @@ func evalIdentifier(
 		return val
 	}
 
-	parts := strings.Split(node.Value, ".")
-	if len(parts) == 2 {
-		moduleName, functionName := parts[0], parts[1]
-		if module, exists := env.Modules[moduleName]; exists {
-			if fn, found := module[functionName]; found {
-				return fn
+	// Module names resolve to the module itself, so members can be accessed on them
+	if functions, ok := env.GetModule(node.Value); ok {
+		return &object.Module{Name: node.Value, Functions: functions, Position: object.Position{Line: node.Token.Line, Col: node.Token.Col}}
+	}
+
+	if builtin, ok := builtins[node.Value]; ok {
+		return builtin
+	}
+
+	if protocol, ok := object.Protocols[node.Value]; ok {
+		return protocol
+	}
+
+	return newError("identifier not found: %s", node.Token.Line, node.Token.Col, node.Value)
+}
+
+// Values of these types expose the functions of their module as methods,
+// so "abc".upper() is the same as strings.upper("abc")
+var methodModules = map[object.ObjectType]string{
+	object.STRING_OBJ:   "strings",
+	object.ARRAY_OBJ:    "arrays",
+	object.HASH_OBJ:     "hashes",
+	object.ITERATOR_OBJ: "iter",
+	object.TASK_OBJ:     "sync",
+	object.MUTEX_OBJ:    "sync",
+	object.TIMER_OBJ:    "time",
+	object.DATETIME_OBJ: "time",
+	object.DURATION_OBJ: "time",
+	object.SERVER_OBJ:   "http",
+	object.REGEX_OBJ:    "regex",
+	object.BUILDER_OBJ:  "strings",
+}
+
+// Types that only have some of their module's functions as methods
+var methodSubsets = map[object.ObjectType][]string{
+	object.BUILDER_OBJ: object.BuilderMethods,
+}
+
+func evalMemberExpression(node *ast.MemberExpression, env *object.Environment) object.Object {
+	obj := evalMemberObject(node.Object, env)
+	if isError(obj) {
+		return obj
+	}
+
+	name := node.Property.Value
+	line, col := node.Property.Token.Line, node.Property.Token.Col
+
+	switch obj := obj.(type) {
+	case *object.Module:
+		if fn, ok := obj.Functions[name]; ok {
+			return fn
+		}
+		if constants, ok := env.GetModuleConstants(obj.Name); ok {
+			if value, ok := constants[name]; ok {
+				return value
 			}
-			return newError("function not found in module '%s': %s", node.Token.Line, node.Token.Col, moduleName, functionName)
 		}
+		nested := obj.Name + "." + name
+		if functions, ok := env.GetModule(nested); ok {
+			return &object.Module{Name: nested, Functions: functions, Position: object.Position{Line: line, Col: col}}
+		}
+		return newError("function not found in module '%s': %s", line, col, obj.Name, name)
+
+	case *object.Record:
+		// Fields take priority over methods, like they do for hashes
+		if value, ok := obj.Get(name); ok {
+			return value
+		}
+		if method, ok := lookupUserMethod(obj, name); ok {
+			return method
+		}
+		return newError("%s has no field '%s'", line, col, obj.RecordType.Name, name)
 
-		return newError("module not found: %s", node.Token.Line, node.Token.Col, moduleName)
+	case *object.Enum:
+		return evalEnumMember(obj, name, line, col)
+
+	case *object.EnumValue:
+		return evalEnumValueMember(obj, name, line, col)
+
+	case *object.Hash:
+		// Fields take priority over methods, so a hash can hold its own functions
+		if pair, ok := obj.Get((&object.String{Value: name}).HashKey()); ok {
+			return pair.Value
+		}
+		if method, ok := lookupMethod(obj, name, env); ok {
+			return method
+		}
+		return NULL
 	}
 
-	return newError("identifier not found: %s", node.Token.Line, node.Token.Col, node.Value)
+	if method, ok := lookupMethod(obj, name, env); ok {
+		return method
+	}
+
+	return newError("%s has no member '%s'", line, col, obj.Type(), name)
 }
 
-func isTruthy(obj object.Object) bool {
-	switch obj {
-	case NULL:
-		return false
-	case TRUE:
-		return true
-	case FALSE:
-		return false
-	default:
-		return true
+// Importing a function with `mod rand: [rand]` binds a builtin with the same
+// name as its module. Builtins have no members, so `rand.rand` still means the module
+func evalMemberObject(node ast.Expression, env *object.Environment) object.Object {
+	if ident, ok := node.(*ast.Identifier); ok {
+		val, bound := env.Get(ident.Value)
+		if _, isBuiltin := val.(*object.Builtin); !bound || isBuiltin {
+			if functions, ok := env.GetModule(ident.Value); ok {
+				return &object.Module{Name: ident.Value, Functions: functions, Position: object.Position{Line: ident.Token.Line, Col: ident.Token.Col}}
+			}
+		}
 	}
+
+	return Eval(node, env)
+}
+
+// Binds a module function to the receiver, which is passed as its first argument
+func lookupMethod(receiver object.Object, name string, env *object.Environment) (*object.Builtin, bool) {
+	moduleName, ok := methodModules[receiver.Type()]
+	if !ok {
+		return nil, false
+	}
+	if names, ok := methodSubsets[receiver.Type()]; ok && !slices.Contains(names, name) {
+		return nil, false
+	}
+	functions, ok := env.GetModule(moduleName)
+	if !ok {
+		return nil, false
+	}
+	fn, ok := functions[name]
+	if !ok {
+		return nil, false
+	}
+
+	return &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			return fn.Fn(append([]object.Object{receiver}, args...)...)
+		},
+	}, true
+}
+
+func evalMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := Eval(node.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range node.Arms {
+		// Each arm gets its own scope, so bindings from a failed arm don't leak into the next
+		armEnv := object.NewArmEnvironment(env)
+
+		matched, err := matchPattern(arm.Pattern, subject, armEnv)
+		if err != nil {
+			return err
+		}
+		if !matched {
+			continue
+		}
+
+		if arm.Guard != nil {
+			guard := Eval(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !object.IsTruthy(guard) {
+				continue
+			}
+		}
+
+		result := evalBlockStatement(arm.Body, armEnv)
+		if result == nil {
+			return NULL
+		}
+		return result
+	}
+
+	return newError("no match arm matched value: %s", node.Token.Line, node.Token.Col, subject.Inspect())
+}
+
+// Names usable in type patterns (n: int) and the object types they match
+var patternTypes = map[string][]object.ObjectType{
+	"int":      {object.INTEGER_OBJ},
+	"float":    {object.FLOAT_OBJ},
+	"number":   {object.INTEGER_OBJ, object.FLOAT_OBJ},
+	"string":   {object.STRING_OBJ},
+	"bool":     {object.BOOLEAN_OBJ},
+	"array":    {object.ARRAY_OBJ},
+	"hash":     {object.HASH_OBJ},
+	"range":    {object.RANGE_OBJ},
+	"iterator": {object.ITERATOR_OBJ},
+	"channel":  {object.CHANNEL_OBJ},
+	"task":     {object.TASK_OBJ},
+	"mutex":    {object.MUTEX_OBJ},
+	"timer":    {object.TIMER_OBJ},
+	"datetime": {object.DATETIME_OBJ},
+	"duration": {object.DURATION_OBJ},
+	"server":   {object.SERVER_OBJ},
+	"regex":    {object.REGEX_OBJ},
+	"builder":  {object.BUILDER_OBJ},
+	"fn":       {object.FUNCTION_OBJ, object.BUILTIN_OBJ},
+	"null":     {object.NULL_OBJ},
+}
+
+// Reports whether the value matches the pattern, binding any names into env
+// Errors are only returned for patterns that can't be evaluated at all
+func matchPattern(pattern ast.Pattern, value object.Object, env *object.Environment) (bool, *object.Error) {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return true, nil
+
+	case *ast.BindingPattern:
+		env.Set(pattern.Name.Value, value)
+		return true, nil
+
+	case *ast.LiteralPattern:
+		literal := Eval(pattern.Value, env)
+		if err, ok := literal.(*object.Error); ok {
+			return false, err
+		}
+		return object.Equals(literal, value), nil
+
+	case *ast.RangePattern:
+		return matchRangePattern(pattern, value, env)
+
+	case *ast.DefaultPattern:
+		return matchPattern(pattern.Pattern, value, env)
+
+	case *ast.ArrayPattern:
+		array, ok := value.(*object.Array)
+		if !ok {
+			return false, nil
+		}
+		elements := array.Items()
+		if pattern.Rest == nil && len(elements) > len(pattern.Elements) {
+			return false, nil
+		}
+		for i, element := range pattern.Elements {
+			// Missing elements only match when a default is given
+			if i >= len(elements) {
+				def, ok := element.(*ast.DefaultPattern)
+				if !ok {
+					return false, nil
+				}
+				if matched, err := matchDefault(def, env); !matched || err != nil {
+					return false, err
+				}
+				continue
+			}
+			if matched, err := matchPattern(element, elements[i], env); !matched || err != nil {
+				return false, err
+			}
+		}
+		if pattern.Rest != nil {
+			rest := []object.Object{}
+			if len(elements) > len(pattern.Elements) {
+				rest = append(rest, elements[len(pattern.Elements):]...)
+			}
+			env.Set(pattern.Rest.Value, &object.Array{Elements: rest})
+		}
+		return true, nil
+
+	case *ast.HashPattern:
+		// Records match hash patterns by field name, so {x, y} also destructures a Point
+		if _, isHash := value.(*object.Hash); !isHash && value.Type() != object.RECORD_OBJ {
+			return false, nil
+		}
+		for _, pair := range pattern.Pairs {
+			key := Eval(pair.Key, env)
+			if err, ok := key.(*object.Error); ok {
+				return false, err
+			}
+			entry, ok := lookupPatternKey(value, key)
+			if !ok {
+				def, hasDefault := pair.Value.(*ast.DefaultPattern)
+				if !hasDefault {
+					return false, nil
+				}
+				if matched, err := matchDefault(def, env); !matched || err != nil {
+					return false, err
+				}
+				continue
+			}
+			if matched, err := matchPattern(pair.Value, entry, env); !matched || err != nil {
+				return false, err
+			}
+		}
+		return true, nil
+
+	case *ast.TypePattern:
+		if _, known := typeMatches(value, pattern.TypeName.Name, env); !known {
+			return false, newError("unknown type in pattern: %s", pattern.TypeName.Token.Line, pattern.TypeName.Token.Col, pattern.TypeName.Name)
+		}
+		if err := checkTypeExpr(pattern.TypeName, env); err != nil {
+			return false, err
+		}
+		if !annotationMatches(value, pattern.TypeName, env) {
+			return false, nil
+		}
+		return matchPattern(pattern.Pattern, value, env)
+
+	case *ast.VariantPattern:
+		return matchVariantPattern(pattern, value, env)
+	}
+
+	return false, newError("unsupported pattern: %s", 0, 0, pattern.String())
+}
+
+// Looks up a hash pattern key in a hash, or a field name in a record
+func lookupPatternKey(value, key object.Object) (object.Object, bool) {
+	switch value := value.(type) {
+	case *object.Hash:
+		hashKey, ok := object.HashKeyOf(key)
+		if !ok {
+			return nil, false
+		}
+		pair, ok := value.Get(hashKey)
+		return pair.Value, ok
+	case *object.Record:
+		name, ok := key.(*object.String)
+		if !ok {
+			return nil, false
+		}
+		return value.Get(name.Value)
+	}
+	return nil, false
+}
+
+func matchDefault(def *ast.DefaultPattern, env *object.Environment) (bool, *object.Error) {
+	value := Eval(def.Default, env)
+	if err, ok := value.(*object.Error); ok {
+		return false, err
+	}
+	return matchPattern(def.Pattern, value, env)
+}
+
+// Destructuring lets bind every name in the pattern in the current scope
+// Unlike a match arm, a value that doesn't fit the pattern is an error
+func evalDestructuring(node *ast.LetStatement, value object.Object, env *object.Environment) object.Object {
+	names := ast.PatternNames(node.Pattern)
+	for _, name := range names {
+		if env.IsLocalConst(name.Value) {
+			return newError("cannot redeclare constant: %s", name.Token.Line, name.Token.Col, name.Value)
+		}
+	}
+
+	matched, err := matchPattern(node.Pattern, value, env)
+	if err != nil {
+		return err
+	}
+	if !matched {
+		return newError("cannot destructure %s with pattern %s", node.Token.Line, node.Token.Col, value.Inspect(), node.Pattern.String())
+	}
+
+	if node.Constant {
+		for _, name := range names {
+			bound, _ := env.Get(name.Value)
+			env.SetConst(name.Value, bound)
+		}
+	}
+	return nil
+}
+
+// Range patterns match integers and floats between the bounds
+func matchRangePattern(pattern *ast.RangePattern, value object.Object, env *object.Environment) (bool, *object.Error) {
+	toFloat := func(obj object.Object) (float64, bool) {
+		switch obj := obj.(type) {
+		case *object.Integer:
+			return float64(obj.Value), true
+		case *object.Float:
+			return obj.Value, true
+		}
+		return 0, false
+	}
+
+	bounds := []float64{}
+	for _, exp := range []ast.Expression{pattern.Start, pattern.End} {
+		bound := Eval(exp, env)
+		if err, ok := bound.(*object.Error); ok {
+			return false, err
+		}
+		number, ok := toFloat(bound)
+		if !ok {
+			return false, newError("range pattern bounds must be numbers, got %s", pattern.Token.Line, pattern.Token.Col, bound.Type())
+		}
+		bounds = append(bounds, number)
+	}
+
+	number, ok := toFloat(value)
+	if !ok {
+		return false, nil
+	}
+	if pattern.Inclusive {
+		return number >= bounds[0] && number <= bounds[1], nil
+	}
+	return number >= bounds[0] && number < bounds[1], nil
 }
 
 func newError(format string, line, col int, a ...interface{}) *object.Error {
-	// for _, line := range Lines {
-	// 	fmt.Printf("// %s //\n", line)
-	// }
-	return &object.Error{Message: fmt.Sprintf(format, a...), Position: object.Position{Line: line, Col: col}, Context: Lines[line-1]}
+	// Objects created at runtime (and builtins) don't always carry a position,
+	// so only attach source context when the line actually exists
+	var context string
+	if line > 0 && line <= len(Lines) {
+		context = Lines[line-1]
+	}
+	return &object.Error{Message: fmt.Sprintf(format, a...), Position: object.Position{Line: line, Col: col}, Context: context}
 }
 
 func isError(obj object.Object) bool {
@@ -681,6 +1615,15 @@ func evalExpressions(
 	var result []object.Object
 
 	for _, e := range exps {
+		if spread, ok := e.(*ast.SpreadExpression); ok {
+			spreadResult, err := evalSpread(spread, env)
+			if err != nil {
+				return []object.Object{err}
+			}
+			result = append(result, spreadResult...)
+			continue
+		}
+
 		evaluated := Eval(e, env)
 		if isError(evaluated) {
 			return []object.Object{evaluated}
@@ -691,14 +1634,65 @@ func evalExpressions(
 	return result
 }
 
+// Expands anything iterable except hashes, which can only be spread into hash literals
+func evalSpread(spread *ast.SpreadExpression, env *object.Environment) ([]object.Object, object.Object) {
+	value := Eval(spread.Value, env)
+	if isError(value) {
+		return nil, value
+	}
+	if value.Type() == object.HASH_OBJ {
+		return nil, newError("cannot spread HASH here, hashes can only be spread into hash literals", spread.Token.Line, spread.Token.Col)
+	}
+	if !isIterable(value) {
+		return nil, newError("cannot spread %s", spread.Token.Line, spread.Token.Col, value.Type())
+	}
+
+	// Errors from producing the values, like a failing generator, are kept as they are
+	elements := []object.Object{}
+	err := iterate(value, spread.Token, func(_, el object.Object) bool {
+		elements = append(elements, el)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return elements, nil
+}
+
 func applyFunction(fn object.Object, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
+		// Every parameter needs an argument, extra arguments are ignored like they always have been
+		if len(args) < len(fn.Parameters) {
+			return newError("wrong number of arguments. got=%d, want=%d", 0, 0, len(args), len(fn.Parameters))
+		}
 		extendedEnv := extendFunctionEnv(fn, args)
-		evaluated := Eval(fn.Body, extendedEnv)
-		return unwrapReturnValue(evaluated)
+		if err := checkArguments(fn, args, extendedEnv); err != nil {
+			return err
+		}
+		var evaluated object.Object
+		if fn.Generator {
+			// The body only runs as values are asked for, what the call returns is the iterator over them
+			evaluated = newGenerator(fn, extendedEnv)
+		} else {
+			evaluated = unwrapReturnValue(Eval(fn.Body, extendedEnv))
+		}
+		if fn.ReturnType != nil && !isError(evaluated) {
+			if evaluated == nil {
+				evaluated = NULL
+			}
+			if err := checkType(evaluated, fn.ReturnType, extendedEnv, "return value"); err != nil {
+				return err
+			}
+		}
+		return evaluated
 	case *object.Builtin:
 		return fn.Fn(args...)
+	case *object.RecordType:
+		return constructRecord(fn, args)
+	case *object.EnumVariant:
+		return constructEnumValue(fn, args)
 	default:
 		return newError("not a function: %s", fn.Line(), fn.Col(), fn.Type())
 	}
@@ -710,6 +1704,9 @@ func extendFunctionEnv(
 ) *object.Environment {
 	env := object.NewEnclosedEnvironment(fn.Env)
 
+	for _, typeParam := range fn.TypeParameters {
+		env.Set(typeParam.Value, &object.TypeParameter{Name: typeParam.Value})
+	}
 	for paramIdx, param := range fn.Parameters {
 		env.Set(param.Value, args[paramIdx])
 	}
@@ -729,22 +1726,38 @@ func evalHashLiteral(
 	node *ast.HashLiteral,
 	env *object.Environment,
 ) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
-	for keyNode, valueNode := range node.Pairs {
-		key := Eval(keyNode, env)
+	hash := object.NewHash()
+	hash.Position = object.Position{Line: node.Token.Line, Col: node.Token.Col}
+	for _, pair := range node.Pairs {
+		if spread, ok := pair.Key.(*ast.SpreadExpression); ok {
+			value := Eval(spread.Value, env)
+			if isError(value) {
+				return value
+			}
+			other, ok := value.(*object.Hash)
+			if !ok {
+				return newError("cannot spread %s into a hash", spread.Token.Line, spread.Token.Col, value.Type())
+			}
+			for _, entry := range other.OrderedPairs() {
+				key, _ := object.HashKeyOf(entry.Key)
+				hash.Set(key, entry)
+			}
+			continue
+		}
+
+		key := Eval(pair.Key, env)
 		if isError(key) {
 			return key
 		}
-		hashKey, ok := key.(object.Hashable)
+		hashKey, ok := object.HashKeyOf(key)
 		if !ok {
 			return newError("unusable as hash key: %s", key.Line(), key.Col(), key.Type())
 		}
-		value := Eval(valueNode, env)
+		value := Eval(pair.Value, env)
 		if isError(value) {
 			return value
 		}
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		hash.Set(hashKey, object.HashPair{Key: key, Value: value})
 	}
-	return &object.Hash{Pairs: pairs}
+	return hash
 }
@@ -30,6 +30,9 @@ func TestEvalIntegerExpression(t *testing.T) {
 		{"3 * 3 * 3 + 10", 37},
 		{"3 * (3 * 3) + 10", 37},
 		{"(5 + 10 * 2 + 15 / 3) * 2 + -10", 50},
+		{"7 % 3", 1},
+		{"-7 % 3", -1},
+		{"2 + 10 % 4 * 3", 8},
 	}
 
 	for _, tt := range tests {
@@ -322,6 +325,14 @@ func TestArrayIndexExpressions(t *testing.T) {
 		},
 		{
 			"[1, 2, 3][-1]",
+			3,
+		},
+		{
+			"[1, 2, 3][-3]",
+			1,
+		},
+		{
+			"[1, 2, 3][-4]",
 			nil,
 		},
 	}
@@ -368,15 +379,17 @@ func TestWhileLoops(t *testing.T) {
 		{
 			`
 			let x = 0;
+			let y = 0;
 			while (x < 10) {
+				x += 1;
 				if (x == 5) {
 					continue;
 				}
-				x += 1;
+				y += 1;
 			}
-			return x;
+			return y;
 				`,
-			10,
+			9,
 		},
 		{
 			`
@@ -563,3 +576,1053 @@ func TestHashIndexExpressions(t *testing.T) {
 		}
 	}
 }
+
+func TestArithmeticErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedMessage string
+		expectedLine    int
+	}{
+		{"1 / 0", "division by zero: 1 / 0", 1},
+		{"10 % 0", "division by zero: 10 % 0", 1},
+		{"let x = 5;\nx /= 0;", "division by zero: 5 /= 0", 2},
+		{"let f = fn(x) {\n  x / (x - x);\n};\nf(3);", "division by zero: 3 / 0", 2},
+		{"1.5 / 0", "division by zero: 1.500000 / 0", 1},
+		{"1 % 0.0", "division by zero: 1 % 0.000000", 1},
+		{"let x = 2.5;\nx /= 0;", "division by zero: 2.500000 /= 0", 2},
+		{"0.0 / 0.0", "division by zero: 0.000000 / 0.000000", 1},
+		{"let f = fn(x, y) { x + y; };\nf(1);", "wrong number of arguments. got=1, want=2", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error message. expected=%q, got=%q", tt.expectedMessage, errObj.Message)
+		}
+		if errObj.Position.Line != tt.expectedLine {
+			t.Errorf("wrong error line for %q. expected=%d, got=%d", tt.input, tt.expectedLine, errObj.Position.Line)
+		}
+	}
+}
+
+func TestCheckedArithmetic(t *testing.T) {
+	CheckedArithmetic = true
+	defer func() { CheckedArithmetic = false }()
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"9223372036854775807 + 1", "integer overflow: 9223372036854775807 + 1"},
+		{"let x = -9223372036854775807; x - 2", "integer overflow: -9223372036854775807 - 2"},
+		{"4611686018427387904 * 2", "integer overflow: 4611686018427387904 * 2"},
+		{"let x = 9223372036854775807; x += 1;", "integer overflow: 9223372036854775807 += 1"},
+		{"9223372036854775806 + 1", 9223372036854775807},
+		{"3037000499 * 3037000499", 9223372030926249001},
+		{"[9223372036854775807, 1].sum()", "integer overflow: 9223372036854775807 + 1"},
+		{"[-9223372036854775807, -2].sum()", "integer overflow: -9223372036854775807 + -2"},
+		{"[9223372036854775806, 1].sum()", 9223372036854775807},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+
+	CheckedArithmetic = false
+	testIntegerObject(t, testEval("9223372036854775807 + 1"), -9223372036854775808)
+	testIntegerObject(t, testEval("[9223372036854775807, 1].sum()"), -9223372036854775808)
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3, 4, 5][1:3]", "[2, 3]"},
+		{"[1, 2, 3, 4, 5][:2]", "[1, 2]"},
+		{"[1, 2, 3, 4, 5][3:]", "[4, 5]"},
+		{"[1, 2, 3, 4, 5][:]", "[1, 2, 3, 4, 5]"},
+		{"[1, 2, 3, 4, 5][::2]", "[1, 3, 5]"},
+		{"[1, 2, 3, 4, 5][-2:]", "[4, 5]"},
+		{"[1, 2, 3, 4, 5][::-1]", "[5, 4, 3, 2, 1]"},
+		{"[1, 2, 3, 4, 5][3:0:-1]", "[4, 3, 2]"},
+		{"[1, 2, 3][1:100]", "[2, 3]"},
+		{"[1, 2, 3][2:1]", "[]"},
+		{"let n = 2; [1, 2, 3][:n]", "[1, 2]"},
+		{`"hello world"[0]`, "h"},
+		{`"hello world"[-1]`, "d"},
+		{`"hello world"[2:5]`, "llo"},
+		{`"hello"[1:-1]`, "ell"},
+		{`"abc"[::-1]`, "cba"},
+		{"[1, 2, 3][::0]", "slice step cannot be 0"},
+		{`[1, 2, 3]["a":]`, "slice indices must be INTEGER, got STRING"},
+		{"5[1:2]", "slice operator not supported: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+
+	testNullObject(t, testEval(`"abc"[3]`))
+}
+
+func TestStrictIndexing(t *testing.T) {
+	StrictIndexing = true
+	defer func() { StrictIndexing = false }()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"[1, 2, 3][3]", "index out of range: 3 (length 3)"},
+		{"[1, 2, 3][-4]", "index out of range: -4 (length 3)"},
+		{`"abc"[5]`, "index out of range: 5 (length 3)"},
+		{"[1, 2, 3][1:10]", "slice bound out of range: 10 (length 3)"},
+		{"[1, 2, 3][-5:]", "slice bound out of range: -5 (length 3)"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message. expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+
+	testIntegerObject(t, testEval("[1, 2, 3][-1]"), 3)
+	if result := testEval("[1, 2, 3][0:3]").Inspect(); result != "[1, 2, 3]" {
+		t.Errorf("expected full slice to be allowed, got=%q", result)
+	}
+}
+
+func TestMemberExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let user = {"name": "Ada", "age": 36}; user.age`, 36},
+		{`let config = {"db": {"port": 5432}}; config.db.port`, 5432},
+		{`let user = {"name": "Ada"}; user.age`, nil},
+		{`let counter = {"next": fn(x) { x + 1 }}; counter.next(1)`, 2},
+		{`let users = [{"age": 1}, {"age": 2}]; users[1].age * 10`, 20},
+		{"let x = 5; x.foo", "INTEGER has no member 'foo'"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestMatchExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"match (0) { 0 => 1, _ => 2 }", 1},
+		{"match (5) { 0 => 1, _ => 2 }", 2},
+		{`match ("b") { "a" => 1, "b" => 2, _ => 3 }`, 2},
+		{"match (true) { true => 1, false => 0 }", 1},
+		{"match (15) { x if x > 10 => x * 2, x => x }", 30},
+		{"match (5) { x if x > 10 => x * 2, x => x }", 5},
+		{"match ([1, 2, 3]) { [] => 0, [first, ...rest] => first + rest.len() }", 3},
+		{"match ([]) { [] => 0, [first, ...rest] => first }", 0},
+		{"match ([1, 2]) { [a] => a, [a, b] => a + b, _ => 0 }", 3},
+		{"match ([1, [2, 3]]) { [a, [b, c]] => a + b + c }", 6},
+		{"match ([1, 2, 3]) { [a, b] => 0, _ => 9 }", 9},
+		{`match ({"type": "user", "age": 36}) { {"type": "admin"} => 0, {"type": "user", "age": a} => a }`, 36},
+		{`match ({"name": "Ada"}) { {name, age} => 0, {name} => name.len() }`, 3},
+		{"match (7) { 1..5 => 1, 6..<10 => 2, _ => 3 }", 2},
+		{"match (10) { 6..<10 => 2, _ => 3 }", 3},
+		{"match (2.5) { 0..3 => 1, _ => 2 }", 1},
+		{`match ("x") { n: int => 1, s: string => 2 }`, 2},
+		{"match (1.5) { n: number => 1, _ => 2 }", 1},
+		{"match ([1]) { _: hash => 1, _: array => 2 }", 2},
+		{"let f = fn(x) { match (x) { 0 => { return 10; }, _ => 20 } }; f(0)", 10},
+		{"let n = 3; match (n) { 3 => { n = 4; }, _ => 0 }; n", 4},
+		{"let x = 1; match (5) { x => x }; x", 1},
+		{"let x = 1; match (5) { x => { x = 10; } }; x", 1},
+		{"fn f() { let n = 0; match (1) { _ => { n = 5; } }; n } f()", 5},
+		{"match (5) { 0 => 1 }", "no match arm matched value: 5"},
+		{"match (5) { x: widget => 1 }", "unknown type in pattern: widget"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestDestructuringLet(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let [a, b] = [1, 2]; a * 10 + b", 12},
+		{"let [first, ...rest] = [1, 2, 3]; first + rest.len()", 3},
+		{"let [x, ...rest] = [1]; rest.len()", 0},
+		{`let {name, age} = {"name": "Ada", "age": 36}; age`, 36},
+		{`let {age: years} = {"age": 36}; years`, 36},
+		{`let {name, age = 18} = {"name": "Ada"}; age`, 18},
+		{`let {address: {city, zip = 0}} = {"address": {"city": "London"}}; zip`, 0},
+		{"let [a, b = 5] = [1]; a + b", 6},
+		{"let [a, [b, c]] = [1, [2, 3]]; a + b + c", 6},
+		{`let [user, host] = "ada@example.com".split("@"); host.len()`, 11},
+		{"let [a, b] = [1, 2, 3];", "cannot destructure [1, 2, 3] with pattern [a, b]"},
+		{`let {name} = {"age": 1};`, "cannot destructure {age: 1} with pattern {name: name}"},
+		{"let [a] = 5;", "cannot destructure 5 with pattern [a]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestSpreadExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let a = [1, 2]; let b = [3]; [...a, ...b, 4]", "[1, 2, 3, 4]"},
+		{"[0, ...1..3]", "[0, 1, 2, 3]"},
+		{`[..."ab"]`, "[a, b]"},
+		{"let add = fn(a, b, c) { a + b + c }; let args = [2, 3]; add(1, ...args)", "6"},
+		{`let defaults = {"x": 0, "y": 0}; {...defaults, "x": 1}`, "{x: 1, y: 0}"},
+		{`let a = {"x": 1}; let b = {"y": 2}; {...a, ...b, "z": 3}`, "{x: 1, y: 2, z: 3}"},
+		{`{"x": 1, ...{"x": 2}}`, "{x: 2}"},
+		{"let a = [1]; let b = [...a]; b.push(2); a", "[1]"},
+		{`[...{"a": 1}]`, "cannot spread HASH here, hashes can only be spread into hash literals"},
+		{"[...5]", "cannot spread INTEGER"},
+		{"{...[1]}", "cannot spread ARRAY into a hash"},
+		{"...[1]", "spread (...) is only allowed in array literals, hash literals and call arguments"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestConstants(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const x = 5; x * 2", 10},
+		{"const x = 5; let f = fn() { let x = 1; x = 2; x }; f() + x", 7},
+		{"const [a, b] = [1, 2]; a + b", 3},
+		{"const x = 5; x = 6;", "cannot assign to constant: x"},
+		{"const x = 5; x += 1;", "cannot assign to constant: x"},
+		{"const x = 5; let f = fn() { x = 1; }; f();", "cannot assign to constant: x"},
+		{"const x = 5; let x = 6;", "cannot redeclare constant: x"},
+		{"const [a, b] = [1, 2]; b = 3;", "cannot assign to constant: b"},
+		{"const i = 0; for (let j = 0; j < 1; i++) { }", "cannot assign to constant: i"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestFreeze(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		line     int
+	}{
+		{"let a = freeze([1, 2]); a.len()", "2", 0},
+		{"let a = [1]; freeze(a); isFrozen(a)", "true", 0},
+		{"isFrozen([1])", "false", 0},
+		{"freeze(5)", "5", 0},
+		{"let a = freeze([1]);\na.push(2);", "cannot push to a frozen array", 2},
+		{"let a = freeze([[1], [2]]);\nlet inner = a[0];\narrays.pop(inner);", "cannot pop from a frozen array", 3},
+		{`let h = freeze({"a": {"b": 1}}); hashes.delete(h.a, "b");`, "cannot delete from a frozen hash", 1},
+		{"let a = freeze([1]); let b = [...a]; b.push(2); b", "[1, 2]", 0},
+		{"const a = [1]; a.push(2); a", "[1, 2]", 0},
+		{"let a = [1]; a.push(a); freeze(a); isFrozen(a)", "true", 0},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			if errObj.Line() != tt.line {
+				t.Errorf("wrong error line for %q. expected=%d, got=%d", tt.input, tt.line, errObj.Line())
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestRecordTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"type Point { x, y }; Point(1, 2)", "Point { x: 1, y: 2 }"},
+		{"type Point { x, y }; let p = Point(1, 2); p.x + p.y", "3"},
+		{"type Point { x: int, y: int = 0 }; Point(5)", "Point { x: 5, y: 0 }"},
+		{`type User { name: string, tags = [] }; let u = User("ada"); u.tags.push(1); User("bob").tags`, "[]"},
+		{"type Point { x, y }; Point(1, 2) == Point(1, 2)", "true"},
+		{"type Point { x, y }; Point(1, 2) == Point(2, 1)", "false"},
+		{"type Point { x, y }; Point(1, 2) === Point(1, 2)", "false"},
+		{"type A { x }; type B { x }; A(1) == B(1)", "false"},
+		{"type Point { x, y }; Point(1, 2) is Point", "true"},
+		{"type Point { x, y }; type Size { w, h }; Point(1, 2) is Size", "false"},
+		{"5 is int", "true"},
+		{`"a" is number`, "false"},
+		{"type Point { x, y }; type Line { from: Point, to: Point }; Line(Point(0, 0), Point(1, 1)).to.x", "1"},
+		{"type Point { x, y }; let {x, y} = Point(3, 4); x * y", "12"},
+		{`type Point { x, y }; match (Point(0, 5)) { p: Point if p.x == 0 => "y axis", _ => "other" }`, "y axis"},
+		{"type Point { x, y }; Point", "type Point { x, y }"},
+		{"type Point { x: int }; Point(\"a\")", "field x of Point must be int, got STRING"},
+		{"type Point { x }; type Line { from: Point }; Line(5)", "field from of Line must be Point, got INTEGER"},
+		{"type Point { x, y }; Point(1)", "missing field y for Point"},
+		{"type Point { x }; Point(1, 2)", "wrong number of arguments to Point. got=2, want at most 1"},
+		{"type Point { x }; Point(1).z", "Point has no field 'z'"},
+		{"type Point { x: vector }", "unknown type: vector"},
+		{"type Point { x, x }", "duplicate field x in type Point"},
+		{"type Line { from: Point }", "unknown type: Point"},
+		{"5 is widget", "unknown type: widget"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestEnums(t *testing.T) {
+	colors := "enum Color { Red, Green, Blue }; "
+	shapes := "enum Shape { Circle(r), Rect(w, h) }; "
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{colors + "Color.Green", "Color.Green"},
+		{colors + "Color", "enum Color { Red, Green, Blue }"},
+		{"enum Status { Ok = 200, Created, NotFound = 404 }; Status", "enum Status { Ok = 200, Created, NotFound = 404 }"},
+		{"enum Status { Ok = 200, Created, NotFound = 404 }; Status.Created.value", "201"},
+		{colors + "Color.Blue.value", "2"},
+		{colors + "Color.Blue.name", "Blue"},
+		{colors + "Color.from(1)", "Color.Green"},
+		{colors + `Color.from("Blue")`, "Color.Blue"},
+		{colors + "Color.from(7)", "null"},
+		{colors + "Color.variants", "[Color.Red, Color.Green, Color.Blue]"},
+		{colors + `let names = []; for (c in Color) { names.push(c.name); } names`, "[Red, Green, Blue]"},
+		{colors + "Color.Red == Color.Red", "true"},
+		{colors + "Color.Red === Color.Red", "true"},
+		{colors + "Color.Red == Color.Blue", "false"},
+		{colors + "Color.Red is Color", "true"},
+		{colors + shapes + "Color.Red is Shape", "false"},
+		{colors + `let next = {Color.Red: Color.Green, Color.Green: Color.Blue}; next[Color.Red]`, "Color.Green"},
+		{shapes + "Shape.Circle", "Shape.Circle(r)"},
+		{shapes + "Shape.Rect(2, 3)", "Shape.Rect(2, 3)"},
+		{shapes + "Shape.Rect(2, 3).h", "3"},
+		{shapes + "Shape.Circle(1) == Shape.Circle(1)", "true"},
+		{shapes + "Shape.Circle(1) == Shape.Circle(2)", "false"},
+		{shapes + "let area = fn(s) { match (s) { Shape.Circle(r) => 3 * r * r, Shape.Rect(w, h) => w * h } }; [area(Shape.Circle(2)), area(Shape.Rect(2, 5))]", "[12, 10]"},
+		{shapes + "match (Shape.Rect(0, 4)) { Shape.Rect(0, h) => h, Shape.Rect => -1, _ => 0 }", "4"},
+		{colors + `match (Color.Blue) { Color.Red => "stop", Color.Green => "go", _ => "other" }`, "other"},
+		{colors + "match (Color.Red) { c: Color => c.value, _ => -1 }", "0"},
+		{"enum Color { Red, Red }", "duplicate variant Red in enum Color"},
+		{`enum Color { Red = "a" }`, "value of variant Red must be INTEGER, got STRING"},
+		{"enum Color { Red = 1, Green = 1 }", "variants Red and Green of enum Color have the same value: 1"},
+		{colors + "Color.Purple", "enum Color has no variant 'Purple'"},
+		{colors + "Color.Red.hue", "Color.Red has no field 'hue'"},
+		{colors + "Color.from(true)", "argument to `from` must be INTEGER or STRING, got BOOLEAN"},
+		{shapes + "Shape.Rect(1)", "wrong number of arguments to Shape.Rect. got=1, want=2"},
+		{shapes + "match (Shape.Circle(1)) { Shape.Square => 1, _ => 0 }", "enum Shape has no variant 'Square'"},
+		{shapes + "match (Shape.Circle(1)) { Shape.Circle(a, b) => 1, _ => 0 }", "variant Shape.Circle has 1 fields, pattern has 2"},
+		{"let x = 1; match (1) { x.Red => 1, _ => 0 }", "x is not an enum, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x: int = 5; x", "5"},
+		{"let x: number = 1.5; x", "1.500000"},
+		{"let x: any = [1]; x", "[1]"},
+		{"let f = fn(a: int, b: int) -> int { a + b }; f(1, 2)", "3"},
+		{"let f = fn(a: number) -> string { return \"ok\"; }; f(2.5)", "ok"},
+		{"let f = fn(a: fn) { a(1) }; f(fn(x) { x + 1 })", "2"},
+		{"type Point { x, y }; let f = fn(p: Point) -> int { p.x }; f(Point(4, 5))", "4"},
+		{"enum Color { Red }; let f = fn(c: Color) -> Color { c }; f(Color.Red)", "Color.Red"},
+		{"let f = fn() -> null { let x = 1; }; f()", "null"},
+		{"let f = fn(a, b) { b }; f(1, 2, 3)", "2"},
+		{`let x: int = "a";`, "value of x must be int, got STRING"},
+		{"let x: float = 1;", "value of x must be float, got INTEGER"},
+		{"let x: widget = 1;", "unknown type: widget"},
+		{`let f = fn(a: int) { a }; f("a")`, "argument a must be int, got STRING"},
+		{"type Point { x, y }; let f = fn(p: Point) { p }; f(1)", "argument p must be Point, got INTEGER"},
+		{`let f = fn() -> int { "a" }; f()`, "return value must be int, got STRING"},
+		{`let f = fn(a) -> string { return a; }; f(1)`, "return value must be string, got INTEGER"},
+		{"let f = fn(a, b) { a }; f(1)", "wrong number of arguments. got=1, want=2"},
+		{"let xs: array<int> = [1, 2]; xs", "[1, 2]"},
+		{`let xs: array<int> = [1, "a"]; xs`, "value of xs must be array<int>, got ARRAY"},
+		{`let h: hash<string, array<int>> = {"a": [1]}; h["a"][0]`, "1"},
+		{`let h: hash<string, int> = {"a": "b"}; h`, "value of h must be hash<string, int>, got HASH"},
+		{"let xs: array<int, int> = [];", "wrong number of type arguments for array. got=2, want=1"},
+		{"let xs: array<widget> = [];", "unknown type: widget"},
+		{"fn first<T>(xs: array<T>) -> T { let x: T = xs[0]; x } first([3, 4])", "3"},
+		{`fn first<T>(xs: array<T>) -> T { xs[0] } first(["a"])`, "a"},
+		{"fn first<T>(xs: array<T>) -> T { xs[0] } first(1)", "argument xs must be array<T>, got INTEGER"},
+		{"let f = fn<T>(x: T) { x }; f", "fn<T>(x: T) {\nx\n}"},
+		{`let f = fn(xs: array<string>) { xs }; f(["a", 1])`, "argument xs must be array<string>, got ARRAY"},
+		{`match ([1, "a"]) { xs: array<int> => "ints", xs: array => "mixed" }`, "mixed"},
+		{"type Tree { value: int, children: array<Tree> = [] }; Tree(1, [Tree(2)]).children[0].value", "2"},
+		{"type Tree { value: int, children: array<Tree> = [] }; Tree(1, [2])", "field children of Tree must be array<Tree>, got ARRAY"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestInterfaces(t *testing.T) {
+	shapes := "interface Shape { area(); } type Circle { r }; type Square { side }; "
+	circle := "impl Shape for Circle { fn area(self) { 3 * self.r * self.r } } "
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{shapes + circle + "Circle(2).area()", "12"},
+		{shapes + circle + "Circle(2) is Shape", "true"},
+		{shapes + circle + "Square(2) is Shape", "false"},
+		{shapes + "impl Square { fn area(self) { self.side * self.side } } Square(3) is Shape", "true"},
+		{shapes + circle + "let total = fn(s: Shape) { s.area() }; total(Circle(1))", "3"},
+		{shapes + circle + "match (Circle(1)) { s: Shape => s.area(), _ => 0 }", "3"},
+		{shapes + circle + "Shape", "interface Shape { area(); }"},
+		{"type Point { x, y }; impl Point { fn sum(self, z) { self.x + self.y + z } } Point(1, 2).sum(3)", "6"},
+		{"type Point { x }; impl Point { fn x(self) { 0 } } Point(5).x", "5"},
+		{"enum Color { Red, Green }; impl Color { fn next(self) { Color.from(self.value + 1) } } Color.Red.next()", "Color.Green"},
+		{`type Tag { name }; impl Stringer for Tag { fn toString(self) { "<" + self.name + ">" } } Tag("b")`, "<b>"},
+		{`type Point { x }; impl Point { fn toString(self) { "p" } } [Point(1)]`, "[p]"},
+		{"type Money { cents }; impl Comparable for Money { fn compare(self, other) { self.cents - other.cents } } [Money(1) < Money(2), Money(1) > Money(2)]", "[true, false]"},
+		{"type Bag { items }; impl Iterable for Bag { fn iter(self) { self.items } } let sum = 0; for (x in Bag([1, 2, 3])) { sum += x; } sum", "6"},
+		{"type Bag { items }; impl Bag { fn iter(self) { self.items } } [...Bag([1, 2])]", "[1, 2]"},
+		{shapes + circle + "let f = fn(s: Shape) { s }; f(Square(1))", "argument s must be Shape, got Square"},
+		{shapes + "impl Shape for Circle { fn perimeter(self) { 1 } }", "Circle does not implement Shape: missing method area"},
+		{shapes + "impl Shape for Circle { fn area(self, by) { 1 } }", "Circle does not implement Shape: method area takes 1 arguments, want 0"},
+		{shapes + "impl Circle { fn area() { 1 } }", "method area of Circle must take the value it's called on as its first parameter"},
+		{shapes + circle + "impl Circle { fn area(self) { 1 } }", "duplicate method area for Circle"},
+		{"let x = 1; impl x { fn f(self) { 1 } }", "cannot implement methods for INTEGER, only record types and enums have methods"},
+		{"type Point { x }; impl Point for Point { fn f(self) { 1 } }", "Point is not an interface, got RECORD_TYPE"},
+		{"interface Shape { area(); area(); }", "duplicate method area in interface Shape"},
+		{"type Money { cents }; impl Money { fn compare(self, other) { true } } Money(1) < Money(2)", "compare must return INTEGER, got BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestOperatorOverloading(t *testing.T) {
+	vec := "type Vec { x, y }; impl Vec { fn __add__(self, o) { Vec(self.x + o.x, self.y + o.y) } fn __mul__(self, k) { Vec(self.x * k, self.y * k) } fn __eq__(self, o) { self.x == o.x } fn __lt__(self, o) { self.x < o.x } fn __index__(self, i) { match (i) { 0 => self.x, _ => self.y } } } "
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{vec + "(Vec(1, 2) + Vec(3, 4)).y", "6"},
+		{vec + "(Vec(1, 2) * 3).x", "3"},
+		{vec + "Vec(1, 2) == Vec(1, 5)", "true"},
+		{vec + "Vec(1, 2) != Vec(1, 5)", "false"},
+		{vec + "[Vec(1, 2) < Vec(2, 0), Vec(3, 0) < Vec(2, 0)]", "[true, false]"},
+		{vec + "Vec(1, 2) === Vec(1, 2)", "false"},
+		{vec + "Vec(7, 8)[1]", "8"},
+		{vec + "let v = Vec(1, 1); v += Vec(2, 2); v.x", "3"},
+		{`type Money { cents }; impl Money { fn __str__(self) { "$" + self.cents } } Money("5")`, "$5"},
+		{"type Money { cents }; impl Money { fn __sub__(self, o) { Money(self.cents - o.cents) } fn __div__(self, n) { self.cents / n } fn __mod__(self, n) { self.cents % n } } [(Money(5) - Money(2)).cents, Money(9) / 3, Money(9) % 4]", "[3, 3, 1]"},
+		{"type Money { cents }; impl Money { fn __gt__(self, o) { self.cents > o.cents } } Money(5) > Money(2)", "true"},
+		{vec + "Vec(1, 2) - Vec(1, 2)", "unknown operator: RECORD - RECORD"},
+		{vec + "const v = Vec(1, 1); v += Vec(2, 2);", "cannot assign to constant: v"},
+		{"type Vec { x }; impl Vec { fn __eq__(self, o) { 1 } } Vec(1) == Vec(1)", "__eq__ must return BOOLEAN, got INTEGER"},
+		{"type Vec { x }; Vec(1)[0]", "index operator not supported: RECORD"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	counter := "fn* counter(n) { while (true) { yield n; n += 1; } } "
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{counter + "let it = counter(5); [it.next(), it.next(), it.next()]", "[5, 6, 7]"},
+		{"fn* two() { yield 1; yield 2; } let it = two(); [it.next(), it.next(), it.next(), it.next()]", "[1, 2, null, null]"},
+		{"fn* two() { yield 1; return 5; yield 2; } [...two()]", "[1]"},
+		{"fn* one() { yield 1; } let it = one(); it.next(); if (it.next()) { \"more\" } else { \"done\" }", "done"},
+		{counter + "let sum = 0; for (x in counter(1)) { if (x > 4) { break; } sum += x; } sum", "10"},
+		{counter + "counter(1).map(fn(x) { x * x }).filter(fn(x) { x % 2 == 1 }).take(3).toArray()", "[1, 9, 25]"},
+		{counter + "counter(0).skip(3).take(2).toArray()", "[3, 4]"},
+		{counter + `counter(0).zip(["a", "b"]).toArray()`, "[[0, a], [1, b]]"},
+		{`iter.enumerate(["a", "b"]).toArray()`, "[[0, a], [1, b]]"},
+		{"iter.take(1..1000000000000, 3).toArray()", "[1, 2, 3]"},
+		{counter + "arrays.from(counter(7).take(2))", "[7, 8]"},
+		{"fn* nat(n) { yield n; for (x in nat(n + 1)) { yield x; } } nat(0).take(200).toArray().len()", "200"},
+		{"type Bag { items }; impl Iterable for Bag { fn iter(self) { self.items.filter(fn(x) { x > 1 }) } } [...Bag(iter.take([1, 2, 3], 3))]", "[2, 3]"},
+		{"fn* gen() { let got = yield 1; yield got; } [...gen()]", "[1, null]"},
+		{"let g = fn*(a: int) -> iterator { yield a; }; g(1)", "<iterator generator>"},
+		{"fn* g() { yield 1; } g", "fn*() {\nyield 1\n}"},
+		{"fn* g() { yield 1; } g() is iterator", "true"},
+		{`fn* bad() { yield 1; 1 + "a"; } [...bad()]`, "type mismatch: INTEGER + STRING"},
+		{"fn* g(a: int) { yield a; } g(\"a\")", "argument a must be int, got STRING"},
+		{"let g = fn*() -> int { yield 1; }; g()", "return value must be int, got ITERATOR"},
+		{"iter.take(5, 1)", "cannot iterate over INTEGER"},
+		{"iter.next([1])", "argument must be ITERATOR"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	workers := "fn worker(jobs, out) { for (j in jobs) { send(out, j * 2); } } "
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn add(a, b) { a + b } sync.wait(spawn add(1, 2))", "3"},
+		{"fn add(a, b) { a + b } sync.waitAll([spawn add(1, 2), spawn add(3, 4)])", "[3, 7]"},
+		{workers + "let jobs = chan(10); let out = chan(10); let ts = [spawn worker(jobs, out), spawn worker(jobs, out)]; for (i in 1..4) { send(jobs, i); } close(jobs); sync.waitAll(ts); close(out); let sum = 0; for (x in out) { sum += x; } sum", "20"},
+		{"let c = chan(); spawn fn() { send(c, 5) }(); recv(c)", "5"},
+		{"let c = chan(2); send(c, 1); send(c, 2); close(c); [recv(c), recv(c), recv(c)]", "[1, 2, null]"},
+		{"let c = chan(1); c", "<channel 0/1>"},
+		{"let count = [0]; let m = sync.mutex(); fn inc() { m.lock(); count.push(count.pop() + 1); m.unlock(); } let ts = []; for (i in 1..20) { ts.push(spawn inc()); } sync.waitAll(ts); count[0]", "20"},
+		{"let c = chan(1); send(c, 7); select { v = recv(c) => { v * 2 } _ => { 0 } }", "14"},
+		{"let c = chan(); select { recv(c) => { 1 } _ => { 0 } }", "0"},
+		{"let c = chan(); close(c); select { v = recv(c) => { v } }", "null"},
+		{"let calls = []; let c = chan(1); send(c, 4); close(c); fn get() { calls.push(1); c } select { v = recv(get()) => { [v, calls.len()] } }", "[4, 1]"},
+		{"let c = chan(1); close(c); select { send(c, 1) => { 1 } }", "send on closed channel"},
+		{"let c = chan(1); select { send(c, 3) => { recv(c) } }", "3"},
+		{"select { recv(1) => { 1 } }", "select cases need a CHANNEL, got INTEGER"},
+		{"let c = chan(); close(c); close(c)", "close of closed channel"},
+		{"let c = chan(); close(c); send(c, 1)", "send on closed channel"},
+		{"chan(-1)", "capacity can't be negative, got -1"},
+		{"sync.mutex().unlock()", "unlock of unlocked mutex"},
+		{"let m = sync.mutex(); if (m.lock()) { 1 } else { 2 }", "2"},
+		{`fn bad() { 1 + "a" } sync.wait(spawn bad())`, "type mismatch: INTEGER + STRING"},
+		{"fn f() { 1 } let t = spawn f(); t.wait(); t", "<task done>"},
+		{"chan() is channel", "true"},
+		{"let xs = []; fn add(i) { xs.push(i); xs.len() } let ts = []; for (i in 1..50) { ts.push(spawn add(i)); } sync.waitAll(ts); [xs.len(), xs.sum()]", "[50, 1275]"},
+		{"let xs = []; fn add(i) { xs.push(i); xs.sort(fn(a, b) { b - a }); } let ts = []; for (i in 1..50) { ts.push(spawn add(i)); } sync.waitAll(ts); [xs.len(), xs.sum(), xs[0]]", "[50, 1275, 50]"},
+		{`let b = strings.builder(); fn add(i) { b.write("ab", "cd"); } let ts = []; for (i in 1..50) { ts.push(spawn add(i)); } sync.waitAll(ts); [b.len(), b.build().split("abcd").len()]`, "[200, 51]"},
+		{"let es = []; for (i in 0..49) { es.push([i, i]); } let h = hashes.fromEntries(es); fn drop(i) { hashes.delete(h, i); hashes.has(h, i) } let ts = []; for (i in 0..49) { ts.push(spawn drop(i)); } [sync.waitAll(ts).contains(true), hashes.size(h)]", "[false, 0]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestPanicsInTasks(t *testing.T) {
+	input := `fn work(n) {
+	let x = n;
+	boom()
+}
+let ts = [];
+for (i in 1..20) { ts.push(spawn work(i)); }
+let total = 0;
+for (i in 1..200) { total += i; }
+sync.waitAll(ts)`
+
+	l := lexer.New(input, logger.NewLogger(), false)
+	p := parser.New(l, logger.NewLogger(), false)
+	program := p.ParseProgram()
+	env := object.NewEnvironment()
+	modules.Register(env)
+	env.Set("boom", &object.Builtin{Fn: func(args ...object.Object) object.Object { panic("boom") }})
+
+	evaluated := Eval(program, env)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("expected an error, got %T (%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "internal error: boom" {
+		t.Errorf("wrong error message. expected=%q, got=%q", "internal error: boom", errObj.Message)
+	}
+	if errObj.Line() != 3 {
+		t.Errorf("wrong error line. expected=3, got=%d", errObj.Line())
+	}
+}
+
+// Builtins that make their own false and null values still branch like the shared ones
+func TestFreshFalsyValues(t *testing.T) {
+	input := `[if (no()) { 1 } else { 2 }, if (none()) { 1 } else { 2 }, !no(), !none(), match (1) { x if no() => 1, _ => 2 }]`
+
+	l := lexer.New(input, logger.NewLogger(), false)
+	p := parser.New(l, logger.NewLogger(), false)
+	program := p.ParseProgram()
+	env := object.NewEnvironment()
+	env.Set("no", &object.Builtin{Fn: func(args ...object.Object) object.Object { return &object.Boolean{Value: false} }})
+	env.Set("none", &object.Builtin{Fn: func(args ...object.Object) object.Object { return &object.Null{} }})
+
+	if evaluated := Eval(program, env); evaluated.Inspect() != "[2, 2, true, true, 2]" {
+		t.Errorf("wrong result. expected=%q, got=%q", "[2, 2, true, true, 2]", evaluated.Inspect())
+	}
+}
+
+func TestTimers(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let n = []; time.after(5, fn() { n.push(1) }); time.sleep(50); n.len()", "1"},
+		{"let n = []; let t = time.every(2, fn() { n.push(1); if (n.len() == 3) { t.cancel(); } }); time.sleep(100); n.len()", "3"},
+		{"let c = chan(2); time.after(5, fn() { send(c, 1) }); time.after(1, fn() { send(c, 2) }); c", "<channel 2/2>"},
+		{"let c = chan(1); time.after(5, fn() { send(c, 1) }); return c;", "<channel 1/1>"},
+		{"let t = time.after(1000, fn() { 1 }); [t.cancel(), t.cancel()]", "[true, false]"},
+		{"let t = time.after(1000, fn() { 1 }); t.cancel(); if (t.cancel()) { \"again\" } else { \"once\" }", "once"},
+		{"if (time.sleep(0)) { 1 } else { 2 }", "2"},
+		{"let t = time.after(1000, fn() { 1 }); t.cancel(); t", "<timer stopped>"},
+		{"let t = time.every(1000, fn() { 1 }); let ok = t is timer; t.cancel(); ok", "true"},
+		{"let t = time.after(1, fn() { 1 }); time.sleep(20); t", "<timer stopped>"},
+		{`time.after(1, fn() { 1 + "a" })`, "type mismatch: INTEGER + STRING"},
+		{`time.after(1, fn() { 1 + "a" }); time.sleep(50); 5`, "type mismatch: INTEGER + STRING"},
+		{"time.sleep(-1)", "can't sleep for a negative duration"},
+		{"time.every(0, fn() { 1 })", "interval must be positive"},
+		{"time.after(5, 1)", "second argument must be FUNCTION"},
+		{"time.cancel(1)", "argument must be TIMER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestDateTime(t *testing.T) {
+	date := "let t = time.date(2026, 10, 17, 9, 30); "
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{date + "t", "2026-10-17T09:30:00Z"},
+		{date + "t + time.hours(3)", "2026-10-17T12:30:00Z"},
+		{date + "time.minutes(30) + t", "2026-10-17T10:00:00Z"},
+		{date + "t - time.days(1)", "2026-10-16T09:30:00Z"},
+		{date + "time.date(2026, 10, 18) - t", "14h30m0s"},
+		{date + "t += time.seconds(5); t", "2026-10-17T09:30:05Z"},
+		{date + "[t < time.date(2027, 1, 1), t > time.date(2027, 1, 1), t == t.in(\"Asia/Tokyo\")]", "[true, false, true]"},
+		{date + "[t.year(), t.month(), t.day(), t.hour(), t.minute(), t.second()]", "[2026, 10, 17, 9, 30, 0]"},
+		{date + "[t.weekday(), t.zone(), t.in(\"Europe/Paris\").zone()]", "[Saturday, UTC, Europe/Paris]"},
+		{date + "t.in(\"America/New_York\")", "2026-10-17T05:30:00-04:00"},
+		{date + "t.format(\"Jan 2, 2006 at 3:04pm\")", "Oct 17, 2026 at 9:30am"},
+		{date + "t.format(\"DateOnly\")", "2026-10-17"},
+		{date + "t.addDate(0, 3, 0)", "2027-01-17T09:30:00Z"},
+		{date + "t.unix()", "1792229400"},
+		{"time.fromUnix(1792229400)", "2026-10-17T09:30:00Z"},
+		{"time.date(2026, 13, 40)", "month 13 is out of range, want 1 to 12"},
+		{"time.date(2026, 4, 31)", "day 31 is out of range for 2026-04, want 1 to 30"},
+		{"[time.date(2024, 2, 29), time.date(2026, 12, 31, 23, 59, 59)]", "[2024-02-29T00:00:00Z, 2026-12-31T23:59:59Z]"},
+		{"time.date(2025, 2, 29)", "day 29 is out of range for 2025-02, want 1 to 28"},
+		{"time.date(2026, 1, 1, 24)", "hour 24 is out of range, want 0 to 23"},
+		{"time.date(2026, 1, 1, 0, -1)", "minute -1 is out of range, want 0 to 59"},
+		{"time.date(2026, 1, 1, 0, 0, 60)", "second 60 is out of range, want 0 to 59"},
+		{`time.parse("2006-01-02 15:04", "2026-03-01 08:00", "Europe/Paris")`, "2026-03-01T08:00:00+01:00"},
+		{`time.parse("RFC3339", "2026-10-17T09:30:00+02:00").in("UTC")`, "2026-10-17T07:30:00Z"},
+		{date + "let h = {t: 1}; h[t.in(\"Asia/Tokyo\")]", "1"},
+		{date + "t.truncate(time.days(1))", "2026-10-17T00:00:00Z"},
+		{date + "t.round(time.hours(1))", "2026-10-17T10:00:00Z"},
+		{`time.duration("1h30m").round(time.hours(1))`, "2h0m0s"},
+		{"time.now() - time.now() < time.seconds(1)", "true"},
+		{"time.date(2000, 1, 1).since() > time.days(365)", "true"},
+		{"[time.hours(1.5), time.milliseconds(250), time.minutes(90) * 2, 2 * time.seconds(3), time.hours(1) / 4]", "[1h30m0s, 250ms, 3h0m0s, 6s, 15m0s]"},
+		{"[time.hours(3) / time.hours(2), time.minutes(90).totalHours(), time.seconds(2).totalMilliseconds()]", "[1.500000, 1.500000, 2000]"},
+		{"time.minutes(100) % time.hours(1)", "40m0s"},
+		{"time.hours(1) == time.minutes(60)", "true"},
+		{"time.hours(1) is duration", "true"},
+		{date + "t + t", "unknown operator: DATETIME + DATETIME"},
+		{date + "t + 1", "type mismatch: DATETIME + INTEGER"},
+		{"time.hours(1) / 0", "division by zero: 1h0m0s / 0"},
+		{`time.parse("DateOnly", "17/10/2026")`, `can't parse "17/10/2026" as DateOnly`},
+		{`time.date(2026, 1, 1).in("Mars/Olympus")`, "unknown time zone: Mars/Olympus"},
+		{`time.duration("soon")`, `can't parse "soon" as a duration`},
+		{"time.date(2026, 1)", "wrong number of arguments"},
+		{"time.hours(1).truncate(time.hours(0))", "duration must be positive"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestAssignmentInFunctionsIsLocal(t *testing.T) {
+	input := `
+	let count = 0;
+	let increment = fn() { count = count + 1; count += 1; count };
+	[increment(), increment(), count]
+	`
+
+	evaluated := testEval(input)
+	if evaluated.Inspect() != "[2, 2, 0]" {
+		t.Errorf("functions should assign their own variables. expected=%q, got=%q", "[2, 2, 0]", evaluated.Inspect())
+	}
+}
+
+func TestStructuralEquality(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`"abc" == "abc"`, true},
+		{`"ab" + "c" == "abc"`, true},
+		{`"abc" != "abd"`, true},
+		{`[1, 2] == [1, 2]`, true},
+		{`[1, 2] == [2, 1]`, false},
+		{`[1, [2, 3]] == [1, [2, 3]]`, true},
+		{`[1, 2] != [1, 2, 3]`, true},
+		{`[1, 2.0] == [1, 2]`, true},
+		{`{"a": 1, "b": [1]} == {"b": [1], "a": 1}`, true},
+		{`{"a": 1} == {"a": 2}`, false},
+		{`{"a": 1} == {"b": 1}`, false},
+		{`[1] == "1"`, false},
+		{`let a = [1]; let b = [1]; a == b`, true},
+		{`mod arrays: [push]; let a = [1]; arrays.push(a, a); let b = [1]; arrays.push(b, b); a == b`, true},
+		{`let f = fn() { 1 }; f == f`, true},
+		{`fn() { 1 } == fn() { 1 }`, false},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestIdentityOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected bool
+	}{
+		{`1 === 1`, true},
+		{`1 === 1.0`, false},
+		{`"ab" + "c" === "abc"`, true},
+		{`[1, 2] === [1, 2]`, false},
+		{`[1, 2] !== [1, 2]`, true},
+		{`let a = [1, 2]; let b = a; a === b`, true},
+		{`let a = {"x": 1}; let b = {"x": 1}; a === b`, false},
+		{`let a = {"x": 1}; a === a`, true},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		testBooleanObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestHashInsertionOrder(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"z": 1, "a": 2, "m": 3}`, "{z: 1, a: 2, m: 3}"},
+		{`{3: "c", 1: "a", 2: "b"}`, "{3: c, 1: a, 2: b}"},
+		{`{"a": 1, "b": 2, "a": 3}`, "{a: 3, b: 2}"},
+		{`let log = []; mod arrays: [push]; let f = fn(x) { arrays.push(log, x); x }; {f("a"): f(1), f("b"): f(2)}; log`, "[a, 1, b, 2]"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong inspect output for %q. got=%q, want=%q", tt.input, evaluated.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestCompositeHashKeys(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`{[1, 2]: 5}[[1, 2]]`, 5},
+		{`let k = [1, "a", [true]]; {k: 7}[[1, "a", [true]]]`, 7},
+		{`{[1, 2]: 5}[[2, 1]]`, nil},
+		{`{[]: 3}[[]]`, 3},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		integer, ok := tt.expected.(int)
+		if ok {
+			testIntegerObject(t, evaluated, int64(integer))
+		} else {
+			testNullObject(t, evaluated)
+		}
+	}
+
+	evaluated := testEval(`{[fn() { 1 }]: 1}`)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "unusable as hash key: ARRAY" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+func TestForInLoops(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let sum = 0; for (x in [1, 2, 3]) { sum += x; } sum;", 6},
+		{"let sum = 0; for (i, x in [10, 20, 30]) { sum += i * x; } sum;", 80},
+		{`let out = ""; for (k, v in {"a": "1", "b": "2"}) { out = out + k + v; } out;`, "a1b2"},
+		{`let out = ""; for (k in {"a": 1, "b": 2}) { out = out + k; } out;`, "ab"},
+		{`let out = ""; for (ch in "text") { out = ch + out; } out;`, "txet"},
+		{`let n = 0; for (i, ch in "abc") { n = i; } n;`, 2},
+		{"let sum = 0; for (i in 0..10) { sum += i; } sum;", 55},
+		{"let sum = 0; for (i in 0..<10) { sum += i; } sum;", 45},
+		{"let n = 5; let sum = 0; for (i in 0..<n step 2) { sum += i; } sum;", 6},
+		{"let last = 0; for (i in 10..0 step -5) { last = i; } last;", 0},
+		{"let count = 0; for (i in 3..0) { count += 1; } count;", 4},
+		{"let sum = 0; for (i in 0..100) { if (i == 4) { break; } sum += i; } sum;", 6},
+		{"let sum = 0; for (x in [1, 2, 3, 4]) { if (x == 2) { continue; } sum += x; } sum;", 8},
+		{"let f = fn() { for (x in [1, 2, 3]) { if (x == 2) { return x * 10; } } return 0; }; f();", 20},
+		{"let sum = 0; for (x in [[1, 2], [3]]) { for (y in x) { if (y == 2) { break; } sum += y; } } sum;", 4},
+		{"mod arrays: [push]; let a = [1, 2]; for (x in a) { arrays.push(a, x); } a;", []int64{1, 2, 1, 2}},
+		{"let sum = 0; for (let i = 0; i < 5; i++) { if (i == 1) { continue; } sum += i; } sum;", 9},
+		{"for (x in 5) { x; }", "cannot iterate over INTEGER"},
+		{"0..10 step 0", "range step cannot be 0"},
+		{`0.."a"`, "range bounds must be INTEGER, got STRING"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case []int64:
+			arr, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("object is not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if len(arr.Elements) != len(expected) {
+				t.Errorf("array has wrong num of elements. got=%d", len(arr.Elements))
+				continue
+			}
+			for i, el := range expected {
+				testIntegerObject(t, arr.Elements[i], el)
+			}
+		case string:
+			switch result := evaluated.(type) {
+			case *object.String:
+				if result.Value != expected {
+					t.Errorf("String has wrong value. got=%q, want=%q", result.Value, expected)
+				}
+			case *object.Error:
+				if result.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, result.Message)
+				}
+			default:
+				t.Errorf("object is not String or Error. got=%T (%+v)", evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestRangeInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0..10", "0..10"},
+		{"let n = 3; 0..<n", "0..<3"},
+		{"10..0", "10..0"},
+		{"0..10 step 2", "0..10 step 2"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong inspect output. expected=%q, got=%q", tt.expected, evaluated.Inspect())
+		}
+	}
+}
@@ -0,0 +1,155 @@
+package evaluator
+
+import (
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/ajtroup1/clear/ast"
+	"github.com/ajtroup1/clear/object"
+	"github.com/ajtroup1/clear/token"
+)
+
+func init() {
+	object.CallFunction = func(fn object.Object, args ...object.Object) object.Object {
+		return applyFunction(fn, args)
+	}
+	object.Iter = toIterator
+	object.Arithmetic = func(operator string, left, right object.Object) object.Object {
+		if left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ {
+			return evalIntegerInfixExpression(operator, left, right, object.Position{})
+		}
+		return evalFloatInfixExpression(operator, left, right, object.Position{})
+	}
+}
+
+// Returned from yield once nobody can ask the generator for another value, unwinding its body like an error
+var errGeneratorStopped = &object.Error{Message: "generator stopped"}
+
+// The body of a generator runs on its own goroutine, which waits at every yield until the next value is asked for
+// Only one side runs at a time, so the body can use the environment like any other function does,
+// and a long chain of generators doesn't grow the stack of whoever iterates over it
+type generator struct {
+	fn  *object.Function
+	env *object.Environment
+
+	values  chan object.Object // what the body yields, closed when it returns
+	resume  chan struct{}      // lets the body run on to its next yield
+	stopped chan struct{}      // closed once the iterator is garbage collected
+	stop    sync.Once
+
+	started, finished bool
+}
+
+func newGenerator(fn *object.Function, env *object.Environment) *object.Iterator {
+	g := &generator{
+		fn:      fn,
+		env:     env,
+		values:  make(chan object.Object),
+		resume:  make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	env.SetYield(g.yield)
+
+	it := &object.Iterator{Name: "generator", Next: g.next, Position: fn.Position}
+	// A generator left waiting at a yield would keep its goroutine forever, so it is stopped once nothing can resume it
+	runtime.SetFinalizer(it, func(*object.Iterator) {
+		g.stop.Do(func() { close(g.stopped) })
+	})
+	return it
+}
+
+func (g *generator) next() object.Object {
+	if g.finished {
+		return nil
+	}
+
+	if g.started {
+		g.resume <- struct{}{}
+	} else {
+		g.started = true
+		go g.run()
+	}
+
+	value, ok := <-g.values
+	if !ok || isError(value) {
+		g.finished = true
+	}
+	if !ok {
+		return nil
+	}
+	return value
+}
+
+func (g *generator) run() {
+	defer close(g.values)
+
+	result := Eval(g.fn.Body, g.env)
+	if isError(result) && result != errGeneratorStopped {
+		select {
+		case g.values <- result:
+		case <-g.stopped:
+		}
+	}
+}
+
+func (g *generator) yield(value object.Object) object.Object {
+	select {
+	case g.values <- value:
+	case <-g.stopped:
+		return errGeneratorStopped
+	}
+
+	select {
+	case <-g.resume:
+		return NULL
+	case <-g.stopped:
+		return errGeneratorStopped
+	}
+}
+
+func evalYieldExpression(node *ast.YieldExpression, env *object.Environment) object.Object {
+	value := Eval(node.Value, env)
+	if isError(value) {
+		return value
+	}
+
+	result, ok := env.Yield(value)
+	if !ok {
+		return newError("yield is only allowed inside a generator, declared with fn*", node.Token.Line, node.Token.Col)
+	}
+	return result
+}
+
+// Anything a for-in loop can go over, as an iterator. Iterators are returned as they are and ranges step lazily
+// Everything else is collected up front, since only iterators can pause between values
+func toIterator(obj object.Object) object.Object {
+	switch obj := obj.(type) {
+	case *object.Iterator:
+		return obj
+	case *object.Range:
+		return obj.Iterator()
+	}
+
+	values := []object.Object{}
+	err := iterate(obj, token.Token{Line: obj.Line(), Col: obj.Col()}, func(_, value object.Object) bool {
+		values = append(values, value)
+		return true
+	})
+	if err != nil {
+		return err
+	}
+
+	i := 0
+	return &object.Iterator{
+		Name:     strings.ToLower(string(obj.Type())),
+		Position: object.Position{Line: obj.Line(), Col: obj.Col()},
+		Next: func() object.Object {
+			if i >= len(values) {
+				return nil
+			}
+			i++
+			return values[i-1]
+		},
+	}
+}
@@ -0,0 +1,191 @@
+package evaluator
+
+import (
+	"github.com/ajtroup1/clear/ast"
+	"github.com/ajtroup1/clear/object"
+)
+
+func init() {
+	object.CallMethod = callMethod
+}
+
+// Declares an interface, binding it like any other value so it can be used as a type
+func evalInterfaceStatement(node *ast.InterfaceStatement, env *object.Environment) object.Object {
+	iface := &object.Interface{
+		Name:     node.Name.Value,
+		Position: object.Position{Line: node.Token.Line, Col: node.Token.Col},
+	}
+
+	for _, decl := range node.Methods {
+		for _, method := range iface.Methods {
+			if method.Name == decl.Name.Value {
+				return newError("duplicate method %s in interface %s", decl.Name.Token.Line, decl.Name.Token.Col, decl.Name.Value, iface.Name)
+			}
+		}
+
+		method := object.InterfaceMethod{Name: decl.Name.Value, Params: []string{}}
+		for _, param := range decl.Parameters {
+			method.Params = append(method.Params, param.Value)
+		}
+		iface.Methods = append(iface.Methods, method)
+	}
+
+	env.Set(node.Name.Value, iface)
+	return nil
+}
+
+// Attaches methods to a record type or enum. Every method needs a parameter for the value it's called on
+// Naming an interface checks the type satisfies it once the methods are attached, including ones from earlier impls
+func evalImplStatement(node *ast.ImplStatement, env *object.Environment) object.Object {
+	line, col := node.Target.Token.Line, node.Target.Token.Col
+
+	target, ok := env.Get(node.Target.Value)
+	if !ok {
+		return newError("identifier not found: %s", line, col, node.Target.Value)
+	}
+
+	var methods map[string]*object.Function
+	switch target := target.(type) {
+	case *object.RecordType:
+		if target.Methods == nil {
+			target.Methods = map[string]*object.Function{}
+		}
+		methods = target.Methods
+	case *object.Enum:
+		if target.Methods == nil {
+			target.Methods = map[string]*object.Function{}
+		}
+		methods = target.Methods
+	default:
+		return newError("cannot implement methods for %s, only record types and enums have methods", line, col, target.Type())
+	}
+
+	var iface *object.Interface
+	if node.Interface != nil {
+		obj, ok := lookupType(node.Interface.Value, env)
+		if !ok {
+			return newError("identifier not found: %s", node.Interface.Token.Line, node.Interface.Token.Col, node.Interface.Value)
+		}
+		if iface, ok = obj.(*object.Interface); !ok {
+			return newError("%s is not an interface, got %s", node.Interface.Token.Line, node.Interface.Token.Col, node.Interface.Value, obj.Type())
+		}
+	}
+
+	for _, decl := range node.Methods {
+		name := decl.Name.Value
+		if _, exists := methods[name]; exists {
+			return newError("duplicate method %s for %s", decl.Name.Token.Line, decl.Name.Token.Col, name, node.Target.Value)
+		}
+
+		method := Eval(decl.Value, env).(*object.Function)
+		if len(method.Parameters) == 0 {
+			return newError("method %s of %s must take the value it's called on as its first parameter", decl.Name.Token.Line, decl.Name.Token.Col, name, node.Target.Value)
+		}
+		methods[name] = method
+	}
+
+	if iface != nil {
+		if reason := iface.Unsatisfied(methods); reason != "" {
+			return newError("%s does not implement %s: %s", line, col, node.Target.Value, iface.Name, reason)
+		}
+	}
+
+	return nil
+}
+
+// Type names are looked up like variables, falling back to the builtin protocols
+func lookupType(name string, env *object.Environment) (object.Object, bool) {
+	if obj, ok := env.Get(name); ok {
+		return obj, true
+	}
+	if protocol, ok := object.Protocols[name]; ok {
+		return protocol, true
+	}
+	return nil, false
+}
+
+// Calls a method attached to the type of the receiver, which is passed as its first argument
+func callMethod(receiver object.Object, name string, args ...object.Object) (object.Object, bool) {
+	method, ok := object.MethodsOf(receiver)[name]
+	if !ok {
+		return nil, false
+	}
+	result := applyFunction(method, append([]object.Object{receiver}, args...))
+	if result == nil {
+		return NULL, true
+	}
+	return result, true
+}
+
+// Binds a method to the receiver, the same way module functions are bound to strings and arrays
+func lookupUserMethod(receiver object.Object, name string) (*object.Builtin, bool) {
+	if _, ok := object.MethodsOf(receiver)[name]; !ok {
+		return nil, false
+	}
+
+	return &object.Builtin{
+		Fn: func(args ...object.Object) object.Object {
+			result, _ := callMethod(receiver, name, args...)
+			return result
+		},
+	}, true
+}
+
+// Orders values implementing Comparable with their compare method
+func evalCompareMethod(operator string, left, right object.Object, pos object.Position) object.Object {
+	result, _ := callMethod(left, "compare", right)
+	if isError(result) {
+		return result
+	}
+
+	order, ok := result.(*object.Integer)
+	if !ok {
+		return newError("compare must return INTEGER, got %s", pos.Line, pos.Col, typeName(result))
+	}
+
+	if operator == "<" {
+		return nativeBoolToBooleanObject(order.Value < 0)
+	}
+	return nativeBoolToBooleanObject(order.Value > 0)
+}
+
+// Operators on values whose type defines the matching method, like __add__ for +, call that method with the right operand
+// Reports whether the left operand overloads the operator
+func evalOperatorMethod(operator string, left, right object.Object, literal string, pos object.Position, env *object.Environment) (object.Object, bool) {
+	base := operator
+	switch {
+	case operator == "!=":
+		base = "=="
+	case isCompoundOperator(operator):
+		base = operator[:1]
+	}
+
+	name, ok := object.OperatorMethods[base]
+	if !ok || object.MethodsOf(left)[name] == nil {
+		return nil, false
+	}
+	if isCompoundOperator(operator) && env.IsConst(literal) {
+		return newError("cannot assign to constant: %s", pos.Line, pos.Col, literal), true
+	}
+
+	result, _ := callMethod(left, name, right)
+	if isError(result) {
+		return result, true
+	}
+
+	switch base {
+	case "==", "<", ">":
+		boolean, ok := result.(*object.Boolean)
+		if !ok {
+			return newError("%s must return BOOLEAN, got %s", pos.Line, pos.Col, name, typeName(result)), true
+		}
+		if operator == "!=" {
+			return nativeBoolToBooleanObject(!boolean.Value), true
+		}
+	}
+
+	if isCompoundOperator(operator) {
+		env.Assign(literal, result)
+	}
+	return result, true
+}
@@ -0,0 +1,215 @@
+package evaluator
+
+import (
+	"github.com/ajtroup1/clear/ast"
+	"github.com/ajtroup1/clear/object"
+)
+
+// Declares a record type, binding it like any other value so it can be called as a constructor
+// Field types must be builtin type names, an existing record type or enum, or the type being declared
+func evalTypeStatement(node *ast.TypeStatement, env *object.Environment) object.Object {
+	recordType := &object.RecordType{
+		Name:     node.Name.Value,
+		Env:      env,
+		Position: object.Position{Line: node.Token.Line, Col: node.Token.Col},
+	}
+
+	// Field types may refer to the type being declared, `children: array<Node>`
+	scope := object.NewEnclosedEnvironment(env)
+	scope.Set(node.Name.Value, recordType)
+
+	for _, decl := range node.Fields {
+		if recordType.FieldIndex(decl.Name.Value) >= 0 {
+			return newError("duplicate field %s in type %s", decl.Name.Token.Line, decl.Name.Token.Col, decl.Name.Value, node.Name.Value)
+		}
+
+		field := object.RecordField{Name: decl.Name.Value, Default: decl.Default}
+		if decl.TypeName != nil {
+			if err := checkTypeExpr(decl.TypeName, scope); err != nil {
+				return err
+			}
+			field.TypeName = decl.TypeName
+		}
+		recordType.Fields = append(recordType.Fields, field)
+	}
+
+	env.Set(node.Name.Value, recordType)
+	return nil
+}
+
+// Arguments fill the fields in order, missing trailing fields use their defaults
+// Typed fields are checked here, so a record never holds a value of the wrong type
+func constructRecord(recordType *object.RecordType, args []object.Object) object.Object {
+	if len(args) > len(recordType.Fields) {
+		return newError("wrong number of arguments to %s. got=%d, want at most %d", 0, 0, recordType.Name, len(args), len(recordType.Fields))
+	}
+
+	record := &object.Record{RecordType: recordType, Values: make([]object.Object, len(recordType.Fields))}
+
+	for i, field := range recordType.Fields {
+		var value object.Object
+		switch {
+		case i < len(args):
+			value = args[i]
+		case field.Default != nil:
+			value = Eval(field.Default, recordType.Env)
+			if isError(value) {
+				return value
+			}
+		default:
+			return newError("missing field %s for %s", 0, 0, field.Name, recordType.Name)
+		}
+
+		if field.TypeName != nil && !annotationMatches(value, field.TypeName, recordType.Env) {
+			return newError("field %s of %s must be %s, got %s", 0, 0, field.Name, recordType.Name, field.TypeName.String(), typeName(value))
+		}
+
+		record.Values[i] = value
+	}
+
+	return record
+}
+
+// Evaluates `value is Type`, where Type is a builtin type name, a record type, an enum or an interface
+func evalIsExpression(node *ast.InfixExpression, env *object.Environment) object.Object {
+	left := Eval(node.Left, env)
+	if isError(left) {
+		return left
+	}
+
+	ident, ok := node.Right.(*ast.Identifier)
+	if !ok {
+		return newError("right side of `is` must be a type name, got %s", node.Token.Line, node.Token.Col, node.Right.String())
+	}
+
+	matches, known := typeMatches(left, ident.Value, env)
+	if !known {
+		return newError("unknown type: %s", ident.Token.Line, ident.Token.Col, ident.Value)
+	}
+
+	return nativeBoolToBooleanObject(matches)
+}
+
+// Reports whether the value has the named type, and whether the name is a type at all
+// Builtin type names (int, string, ...) take priority over variables, and `any` matches everything
+func typeMatches(value object.Object, name string, env *object.Environment) (matches bool, known bool) {
+	if name == "any" {
+		return true, true
+	}
+	if types, ok := patternTypes[name]; ok {
+		for _, t := range types {
+			if value.Type() == t {
+				return true, true
+			}
+		}
+		return false, true
+	}
+
+	if obj, ok := lookupType(name, env); ok {
+		switch obj := obj.(type) {
+		case *object.RecordType:
+			record, isRecord := value.(*object.Record)
+			return isRecord && record.RecordType == obj, true
+		case *object.Enum:
+			enumValue, isEnumValue := value.(*object.EnumValue)
+			return isEnumValue && enumValue.Variant.Enum == obj, true
+		case *object.TypeParameter:
+			return true, true
+		case *object.Interface:
+			return obj.SatisfiedBy(value), true
+		}
+	}
+
+	return false, false
+}
+
+// Records and enum values are described by their type's name rather than RECORD or ENUM_VALUE
+func typeName(obj object.Object) string {
+	switch obj := obj.(type) {
+	case *object.Record:
+		return obj.RecordType.Name
+	case *object.EnumValue:
+		return obj.Variant.Enum.Name
+	}
+	return string(obj.Type())
+}
+
+// The number of type arguments each builtin type takes, array<T> and hash<K, V>
+var typeArities = map[string]int{
+	"array": 1,
+	"hash":  2,
+}
+
+// Reports the first unknown type name in an annotation, or a type given the wrong number of type arguments
+// Arrays and hashes may also be written without type arguments, which leaves their contents unchecked
+func checkTypeExpr(annotation *ast.TypeExpr, env *object.Environment) *object.Error {
+	line, col := annotation.Token.Line, annotation.Token.Col
+	if _, known := typeMatches(NULL, annotation.Name, env); !known {
+		return newError("unknown type: %s", line, col, annotation.Name)
+	}
+	if want := typeArities[annotation.Name]; len(annotation.Args) > 0 && len(annotation.Args) != want {
+		return newError("wrong number of type arguments for %s. got=%d, want=%d", line, col, annotation.Name, len(annotation.Args), want)
+	}
+
+	for _, arg := range annotation.Args {
+		if err := checkTypeExpr(arg, env); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reports whether the value has the annotated type, checking every element of an array
+// and every key and value of a hash against their type arguments
+func annotationMatches(value object.Object, annotation *ast.TypeExpr, env *object.Environment) bool {
+	if matches, _ := typeMatches(value, annotation.Name, env); !matches {
+		return false
+	}
+
+	switch value := value.(type) {
+	case *object.Array:
+		if len(annotation.Args) == 1 {
+			for _, el := range value.Items() {
+				if !annotationMatches(el, annotation.Args[0], env) {
+					return false
+				}
+			}
+		}
+	case *object.Hash:
+		if len(annotation.Args) == 2 {
+			for _, pair := range value.OrderedPairs() {
+				if !annotationMatches(pair.Key, annotation.Args[0], env) || !annotationMatches(pair.Value, annotation.Args[1], env) {
+					return false
+				}
+			}
+		}
+	}
+	return true
+}
+
+// Enforces a type annotation at runtime, describing the value as what in the error
+// Errors have no position, so errors during a call are placed at the call
+func checkType(value object.Object, annotation *ast.TypeExpr, env *object.Environment, what string) *object.Error {
+	if err := checkTypeExpr(annotation, env); err != nil {
+		return err
+	}
+	if !annotationMatches(value, annotation, env) {
+		return newError("%s must be %s, got %s", 0, 0, what, annotation.String(), typeName(value))
+	}
+	return nil
+}
+
+// Called on function entry, once the arguments are bound: annotated parameters need an argument of their type
+// Annotations are resolved in the call's scope, where the function's type parameters are bound
+func checkArguments(fn *object.Function, args []object.Object, env *object.Environment) *object.Error {
+	for i, typeName := range fn.ParameterTypes {
+		if typeName == nil {
+			continue
+		}
+		if err := checkType(args[i], typeName, env, "argument "+fn.Parameters[i].Value); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
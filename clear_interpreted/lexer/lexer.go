@@ -54,7 +54,16 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.EQ, Literal: literal}
+			tok = token.Token{Type: token.EQ, Literal: literal, Line: l.line, Col: l.col - 1}
+			if l.peekChar() == '=' {
+				l.readChar()
+				tok = token.Token{Type: token.IDENTICAL, Literal: literal + string(l.ch), Line: l.line, Col: l.col - 2}
+			}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			literal := string(ch) + string(l.ch)
+			tok = token.Token{Type: token.FAT_ARROW, Literal: literal, Line: l.line, Col: l.col - 1}
 		} else {
 			tok = l.newToken(token.ASSIGN, l.ch)
 		}
@@ -63,12 +72,12 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.INC, Literal: literal}
+			tok = token.Token{Type: token.INC, Literal: literal, Line: l.line, Col: l.col - 1}
 		} else if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.PLUS_EQ, Literal: literal}
+			tok = token.Token{Type: token.PLUS_EQ, Literal: literal, Line: l.line, Col: l.col - 1}
 		} else {
 			tok = l.newToken(token.PLUS, l.ch)
 		}
@@ -77,12 +86,17 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.DEC, Literal: literal}
+			tok = token.Token{Type: token.DEC, Literal: literal, Line: l.line, Col: l.col - 1}
 		} else if l.peekChar() == '=' {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.MINUS_EQ, Literal: literal}
+			tok = token.Token{Type: token.MINUS_EQ, Literal: literal, Line: l.line, Col: l.col - 1}
+		} else if l.peekChar() == '>' {
+			ch := l.ch
+			l.readChar()
+			literal := string(ch) + string(l.ch)
+			tok = token.Token{Type: token.ARROW, Literal: literal, Line: l.line, Col: l.col - 1}
 		} else {
 			tok = l.newToken(token.MINUS, l.ch)
 		}
@@ -91,7 +105,11 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.NOT_EQ, Literal: literal}
+			tok = token.Token{Type: token.NOT_EQ, Literal: literal, Line: l.line, Col: l.col - 1}
+			if l.peekChar() == '=' {
+				l.readChar()
+				tok = token.Token{Type: token.NOT_IDENTICAL, Literal: literal + string(l.ch), Line: l.line, Col: l.col - 2}
+			}
 		} else {
 			tok = l.newToken(token.BANG, l.ch)
 		}
@@ -102,7 +120,7 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.DIV_EQ, Literal: literal}
+			tok = token.Token{Type: token.DIV_EQ, Literal: literal, Line: l.line, Col: l.col - 1}
 		} else {
 			tok = l.newToken(token.SLASH, l.ch)
 		}
@@ -111,10 +129,12 @@ func (l *Lexer) NextToken() token.Token {
 			ch := l.ch
 			l.readChar()
 			literal := string(ch) + string(l.ch)
-			tok = token.Token{Type: token.MULT_EQ, Literal: literal}
+			tok = token.Token{Type: token.MULT_EQ, Literal: literal, Line: l.line, Col: l.col - 1}
 		} else {
 			tok = l.newToken(token.ASTERISK, l.ch)
 		}
+	case '%':
+		tok = l.newToken(token.MODULO, l.ch)
 	case '<':
 		tok = l.newToken(token.LT, l.ch)
 	case '>':
@@ -126,7 +146,19 @@ func (l *Lexer) NextToken() token.Token {
 	case ',':
 		tok = l.newToken(token.COMMA, l.ch)
 	case '.':
-		tok = l.newToken(token.DOT, l.ch)
+		if l.peekChar() == '.' {
+			l.readChar()
+			tok = token.Token{Type: token.RANGE, Literal: "..", Line: l.line, Col: l.col - 1}
+			if l.peekChar() == '<' {
+				l.readChar()
+				tok = token.Token{Type: token.RANGE_EXCL, Literal: "..<", Line: l.line, Col: l.col - 2}
+			} else if l.peekChar() == '.' {
+				l.readChar()
+				tok = token.Token{Type: token.ELLIPSIS, Literal: "...", Line: l.line, Col: l.col - 2}
+			}
+		} else {
+			tok = l.newToken(token.DOT, l.ch)
+		}
 	case '{':
 		tok = l.newToken(token.LBRACE, l.ch)
 	case '}':
@@ -149,6 +181,11 @@ func (l *Lexer) NextToken() token.Token {
 		if isLetter(l.ch) {
 			tok.Literal = l.readIdentifier()
 			tok.Type = token.LookupIdent(tok.Literal, l.log, l.encounterCount)
+			// `fn*` declares a generator, the star is part of the keyword
+			if tok.Type == token.FUNCTION && l.ch == '*' {
+				l.readChar()
+				tok.Literal = "fn*"
+			}
 			tok.Line = l.line
 			tok.Col = l.col - len(tok.Literal)
 			l.Tokens = append(l.Tokens, tok)
@@ -234,7 +271,9 @@ func (l *Lexer) readNumber() (string, bool) {
 		l.readChar()
 	}
 
-	if l.ch == '.' {
+	// A dot only makes a float when a digit follows it, otherwise `0..10` would
+	// be read as the float `0.` followed by `.10`
+	if l.ch == '.' && isDigit(l.peekChar()) {
 		isFloat = true
 		l.readChar()
 		for isDigit(l.ch) {
@@ -144,3 +144,174 @@ if (5 < 10) {
 		}
 	}
 }
+
+func TestRangeTokens(t *testing.T) {
+	input := `0..10 0..<n 1.5 x === y !== z`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.INT, "0"},
+		{token.RANGE, ".."},
+		{token.INT, "10"},
+		{token.INT, "0"},
+		{token.RANGE_EXCL, "..<"},
+		{token.IDENT, "n"},
+		{token.FLOAT, "1.5"},
+		{token.IDENT, "x"},
+		{token.IDENTICAL, "==="},
+		{token.IDENT, "y"},
+		{token.NOT_IDENTICAL, "!=="},
+		{token.IDENT, "z"},
+		{token.EOF, ""},
+	}
+
+	log := logger.NewLogger()
+
+	l := New(input, log, false)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestMatchTokens(t *testing.T) {
+	input := `match (x) { [a, ...rest] => a, _ => 0 }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.MATCH, "match"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.LBRACKET, "["},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.ELLIPSIS, "..."},
+		{token.IDENT, "rest"},
+		{token.RBRACKET, "]"},
+		{token.FAT_ARROW, "=>"},
+		{token.IDENT, "a"},
+		{token.COMMA, ","},
+		{token.IDENT, "_"},
+		{token.FAT_ARROW, "=>"},
+		{token.INT, "0"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	log := logger.NewLogger()
+
+	l := New(input, log, false)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestTypeAnnotationTokens(t *testing.T) {
+	input := `fn(a: int) -> bool { a-- > 0 }`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FUNCTION, "fn"},
+		{token.LPAREN, "("},
+		{token.IDENT, "a"},
+		{token.COLON, ":"},
+		{token.IDENT, "int"},
+		{token.RPAREN, ")"},
+		{token.ARROW, "->"},
+		{token.IDENT, "bool"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "a"},
+		{token.DEC, "--"},
+		{token.GT, ">"},
+		{token.INT, "0"},
+		{token.RBRACE, "}"},
+		{token.EOF, ""},
+	}
+
+	log := logger.NewLogger()
+
+	l := New(input, log, false)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
+
+func TestGeneratorTokens(t *testing.T) {
+	input := `fn* count() { yield 1; } fn *`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.FUNCTION, "fn*"},
+		{token.IDENT, "count"},
+		{token.LPAREN, "("},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.YIELD, "yield"},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.RBRACE, "}"},
+		{token.FUNCTION, "fn"},
+		{token.ASTERISK, "*"},
+		{token.EOF, ""},
+	}
+
+	log := logger.NewLogger()
+
+	l := New(input, log, false)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q",
+				i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q",
+				i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/ajtroup1/clear/checker"
 	"github.com/ajtroup1/clear/errors"
 	"github.com/ajtroup1/clear/evaluator"
 	"github.com/ajtroup1/clear/logger"
@@ -24,6 +25,8 @@ func main() {
 	var debug bool
 	flag.BoolVar(&debug, "debug", false, "Debug mode")
 	flag.BoolVar(&debug, "d", false, "Debug mode (short)")
+	flag.BoolVar(&evaluator.CheckedArithmetic, "checked", false, "Error on integer overflow instead of wrapping around")
+	flag.BoolVar(&evaluator.StrictIndexing, "strict", false, "Error on out of range indexes instead of returning null")
 	flag.Parse()
 
 	args := flag.Args()
@@ -85,6 +88,20 @@ func runScript(filePath string, debug bool) {
 		os.Exit(1)
 	}
 
+	// Static checks are reported alongside the parser's errors and warnings
+	check := checker.New(lexer.Lines)
+	diagnostics := append(parser.Errors, check.Check(program)...)
+
+	// Errors found before evaluation stop the program from running at all
+	errs, warn := errors.HasErrors(lexer.Errors, diagnostics)
+	if errs {
+		fmt.Print(errors.ReportErrors(lexer.Errors, diagnostics))
+		os.Exit(1)
+	}
+	if warn {
+		fmt.Print(errors.ReportErrors(lexer.Errors, diagnostics))
+	}
+
 	if debug {
 		// Generate JSON representation of the parse tree
 		parseTreeJSON, err := json.MarshalIndent(program, "", "  ")
@@ -110,15 +127,6 @@ func runScript(filePath string, debug bool) {
 	evaluator.Init(log, debug, lexer.Lines)
 	evaluated := evaluator.Eval(program, env)
 
-	errs, warn := errors.HasErrors(lexer.Errors, parser.Errors)
-	if errs {
-		fmt.Print(errors.ReportErrors(lexer.Errors, parser.Errors))
-		os.Exit(1)
-	}
-	if warn {
-		fmt.Print(errors.ReportErrors(lexer.Errors, parser.Errors))
-	}
-
 	if evaluated != nil && evaluated.Type() == object.ERROR_OBJ {
 		fmt.Print(errors.ReportEvaluationError(evaluated.(*object.Error)))
 		os.Exit(1)
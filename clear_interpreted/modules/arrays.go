@@ -1,16 +1,28 @@
 package modules
 
-import "github.com/ajtroup1/clear/object"
-
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/ajtroup1/clear/object"
+)
+
+// Functions named for something done to the array (push, pop, insert, removeAt and sort) change it in place,
+// and are errors on frozen arrays. The others leave it as it is and return a new array or another value,
+// like sorted, which is sort for a copy of the array
 var ArraysBuiltins = map[string]*object.Builtin{
 	"len": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>"}, Return: "int"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
 			}
 			switch arg := args[0].(type) {
 			case *object.Array:
-				return &object.Integer{Value: int64(len(arg.Elements))}
+				return &object.Integer{Value: int64(len(arg.Items()))}
 			default:
 				return &object.Error{Message: "argument to `len` not supported"}
 			}
@@ -18,6 +30,7 @@ var ArraysBuiltins = map[string]*object.Builtin{
 	},
 
 	"push": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "T"}, Return: "array<T>", Variadic: true},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) < 2 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -28,13 +41,19 @@ var ArraysBuiltins = map[string]*object.Builtin{
 			}
 
 			arr := args[0].(*object.Array)
-			arr.Elements = append(arr.Elements, args[1:]...)
+			if object.IsFrozen(arr) {
+				return &object.Error{Message: "cannot push to a frozen array"}
+			}
+			arr.Update(func(elements []object.Object) []object.Object {
+				return append(elements, args[1:]...)
+			})
 
 			return arr
 		},
 	},
 
 	"pop": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>"}, Return: "T"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -45,19 +64,26 @@ var ArraysBuiltins = map[string]*object.Builtin{
 			}
 
 			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
-			if length == 0 {
-				return &object.Null{}
+			if object.IsFrozen(arr) {
+				return &object.Error{Message: "cannot pop from a frozen array"}
 			}
 
-			popped := arr.Elements[length-1]
-			arr.Elements = arr.Elements[:length-1]
+			var popped object.Object = object.NULL
+			arr.Update(func(elements []object.Object) []object.Object {
+				length := len(elements)
+				if length == 0 {
+					return elements
+				}
+				popped = elements[length-1]
+				return elements[: length-1 : length-1]
+			})
 
 			return popped
 		},
 	},
 
 	"first": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>"}, Return: "T"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -67,17 +93,18 @@ var ArraysBuiltins = map[string]*object.Builtin{
 				return &object.Error{Message: "first argument must be ARRAY"}
 			}
 
-			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
+			elements := args[0].(*object.Array).Items()
+			length := len(elements)
 			if length == 0 {
-				return &object.Null{}
+				return object.NULL
 			}
 
-			return arr.Elements[0]
+			return elements[0]
 		},
 	},
 
 	"rest": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>"}, Return: "array<T>"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -87,20 +114,21 @@ var ArraysBuiltins = map[string]*object.Builtin{
 				return &object.Error{Message: "first argument must be ARRAY"}
 			}
 
-			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
+			elements := args[0].(*object.Array).Items()
+			length := len(elements)
 			if length == 0 {
-				return &object.Null{}
+				return object.NULL
 			}
 
 			newElements := make([]object.Object, length-1)
-			copy(newElements, arr.Elements[1:length])
+			copy(newElements, elements[1:length])
 
 			return &object.Array{Elements: newElements}
 		},
 	},
 
 	"last": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>"}, Return: "T"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -110,17 +138,18 @@ var ArraysBuiltins = map[string]*object.Builtin{
 				return &object.Error{Message: "first argument must be ARRAY"}
 			}
 
-			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
+			elements := args[0].(*object.Array).Items()
+			length := len(elements)
 			if length == 0 {
-				return &object.Null{}
+				return object.NULL
 			}
 
-			return arr.Elements[length-1]
+			return elements[length-1]
 		},
 	},
 
 	"reverse": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>"}, Return: "array<T>"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -131,13 +160,14 @@ var ArraysBuiltins = map[string]*object.Builtin{
 			}
 
 			arr := args[0].(*object.Array)
-			length := len(arr.Elements)
+			elements := arr.Items()
+			length := len(elements)
 			if length == 0 {
 				return arr
 			}
 
 			newElements := make([]object.Object, length)
-			for i, el := range arr.Elements {
+			for i, el := range elements {
 				newElements[length-i-1] = el
 			}
 
@@ -146,6 +176,220 @@ var ArraysBuiltins = map[string]*object.Builtin{
 	},
 
 	"contains": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "T"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: "first argument must be ARRAY"}
+			}
+
+			arr := args[0].(*object.Array)
+
+			for _, el := range arr.Items() {
+				if object.Equals(el, args[1]) {
+					return object.TRUE
+				}
+			}
+
+			return object.FALSE
+		},
+	},
+
+	"from": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any"}, Return: "array"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			// Collects anything iterable, pulling every value out of iterators and generators
+			return collect(args[0])
+		},
+	},
+
+	// Sorts the array in place, from smallest to largest, and returns it
+	// Numbers, strings, datetimes and durations are ordered naturally, and values whose type implements Comparable
+	// (or __lt__) with their method, while anything else, including numbers mixed with strings, is an error
+	// sort(arr, compare) orders the elements with compare(a, b) instead, which returns a negative integer, zero or a positive integer
+	// Elements that are equal keep their order
+	// compare mustn't use the array being sorted, since other tasks are kept from it until the sort is done
+	"sort": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "fn"}, Return: "array<T>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			arr, compare, err := sortArgs(args)
+			if err != nil {
+				return err
+			}
+			if object.IsFrozen(arr) {
+				return &object.Error{Message: "cannot sort a frozen array"}
+			}
+
+			// Sorting while the array is held means nothing pushed by another task in the meantime is lost
+			// The elements are sorted as a copy, so a compare function failing partway leaves the array as it was
+			var failed object.Object
+			arr.Update(func(elements []object.Object) []object.Object {
+				sorted := append([]object.Object(nil), elements...)
+				if failed = sortElements(sorted, compare); failed != nil {
+					return elements
+				}
+				return sorted
+			})
+			if failed != nil {
+				return failed
+			}
+
+			return arr
+		},
+	},
+
+	// Like sort, but returns a sorted copy and leaves the array as it is
+	"sorted": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "fn"}, Return: "array<T>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			arr, compare, err := sortArgs(args)
+			if err != nil {
+				return err
+			}
+
+			elements := append([]object.Object(nil), arr.Items()...)
+			if err := sortElements(elements, compare); err != nil {
+				return err
+			}
+
+			return &object.Array{Elements: elements}
+		},
+	},
+
+	// slice(arr, start, end?) copies the elements from start up to but not including end, like arr[start:end]
+	// Negative indexes count from the end and indexes past either end are clamped
+	"slice": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "int", "int"}, Return: "array<T>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: "first argument must be ARRAY"}
+			}
+
+			items := args[0].(*object.Array).Items()
+			length := len(items)
+
+			bounds := []int{0, length}
+			for i, arg := range args[1:] {
+				index, ok := arg.(*object.Integer)
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("slice indices must be INTEGER, got %s", arg.Type())}
+				}
+				bound := int(index.Value)
+				if bound < 0 {
+					bound += length
+				}
+				bounds[i] = min(max(bound, 0), length)
+			}
+
+			start, end := bounds[0], max(bounds[0], bounds[1])
+			elements := make([]object.Object, end-start)
+			copy(elements, items[start:end])
+
+			return &object.Array{Elements: elements}
+		},
+	},
+
+	// insert(arr, index, values...) inserts the values before the element at index, in place, and returns the array
+	// The index can be the length of the array to add to the end, and negative indexes count from the end
+	"insert": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "int", "T"}, Return: "array<T>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) < 3 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: "first argument must be ARRAY"}
+			}
+
+			if args[1].Type() != object.INTEGER_OBJ {
+				return &object.Error{Message: "second argument must be INTEGER"}
+			}
+
+			arr := args[0].(*object.Array)
+			if object.IsFrozen(arr) {
+				return &object.Error{Message: "cannot insert into a frozen array"}
+			}
+
+			var err object.Object
+			arr.Update(func(elements []object.Object) []object.Object {
+				// Inserting at the length adds to the end, while -1 is still the last element, so values go before it
+				index := args[1].(*object.Integer).Value
+				length := int64(len(elements))
+				if index < -length || index > length {
+					err = &object.Error{Message: fmt.Sprintf("index out of range: %d (length %d)", index, length)}
+					return elements
+				}
+				if index < 0 {
+					index += length
+				}
+
+				inserted := make([]object.Object, 0, len(elements)+len(args)-2)
+				inserted = append(inserted, elements[:index]...)
+				inserted = append(inserted, args[2:]...)
+				return append(inserted, elements[index:]...)
+			})
+			if err != nil {
+				return err
+			}
+
+			return arr
+		},
+	},
+
+	// Removes the element at the index in place and returns it, negative indexes count from the end
+	"removeAt": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "int"}, Return: "T"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: "first argument must be ARRAY"}
+			}
+
+			if args[1].Type() != object.INTEGER_OBJ {
+				return &object.Error{Message: "second argument must be INTEGER"}
+			}
+
+			arr := args[0].(*object.Array)
+			if object.IsFrozen(arr) {
+				return &object.Error{Message: "cannot remove from a frozen array"}
+			}
+
+			var removed object.Object
+			arr.Update(func(elements []object.Object) []object.Object {
+				index, err := arrayIndex(args[1].(*object.Integer).Value, len(elements))
+				if err != nil {
+					removed = err
+					return elements
+				}
+
+				removed = elements[index]
+				remaining := make([]object.Object, 0, len(elements)-1)
+				remaining = append(remaining, elements[:index]...)
+				return append(remaining, elements[index+1:]...)
+			})
+
+			return removed
+		},
+	},
+
+	// The index of the first element equal to the value, or -1 when there's none
+	"indexOf": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "T"}, Return: "int"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -157,13 +401,554 @@ var ArraysBuiltins = map[string]*object.Builtin{
 
 			arr := args[0].(*object.Array)
 
-			for _, el := range arr.Elements {
-				if el.Inspect() == args[1].Inspect() {
-					return &object.Boolean{Value: true}
+			for i, el := range arr.Items() {
+				if object.Equals(el, args[1]) {
+					return &object.Integer{Value: int64(i)}
+				}
+			}
+
+			return &object.Integer{Value: -1}
+		},
+	},
+
+	// concat(arrays...) makes a new array of the elements of each array in turn
+	"concat": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "array<T>"}, Return: "array<T>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) < 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			elements := []object.Object{}
+			for i, arg := range args {
+				arr, ok := arg.(*object.Array)
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("argument %d must be ARRAY, got %s", i+1, arg.Type())}
+				}
+				elements = append(elements, arr.Items()...)
+			}
+
+			return &object.Array{Elements: elements}
+		},
+	},
+
+	// Joins the elements into a string with the delimiter between them, elements that aren't strings are joined as they're printed
+	"join": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array", "string"}, Return: "string"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: "first argument must be ARRAY"}
+			}
+
+			if args[1].Type() != object.STRING_OBJ {
+				return &object.Error{Message: "second argument must be STRING"}
+			}
+
+			return &object.String{Value: joinElements(args[0].(*object.Array), args[1].(*object.String).Value)}
+		},
+	},
+
+	// A new array without the elements equal to one before them
+	"unique": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>"}, Return: "array<T>"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: "first argument must be ARRAY"}
+			}
+
+			seen := newValueSet()
+			elements := []object.Object{}
+			for _, el := range args[0].(*object.Array).Items() {
+				if seen.add(el) {
+					elements = append(elements, el)
+				}
+			}
+
+			return &object.Array{Elements: elements}
+		},
+	},
+
+	// The set operations treat arrays as sets, so each returns a new array without duplicates,
+	// in the order the elements first appear, a's before b's
+	// Elements are the same when they're equal, like for ==
+
+	// union(a, b) has the elements in either array
+	"union": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "array<T>"}, Return: "array<T>"},
+		Fn: func(args ...object.Object) object.Object {
+			return setOperation(args, func(inB bool) bool { return true }, true)
+		},
+	},
+
+	// intersection(a, b) has the elements in both arrays
+	"intersection": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "array<T>"}, Return: "array<T>"},
+		Fn: func(args ...object.Object) object.Object {
+			return setOperation(args, func(inB bool) bool { return inB }, false)
+		},
+	},
+
+	// difference(a, b) has the elements of a that aren't in b
+	"difference": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "array<T>"}, Return: "array<T>"},
+		Fn: func(args ...object.Object) object.Object {
+			return setOperation(args, func(inB bool) bool { return !inB }, false)
+		},
+	},
+
+	// flatten(arr, depth?) makes a new array with the elements of nested arrays in their place
+	// Only one level is flattened unless depth is given, and a negative depth flattens every level
+	"flatten": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array", "int"}, Return: "array", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: "first argument must be ARRAY"}
+			}
+
+			depth := 1
+			if len(args) == 2 {
+				if args[1].Type() != object.INTEGER_OBJ {
+					return &object.Error{Message: "second argument must be INTEGER"}
+				}
+				depth = int(args[1].(*object.Integer).Value)
+			}
+
+			return flattenArray(args[0].(*object.Array), depth, map[*object.Array]bool{})
+		},
+	},
+
+	// zip(arrays...) makes an array of arrays, holding the first element of each array, then the second, and so on
+	// It's as long as the shortest array
+	"zip": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array", "array"}, Return: "array<array>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) < 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			arrays := make([][]object.Object, len(args))
+			length := -1
+			for i, arg := range args {
+				arr, ok := arg.(*object.Array)
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("argument %d must be ARRAY, got %s", i+1, arg.Type())}
+				}
+				arrays[i] = arr.Items()
+				if length < 0 || len(arrays[i]) < length {
+					length = len(arrays[i])
+				}
+			}
+
+			tuples := make([]object.Object, length)
+			for i := range tuples {
+				tuple := make([]object.Object, len(arrays))
+				for j, elements := range arrays {
+					tuple[j] = elements[i]
+				}
+				tuples[i] = &object.Array{Elements: tuple}
+			}
+
+			return &object.Array{Elements: tuples}
+		},
+	},
+
+	// chunk(arr, size) splits the array into new arrays of size elements, the last of which can be shorter
+	"chunk": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "int"}, Return: "array<array<T>>"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: "first argument must be ARRAY"}
+			}
+
+			if args[1].Type() != object.INTEGER_OBJ {
+				return &object.Error{Message: "second argument must be INTEGER"}
+			}
+
+			elements := args[0].(*object.Array).Items()
+			size := int(args[1].(*object.Integer).Value)
+			if size <= 0 {
+				return &object.Error{Message: fmt.Sprintf("chunk size must be positive, got %d", size)}
+			}
+
+			chunks := []object.Object{}
+			for start := 0; start < len(elements); start += size {
+				end := min(start+size, len(elements))
+				chunk := make([]object.Object, end-start)
+				copy(chunk, elements[start:end])
+				chunks = append(chunks, &object.Array{Elements: chunk})
+			}
+
+			return &object.Array{Elements: chunks}
+		},
+	},
+
+	// range(start, end, step?) makes an array of the integers from start up to but not including end, like start..<end
+	// A negative step counts down from start to just above end
+	"range": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int", "int", "int"}, Return: "array<int>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			bounds := []int64{0, 0, 1}
+			for i, arg := range args {
+				integer, ok := arg.(*object.Integer)
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("argument %d must be INTEGER, got %s", i+1, arg.Type())}
 				}
+				bounds[i] = integer.Value
+			}
+
+			start, end, step := bounds[0], bounds[1], bounds[2]
+			if step == 0 {
+				return &object.Error{Message: "range step cannot be 0"}
+			}
+
+			elements := []object.Object{}
+			for i := start; (step > 0 && i < end) || (step < 0 && i > end); i += step {
+				elements = append(elements, &object.Integer{Value: i})
 			}
 
-			return &object.Boolean{Value: false}
+			return &object.Array{Elements: elements}
+		},
+	},
+
+	// Adds up the numbers in the array, giving an integer when they're all integers and a float otherwise
+	// The sum of an empty array is 0, and integers overflow like they do with +, which is an error in checked mode
+	"sum": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array<number>"}, Return: "number"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: "first argument must be ARRAY"}
+			}
+
+			intSum := &object.Integer{Value: 0}
+			var floatSum float64
+			isFloat := false
+			for _, el := range args[0].(*object.Array).Items() {
+				switch el := el.(type) {
+				case *object.Integer:
+					added := object.Arithmetic("+", intSum, el)
+					if added.Type() == object.ERROR_OBJ {
+						return added
+					}
+					intSum = added.(*object.Integer)
+				case *object.Float:
+					floatSum += el.Value
+					isFloat = true
+				default:
+					return &object.Error{Message: fmt.Sprintf("cannot sum %s, only numbers", el.Type())}
+				}
+			}
+
+			if isFloat {
+				return &object.Float{Value: floatSum + float64(intSum.Value)}
+			}
+			return intSum
+		},
+	},
+
+	// min(arr, compare?) is the smallest element, in the order sort puts them in, or null for an empty array
+	"min": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "fn"}, Return: "T", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			return extremeElement(args, -1)
+		},
+	},
+
+	// max(arr, compare?) is the largest element, in the order sort puts them in, or null for an empty array
+	"max": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"array<T>", "fn"}, Return: "T", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			return extremeElement(args, 1)
 		},
 	},
 }
+
+// Checks the arguments to sort and sorted, returning the array and the compare function if there's one
+func sortArgs(args []object.Object) (*object.Array, object.Object, object.Object) {
+	if len(args) != 1 && len(args) != 2 {
+		return nil, nil, &object.Error{Message: "wrong number of arguments"}
+	}
+
+	if args[0].Type() != object.ARRAY_OBJ {
+		return nil, nil, &object.Error{Message: "first argument must be ARRAY"}
+	}
+
+	var compare object.Object
+	if len(args) == 2 {
+		if !isCallable(args[1]) {
+			return nil, nil, &object.Error{Message: "second argument must be FUNCTION"}
+		}
+		compare = args[1]
+	}
+
+	return args[0].(*object.Array), compare, nil
+}
+
+// Sorts the elements in place, stopping at the first pair that can't be compared
+func sortElements(elements []object.Object, compare object.Object) object.Object {
+	var failed object.Object
+	sort.SliceStable(elements, func(i, j int) bool {
+		if failed != nil {
+			return false
+		}
+		order, err := compareValues(elements[i], elements[j], compare)
+		if err != nil {
+			failed = err
+			return false
+		}
+		return order < 0
+	})
+	return failed
+}
+
+// The smallest element when sign is -1, or the largest when it's 1
+// The first of several equal elements is the one picked
+func extremeElement(args []object.Object, sign int) object.Object {
+	arr, compare, err := sortArgs(args)
+	if err != nil {
+		return err
+	}
+	elements := arr.Items()
+	if len(elements) == 0 {
+		return object.NULL
+	}
+
+	best := elements[0]
+	for _, el := range elements[1:] {
+		order, err := compareValues(el, best, compare)
+		if err != nil {
+			return err
+		}
+		if order*sign > 0 {
+			best = el
+		}
+	}
+	return best
+}
+
+// Orders two values the way sort does, as a negative integer, zero or a positive integer
+// With a compare function, that decides the order instead
+func compareValues(a, b object.Object, compare object.Object) (int, object.Object) {
+	if compare != nil {
+		return orderOf(object.CallFunction(compare, a, b), "compare function")
+	}
+
+	switch a := a.(type) {
+	case *object.Integer:
+		switch b := b.(type) {
+		case *object.Integer:
+			return cmp.Compare(a.Value, b.Value), nil
+		case *object.Float:
+			return cmp.Compare(float64(a.Value), b.Value), nil
+		}
+	case *object.Float:
+		switch b := b.(type) {
+		case *object.Integer:
+			return cmp.Compare(a.Value, float64(b.Value)), nil
+		case *object.Float:
+			return cmp.Compare(a.Value, b.Value), nil
+		}
+	case *object.String:
+		if b, ok := b.(*object.String); ok {
+			return strings.Compare(a.Value, b.Value), nil
+		}
+	case *object.DateTime:
+		if b, ok := b.(*object.DateTime); ok {
+			return a.Value.Compare(b.Value), nil
+		}
+	case *object.Duration:
+		if b, ok := b.(*object.Duration); ok {
+			return cmp.Compare(a.Value, b.Value), nil
+		}
+	}
+
+	methods := object.MethodsOf(a)
+	switch {
+	case methods["compare"] != nil:
+		result, _ := object.CallMethod(a, "compare", b)
+		return orderOf(result, "compare")
+	case methods["__lt__"] != nil:
+		for _, pair := range [][2]object.Object{{a, b}, {b, a}} {
+			result, _ := object.CallMethod(pair[0], "__lt__", pair[1])
+			if result.Type() == object.ERROR_OBJ {
+				return 0, result
+			}
+			if object.IsTruthy(result) {
+				if pair[0] == a {
+					return -1, nil
+				}
+				return 1, nil
+			}
+		}
+		return 0, nil
+	}
+
+	return 0, &object.Error{Message: fmt.Sprintf("cannot compare %s with %s", valueTypeName(a), valueTypeName(b))}
+}
+
+// The type of a value as the evaluator names it, records and enums by the name of their type
+func valueTypeName(obj object.Object) string {
+	switch obj := obj.(type) {
+	case *object.Record:
+		return obj.RecordType.Name
+	case *object.EnumValue:
+		return obj.Variant.Enum.Name
+	}
+	return string(obj.Type())
+}
+
+// The order a compare function returned, which has to be an integer
+func orderOf(result object.Object, name string) (int, object.Object) {
+	switch result := result.(type) {
+	case *object.Error:
+		return 0, result
+	case *object.Integer:
+		return cmp.Compare(result.Value, 0), nil
+	}
+	return 0, &object.Error{Message: fmt.Sprintf("%s must return INTEGER, got %s", name, valueTypeName(result))}
+}
+
+// Values compared with object.Equals, for unique and the set operations
+// Values that can be hash keys are looked up by their key, the rest are compared with every other one that can't
+// Whole floats are keyed like integers, since 1 == 1.0
+type valueSet struct {
+	keyed      map[object.HashKey][]object.Object
+	unhashable []object.Object
+}
+
+func newValueSet() *valueSet {
+	return &valueSet{keyed: map[object.HashKey][]object.Object{}}
+}
+
+// The values the value has to be compared with, and the key it's kept under when it has one
+func (s *valueSet) candidates(value object.Object) ([]object.Object, object.HashKey, bool) {
+	keyed := value
+	if f, ok := value.(*object.Float); ok && f.Value == math.Trunc(f.Value) && math.Abs(f.Value) < 1<<63 {
+		keyed = &object.Integer{Value: int64(f.Value)}
+	}
+
+	key, hashable := object.HashKeyOf(keyed)
+	if hashable {
+		return s.keyed[key], key, true
+	}
+	return s.unhashable, key, false
+}
+
+func (s *valueSet) has(value object.Object) bool {
+	candidates, _, _ := s.candidates(value)
+	for _, candidate := range candidates {
+		if object.Equals(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// Adds the value, reporting false when an equal one was already there
+func (s *valueSet) add(value object.Object) bool {
+	if s.has(value) {
+		return false
+	}
+	if _, key, hashable := s.candidates(value); hashable {
+		s.keyed[key] = append(s.keyed[key], value)
+	} else {
+		s.unhashable = append(s.unhashable, value)
+	}
+	return true
+}
+
+// Keeps the elements of a that keep says to, given whether they're in b, followed by the rest of b's when withB is set,
+// leaving out duplicates
+func setOperation(args []object.Object, keep func(inB bool) bool, withB bool) object.Object {
+	if len(args) != 2 {
+		return &object.Error{Message: "wrong number of arguments"}
+	}
+	for i, arg := range args {
+		if arg.Type() != object.ARRAY_OBJ {
+			return &object.Error{Message: fmt.Sprintf("argument %d must be ARRAY, got %s", i+1, arg.Type())}
+		}
+	}
+	a, b := args[0].(*object.Array).Items(), args[1].(*object.Array).Items()
+
+	inB := newValueSet()
+	for _, el := range b {
+		inB.add(el)
+	}
+
+	seen := newValueSet()
+	elements := []object.Object{}
+	for _, el := range a {
+		if keep(inB.has(el)) && seen.add(el) {
+			elements = append(elements, el)
+		}
+	}
+	if withB {
+		for _, el := range b {
+			if seen.add(el) {
+				elements = append(elements, el)
+			}
+		}
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+// Resolves an index into an array of the given length, counting negative indexes from the end
+func arrayIndex(index int64, length int) (int, object.Object) {
+	resolved := index
+	if resolved < 0 {
+		resolved += int64(length)
+	}
+	if resolved < 0 || resolved >= int64(length) {
+		return 0, &object.Error{Message: fmt.Sprintf("index out of range: %d (length %d)", index, length)}
+	}
+	return int(resolved), nil
+}
+
+func flattenArray(arr *object.Array, depth int, visiting map[*object.Array]bool) object.Object {
+	if visiting[arr] {
+		return &object.Error{Message: "cannot flatten an array that contains itself"}
+	}
+	visiting[arr] = true
+	defer delete(visiting, arr)
+
+	elements := []object.Object{}
+	for _, el := range arr.Items() {
+		nested, ok := el.(*object.Array)
+		if !ok || depth == 0 {
+			elements = append(elements, el)
+			continue
+		}
+
+		flattened := flattenArray(nested, depth-1, visiting)
+		if flattened.Type() == object.ERROR_OBJ {
+			return flattened
+		}
+		elements = append(elements, flattened.(*object.Array).Elements...)
+	}
+
+	return &object.Array{Elements: elements}
+}
@@ -1,13 +1,16 @@
 package modules
 
 import (
+	"bufio"
 	"os"
+	"runtime"
 
 	"github.com/ajtroup1/clear/object"
 )
 
 var FileBuiltins = map[string]*object.Builtin{
 	"read": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "string"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -29,6 +32,7 @@ var FileBuiltins = map[string]*object.Builtin{
 	},
 
 	"create": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "null"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -46,11 +50,12 @@ var FileBuiltins = map[string]*object.Builtin{
 			}
 			defer file.Close()
 
-			return &object.Null{}
+			return object.NULL
 		},
 	},
 
 	"write": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "null"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -74,11 +79,12 @@ var FileBuiltins = map[string]*object.Builtin{
 				return &object.Error{Message: err.Error()}
 			}
 
-			return &object.Null{}
+			return object.NULL
 		},
 	},
 
 	"remove": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "null"},
 		// TODO is it a dir or file?
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
@@ -96,11 +102,12 @@ var FileBuiltins = map[string]*object.Builtin{
 				return &object.Error{Message: err.Error()}
 			}
 
-			return &object.Null{}
+			return object.NULL
 		},
 	},
 
 	"rename": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "null"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -118,11 +125,12 @@ var FileBuiltins = map[string]*object.Builtin{
 				return &object.Error{Message: err.Error()}
 			}
 
-			return &object.Null{}
+			return object.NULL
 		},
 	},
 
 	"exists": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "bool"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -136,14 +144,15 @@ var FileBuiltins = map[string]*object.Builtin{
 
 			_, err := os.Stat(fileName)
 			if err != nil {
-				return &object.Boolean{Value: false}
+				return object.FALSE
 			}
 
-			return &object.Boolean{Value: true}
+			return object.TRUE
 		},
 	},
 
 	"isdir": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "bool"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -157,14 +166,15 @@ var FileBuiltins = map[string]*object.Builtin{
 
 			info, err := os.Stat(fileName)
 			if err != nil {
-				return &object.Boolean{Value: false}
+				return object.FALSE
 			}
 
-			return &object.Boolean{Value: info.IsDir()}
+			return object.NativeBool(info.IsDir())
 		},
 	},
 
 	"isfile": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "bool"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
@@ -178,10 +188,54 @@ var FileBuiltins = map[string]*object.Builtin{
 
 			info, err := os.Stat(fileName)
 			if err != nil {
-				return &object.Boolean{Value: false}
+				return object.FALSE
 			}
 
-			return &object.Boolean{Value: !info.IsDir()}
+			return object.NativeBool(!info.IsDir())
+		},
+	},
+
+	"lines": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "iterator"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			if args[0].Type() != object.STRING_OBJ {
+				return &object.Error{Message: "argument must be STRING"}
+			}
+
+			fileName := args[0].(*object.String).Value
+
+			file, err := os.Open(fileName)
+			if err != nil {
+				return &object.Error{Message: err.Error()}
+			}
+
+			// Lines are read as they're asked for, so files of any size can be gone through line by line
+			scanner := bufio.NewScanner(file)
+			done := false
+			it := &object.Iterator{
+				Name: "lines",
+				Next: func() object.Object {
+					if done {
+						return nil
+					}
+					if scanner.Scan() {
+						return &object.String{Value: scanner.Text()}
+					}
+					done = true
+					file.Close()
+					if err := scanner.Err(); err != nil {
+						return &object.Error{Message: err.Error()}
+					}
+					return nil
+				},
+			}
+			// Iterators that are dropped before the last line still close the file
+			runtime.SetFinalizer(it, func(*object.Iterator) { file.Close() })
+			return it
 		},
 	},
 }
@@ -0,0 +1,196 @@
+package modules
+
+import (
+	"fmt"
+
+	"github.com/ajtroup1/clear/object"
+)
+
+// Every builtin that returns a hash or an array of keys / values
+// preserves the insertion order of the hash it was given
+var HashesBuiltins = map[string]*object.Builtin{
+	"keys": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"K", "V"}, Params: []string{"hash<K, V>"}, Return: "array<K>"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return &object.Error{Message: fmt.Sprintf("argument must be HASH, got %s", args[0].Type())}
+			}
+
+			hash := args[0].(*object.Hash)
+			keys := &object.Array{Elements: []object.Object{}}
+			for _, pair := range hash.OrderedPairs() {
+				keys.Elements = append(keys.Elements, pair.Key)
+			}
+
+			return keys
+		},
+	},
+
+	"values": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"K", "V"}, Params: []string{"hash<K, V>"}, Return: "array<V>"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return &object.Error{Message: fmt.Sprintf("argument must be HASH, got %s", args[0].Type())}
+			}
+
+			hash := args[0].(*object.Hash)
+			values := &object.Array{Elements: []object.Object{}}
+			for _, pair := range hash.OrderedPairs() {
+				values.Elements = append(values.Elements, pair.Value)
+			}
+
+			return values
+		},
+	},
+
+	"entries": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"K", "V"}, Params: []string{"hash<K, V>"}, Return: "array<array<any>>"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return &object.Error{Message: fmt.Sprintf("argument must be HASH, got %s", args[0].Type())}
+			}
+
+			hash := args[0].(*object.Hash)
+			entries := &object.Array{Elements: []object.Object{}}
+			for _, pair := range hash.OrderedPairs() {
+				entry := &object.Array{Elements: []object.Object{pair.Key, pair.Value}}
+				entries.Elements = append(entries.Elements, entry)
+			}
+
+			return entries
+		},
+	},
+
+	"has": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"K", "V"}, Params: []string{"hash<K, V>", "K"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return &object.Error{Message: fmt.Sprintf("first argument must be HASH, got %s", args[0].Type())}
+			}
+
+			key, ok := object.HashKeyOf(args[1])
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("unusable as hash key: %s", args[1].Type())}
+			}
+
+			_, exists := args[0].(*object.Hash).Get(key)
+			return object.NativeBool(exists)
+		},
+	},
+
+	// Removes the key from the hash in place, the same way `arrays.push` mutates its array
+	"delete": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"K", "V"}, Params: []string{"hash<K, V>", "K"}, Return: "hash<K, V>"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return &object.Error{Message: fmt.Sprintf("first argument must be HASH, got %s", args[0].Type())}
+			}
+
+			key, ok := object.HashKeyOf(args[1])
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("unusable as hash key: %s", args[1].Type())}
+			}
+
+			hash := args[0].(*object.Hash)
+			if object.IsFrozen(hash) {
+				return &object.Error{Message: "cannot delete from a frozen hash"}
+			}
+			hash.Delete(key)
+
+			return hash
+		},
+	},
+
+	// Returns a new hash, keys of later hashes overwrite the values of earlier ones
+	"merge": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"K", "V"}, Params: []string{"hash<K, V>"}, Return: "hash<K, V>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) < 2 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want >= 2", len(args))}
+			}
+
+			merged := object.NewHash()
+			for i, arg := range args {
+				if arg.Type() != object.HASH_OBJ {
+					return &object.Error{Message: fmt.Sprintf("argument %d must be HASH, got %s", i, arg.Type())}
+				}
+				hash := arg.(*object.Hash)
+				for _, key := range hash.Keys() {
+					if pair, ok := hash.Get(key); ok {
+						merged.Set(key, pair)
+					}
+				}
+			}
+
+			return merged
+		},
+	},
+
+	"size": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"K", "V"}, Params: []string{"hash<K, V>"}, Return: "int"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+
+			if args[0].Type() != object.HASH_OBJ {
+				return &object.Error{Message: fmt.Sprintf("argument must be HASH, got %s", args[0].Type())}
+			}
+
+			return &object.Integer{Value: int64(args[0].(*object.Hash).Len())}
+		},
+	},
+
+	// Builds a hash from an array of [key, value] arrays, the inverse of `entries`
+	"fromEntries": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array<array<any>>"}, Return: "hash"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: fmt.Sprintf("argument must be ARRAY, got %s", args[0].Type())}
+			}
+
+			hash := object.NewHash()
+			for i, el := range args[0].(*object.Array).Items() {
+				var pair []object.Object
+				if entry, ok := el.(*object.Array); ok {
+					pair = entry.Items()
+				}
+				if len(pair) != 2 {
+					return &object.Error{Message: fmt.Sprintf("entry %d must be an ARRAY of [key, value], got %s", i, el.Inspect())}
+				}
+
+				key, ok := object.HashKeyOf(pair[0])
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("unusable as hash key: %s", pair[0].Type())}
+				}
+				hash.Set(key, object.HashPair{Key: pair[0], Value: pair[1]})
+			}
+
+			return hash
+		},
+	},
+}
@@ -0,0 +1,512 @@
+package modules
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/ajtroup1/clear/object"
+)
+
+// Responses are hashes of {status, headers, body}, with a json() function that parses the body
+// Statuses like 404 are responses like any other, only failing to get a response at all is an error
+//
+// Options for requests are a hash that can hold
+//   - headers: a hash of header names to values
+//   - body: sent as is when it's a string, otherwise as JSON
+//   - timeout: a duration or a number of milliseconds, 30 seconds by default
+var HTTPBuiltins = map[string]*object.Builtin{
+	"get": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "hash"}, Return: "hash", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+			return sendRequest(&object.String{Value: "GET"}, args[0], args[1:]...)
+		},
+	},
+
+	// post(url, body, options?) sends the body, which is the same as giving it in the options
+	"post": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "any", "hash"}, Return: "hash", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			options := object.NewHash()
+			if len(args) == 3 {
+				given, ok := args[2].(*object.Hash)
+				if !ok {
+					return &object.Error{Message: "options must be HASH"}
+				}
+				for _, pair := range given.OrderedPairs() {
+					key, _ := object.HashKeyOf(pair.Key)
+					options.Set(key, pair)
+				}
+			}
+			setField(options, "body", args[1])
+			return sendRequest(&object.String{Value: "POST"}, args[0], options)
+		},
+	},
+
+	// request(method, url, options?) sends a request with any method
+	"request": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string", "hash"}, Return: "hash", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+			return sendRequest(args[0], args[1], args[2:]...)
+		},
+	},
+
+	// serve(port, handler) starts a server on the port, or on any free port when it's 0, and returns straight away
+	// The program keeps running while the server does, until it's stopped or the program is interrupted
+	//
+	// The handler is called with a hash of {method, path, query, headers, body, params} and a json() function,
+	// and can be called for several requests at once, so shared data needs a sync.mutex
+	// What it returns is the response
+	//   - a hash with an integer status is a response of {status, headers?, body?}, like the ones requests get back
+	//   - a string is sent as text, null as an empty response, anything else as JSON
+	//   - an error is sent as a 500 response with its message
+	"serve": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int", "fn"}, Return: "server"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			port, ok := args[0].(*object.Integer)
+			if !ok {
+				return &object.Error{Message: "first argument must be INTEGER"}
+			}
+			handler := args[1]
+			if !isCallable(handler) {
+				return &object.Error{Message: "second argument must be FUNCTION"}
+			}
+
+			listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port.Value))
+			if err != nil {
+				return &object.Error{Message: err.Error()}
+			}
+
+			srv := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				request, err := requestHash(r)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				writeResponse(w, object.CallFunction(handler, request))
+			})}
+
+			server := object.NewServer(fmt.Sprintf("http://localhost:%d", listener.Addr().(*net.TCPAddr).Port), func() {
+				ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+				defer cancel()
+				srv.Shutdown(ctx)
+			})
+			stopOnInterrupt()
+
+			go func() {
+				// Serve only returns early when the listener fails, which stops the server too
+				if err := srv.Serve(listener); err != http.ErrServerClosed {
+					fmt.Fprintf(os.Stderr, "http server stopped: %s\n", err)
+					server.Cancel()
+				}
+			}()
+			return server
+		},
+	},
+
+	// Stops the server once the requests it's handling are finished, reporting false when it was already stopped
+	"stop": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"server"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			server, ok := args[0].(*object.Server)
+			if !ok {
+				return &object.Error{Message: "argument must be SERVER"}
+			}
+
+			return object.NativeBool(server.Cancel())
+		},
+	},
+
+	// Where the server can be reached, like "http://localhost:8080"
+	"url": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"server"}, Return: "string"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			server, ok := args[0].(*object.Server)
+			if !ok {
+				return &object.Error{Message: "argument must be SERVER"}
+			}
+
+			return &object.String{Value: server.URL}
+		},
+	},
+
+	// Makes a handler that picks another handler by the request's method and path, from a hash like
+	//   {"GET /users/:id": getUser, "POST /users": addUser, "/health": health}
+	// Routes without a method take any method, a :name segment matches any one segment and puts it in the
+	// request's params, and a trailing * matches the rest of the path
+	// Routes are tried in the order they are written, requests matching none get a 404 (or a 405 for the wrong method)
+	"router": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"hash"}, Return: "fn"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			table, ok := args[0].(*object.Hash)
+			if !ok {
+				return &object.Error{Message: "argument must be HASH"}
+			}
+
+			routes := []route{}
+			for _, pair := range table.OrderedPairs() {
+				pattern, ok := pair.Key.(*object.String)
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("routes must be STRING, got %s", pair.Key.Type())}
+				}
+				if !isCallable(pair.Value) {
+					return &object.Error{Message: fmt.Sprintf("the handler for %q must be FUNCTION, got %s", pattern.Value, pair.Value.Type())}
+				}
+				routes = append(routes, parseRoute(pattern.Value, pair.Value))
+			}
+
+			return &object.Builtin{
+				Signature: &object.Signature{Params: []string{"hash"}, Return: "any"},
+				Fn: func(args ...object.Object) object.Object {
+					if len(args) != 1 {
+						return &object.Error{Message: "wrong number of arguments"}
+					}
+					request, ok := args[0].(*object.Hash)
+					if !ok {
+						return &object.Error{Message: "argument must be HASH"}
+					}
+					return dispatchRoute(routes, request)
+				},
+			}
+		},
+	},
+}
+
+var httpClient = &http.Client{}
+
+// Builds and sends a request, returning the response hash
+func sendRequest(method, url object.Object, options ...object.Object) object.Object {
+	methodStr, ok := method.(*object.String)
+	if !ok {
+		return &object.Error{Message: "method must be STRING"}
+	}
+	urlStr, ok := url.(*object.String)
+	if !ok {
+		return &object.Error{Message: "url must be STRING"}
+	}
+
+	opts := object.NewHash()
+	if len(options) == 1 {
+		if opts, ok = options[0].(*object.Hash); !ok {
+			return &object.Error{Message: "options must be HASH"}
+		}
+	}
+
+	var body io.Reader
+	contentType := ""
+	if value, ok := field(opts, "body"); ok {
+		switch value := value.(type) {
+		case *object.String:
+			body = strings.NewReader(value.Value)
+		case *object.Null:
+		default:
+			var out bytes.Buffer
+			if err := encodeJSON(&out, value, "", 0, map[object.Object]bool{}); err != nil {
+				return &object.Error{Message: err.Error()}
+			}
+			body = &out
+			contentType = "application/json"
+		}
+	}
+
+	timeout := 30 * time.Second
+	if value, ok := field(opts, "timeout"); ok {
+		switch value := value.(type) {
+		case *object.Duration:
+			timeout = value.Value
+		case *object.Integer:
+			timeout = time.Duration(value.Value) * time.Millisecond
+		default:
+			return &object.Error{Message: fmt.Sprintf("timeout must be DURATION or INTEGER, got %s", value.Type())}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, strings.ToUpper(methodStr.Value), urlStr.Value, body)
+	if err != nil {
+		return &object.Error{Message: err.Error()}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if value, ok := field(opts, "headers"); ok {
+		headers, ok := value.(*object.Hash)
+		if !ok {
+			return &object.Error{Message: fmt.Sprintf("headers must be HASH, got %s", value.Type())}
+		}
+		for _, pair := range headers.OrderedPairs() {
+			req.Header.Set(textOf(pair.Key), textOf(pair.Value))
+		}
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return &object.Error{Message: err.Error()}
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return &object.Error{Message: err.Error()}
+	}
+
+	response := object.NewHash()
+	setField(response, "status", &object.Integer{Value: int64(resp.StatusCode)})
+	setField(response, "headers", headerHash(resp.Header))
+	setBody(response, string(data))
+	return response
+}
+
+// The request a handler is called with
+func requestHash(r *http.Request) (*object.Hash, error) {
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	query := object.NewHash()
+	values := r.URL.Query()
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		setField(query, name, &object.String{Value: values.Get(name)})
+	}
+
+	request := object.NewHash()
+	setField(request, "method", &object.String{Value: r.Method})
+	setField(request, "path", &object.String{Value: r.URL.Path})
+	setField(request, "query", query)
+	setField(request, "headers", headerHash(r.Header))
+	setField(request, "params", object.NewHash())
+	setBody(request, string(data))
+	return request, nil
+}
+
+// Writes what a handler returned as the response
+func writeResponse(w http.ResponseWriter, result object.Object) {
+	status := http.StatusOK
+	var body object.Object = result
+
+	if hash, ok := result.(*object.Hash); ok {
+		if value, ok := field(hash, "status"); ok {
+			if code, ok := value.(*object.Integer); ok {
+				status = int(code.Value)
+				body, _ = field(hash, "body")
+				if headers, ok := field(hash, "headers"); ok {
+					if headers, ok := headers.(*object.Hash); ok {
+						for _, pair := range headers.OrderedPairs() {
+							w.Header().Set(textOf(pair.Key), textOf(pair.Value))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	switch value := body.(type) {
+	case nil, *object.Null:
+		w.WriteHeader(status)
+	case *object.Error:
+		http.Error(w, value.Message, http.StatusInternalServerError)
+	case *object.String:
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		}
+		w.WriteHeader(status)
+		io.WriteString(w, value.Value)
+	default:
+		var out bytes.Buffer
+		if err := encodeJSON(&out, value, "", 0, map[object.Object]bool{}); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if w.Header().Get("Content-Type") == "" {
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.WriteHeader(status)
+		w.Write(out.Bytes())
+	}
+}
+
+// A route of the router, split into the segments of its path
+type route struct {
+	method   string // empty for any method
+	segments []string
+	handler  object.Object
+}
+
+func parseRoute(pattern string, handler object.Object) route {
+	r := route{handler: handler}
+	if method, path, ok := strings.Cut(pattern, " "); ok {
+		r.method, pattern = strings.ToUpper(method), strings.TrimSpace(path)
+	}
+	r.segments = strings.Split(strings.Trim(pattern, "/"), "/")
+	return r
+}
+
+// Reports whether the path matches the route, and the params its :name segments matched
+func (r route) match(path string) (map[string]string, bool) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	params := map[string]string{}
+	for i, segment := range r.segments {
+		switch {
+		case segment == "*":
+			return params, true
+		case i >= len(parts):
+			return nil, false
+		case strings.HasPrefix(segment, ":"):
+			params[segment[1:]] = parts[i]
+		case segment != parts[i]:
+			return nil, false
+		}
+	}
+	return params, len(parts) == len(r.segments)
+}
+
+func dispatchRoute(routes []route, request *object.Hash) object.Object {
+	method, _ := field(request, "method")
+	path, _ := field(request, "path")
+
+	allowed := false
+	for _, r := range routes {
+		params, ok := r.match(textOf(path))
+		if !ok {
+			continue
+		}
+		if r.method != "" && r.method != textOf(method) {
+			allowed = true
+			continue
+		}
+
+		matched := object.NewHash()
+		for _, segment := range r.segments {
+			if name, ok := strings.CutPrefix(segment, ":"); ok {
+				setField(matched, name, &object.String{Value: params[name]})
+			}
+		}
+		setField(request, "params", matched)
+		return object.CallFunction(r.handler, request)
+	}
+
+	response := object.NewHash()
+	if allowed {
+		setField(response, "status", &object.Integer{Value: http.StatusMethodNotAllowed})
+		setField(response, "body", &object.String{Value: "method not allowed"})
+	} else {
+		setField(response, "status", &object.Integer{Value: http.StatusNotFound})
+		setField(response, "body", &object.String{Value: "not found"})
+	}
+	return response
+}
+
+// Interrupting the program (Ctrl-C) stops the servers and timers gracefully, instead of killing it outright
+// A second interrupt kills it as usual
+var interruptOnce sync.Once
+
+func stopOnInterrupt() {
+	interruptOnce.Do(func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		go func() {
+			<-signals
+			signal.Stop(signals)
+			object.StopPending()
+		}()
+	})
+}
+
+// Sets the body of a request or response, along with a json() function that parses it
+func setBody(hash *object.Hash, body string) {
+	setField(hash, "body", &object.String{Value: body})
+	setField(hash, "json", &object.Builtin{
+		Signature: &object.Signature{Params: []string{}, Return: "any"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+			return JSONBuiltins["parse"].Fn(&object.String{Value: body})
+		},
+	})
+}
+
+// Headers with several values have them joined by commas, the way HTTP allows them to be written
+func headerHash(header http.Header) *object.Hash {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := object.NewHash()
+	for _, name := range names {
+		setField(hash, name, &object.String{Value: strings.Join(header.Values(name), ", ")})
+	}
+	return hash
+}
+
+func setField(hash *object.Hash, name string, value object.Object) {
+	key := &object.String{Value: name}
+	hash.Set(key.HashKey(), object.HashPair{Key: key, Value: value})
+}
+
+func field(hash *object.Hash, name string) (object.Object, bool) {
+	pair, ok := hash.Get((&object.String{Value: name}).HashKey())
+	return pair.Value, ok
+}
+
+// Strings as they are, anything else as it's printed
+func textOf(obj object.Object) string {
+	if str, ok := obj.(*object.String); ok {
+		return str.Value
+	}
+	return obj.Inspect()
+}
+
+func isCallable(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Function, *object.Builtin:
+		return true
+	}
+	return false
+}
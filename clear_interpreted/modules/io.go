@@ -8,26 +8,29 @@ import (
 
 var IOBuiltins = map[string]*object.Builtin{
 	"print": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any"}, Return: "null", Variadic: true},
 		Fn: func(args ...object.Object) object.Object {
 			for _, arg := range args {
 				fmt.Print(arg.Inspect())
 			}
-			return &object.Null{}
+			return object.NULL
 		},
 	},
 
 	"println": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any"}, Return: "null", Variadic: true},
 		Fn: func(args ...object.Object) object.Object {
 			out := ""
 			for _, arg := range args {
 				out += arg.Inspect()
 			}
 			fmt.Println(out)
-			return &object.Null{}
+			return object.NULL
 		},
 	},
 
 	"printf": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "any"}, Return: "null", Variadic: true},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) < 1 {
 				return &object.Error{Message: "printf requires at least one argument"}
@@ -37,31 +40,16 @@ var IOBuiltins = map[string]*object.Builtin{
 
 			if len(args) == 1 {
 				fmt.Print(format)
-				return &object.Null{}
+				return object.NULL
 			}
 
-			values := make([]interface{}, len(args)-1)
-			for i, arg := range args[1:] {
-				switch arg := arg.(type) {
-				case *object.Integer:
-					values[i] = arg.Value
-				case *object.Float:
-					values[i] = arg.Value
-				case *object.String:
-					values[i] = arg.Value
-				case *object.Boolean:
-					values[i] = arg.Value
-				default:
-					values[i] = arg.Inspect()
-				}
-			}
-
-			fmt.Printf(format, values...)
-			return &object.Null{}
+			fmt.Printf(format, formatValues(args[1:])...)
+			return object.NULL
 		},
 	},
 
 	"input": &object.Builtin{
+		Signature: &object.Signature{Params: []string{}, Return: "string"},
 		Fn: func(args ...object.Object) object.Object {
 			var input string
 			fmt.Scanln(&input)
@@ -69,3 +57,24 @@ var IOBuiltins = map[string]*object.Builtin{
 		},
 	},
 }
+
+// The Go values to format in place of the arguments to printf and strings.format,
+// numbers, strings and booleans as themselves and anything else as it's printed
+func formatValues(args []object.Object) []interface{} {
+	values := make([]interface{}, len(args))
+	for i, arg := range args {
+		switch arg := arg.(type) {
+		case *object.Integer:
+			values[i] = arg.Value
+		case *object.Float:
+			values[i] = arg.Value
+		case *object.String:
+			values[i] = arg.Value
+		case *object.Boolean:
+			values[i] = arg.Value
+		default:
+			values[i] = arg.Inspect()
+		}
+	}
+	return values
+}
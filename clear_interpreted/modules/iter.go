@@ -0,0 +1,223 @@
+package modules
+
+import "github.com/ajtroup1/clear/object"
+
+// Adapters over iterators, which are also callable as methods on them: gen().map(f).take(3)
+// Every adapter is lazy, values are only pulled through it as whatever iterates over the result asks for them
+// Anything a for-in loop can go over is accepted where an iterator is expected
+var IterBuiltins = map[string]*object.Builtin{
+	"next": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"iterator"}, Return: "any"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			it, ok := args[0].(*object.Iterator)
+			if !ok {
+				return &object.Error{Message: "argument must be ITERATOR"}
+			}
+
+			// Exhausted iterators keep returning null
+			value := it.Next()
+			if value == nil {
+				return object.NULL
+			}
+			return value
+		},
+	},
+
+	"take": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "int"}, Return: "iterator"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			n, ok := args[1].(*object.Integer)
+			if !ok {
+				return &object.Error{Message: "second argument must be INTEGER"}
+			}
+
+			return adapt(args[0], "take", func(it *object.Iterator) func() object.Object {
+				taken := int64(0)
+				return func() object.Object {
+					if taken >= n.Value {
+						return nil
+					}
+					taken++
+					return it.Next()
+				}
+			})
+		},
+	},
+
+	"skip": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "int"}, Return: "iterator"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			n, ok := args[1].(*object.Integer)
+			if !ok {
+				return &object.Error{Message: "second argument must be INTEGER"}
+			}
+
+			return adapt(args[0], "skip", func(it *object.Iterator) func() object.Object {
+				skipped := false
+				return func() object.Object {
+					if !skipped {
+						skipped = true
+						for i := int64(0); i < n.Value; i++ {
+							if value := it.Next(); value == nil || value.Type() == object.ERROR_OBJ {
+								return value
+							}
+						}
+					}
+					return it.Next()
+				}
+			})
+		},
+	},
+
+	"map": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "fn"}, Return: "iterator"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			fn := args[1]
+			return adapt(args[0], "map", func(it *object.Iterator) func() object.Object {
+				return func() object.Object {
+					value := it.Next()
+					if value == nil || value.Type() == object.ERROR_OBJ {
+						return value
+					}
+					return object.CallFunction(fn, value)
+				}
+			})
+		},
+	},
+
+	"filter": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "fn"}, Return: "iterator"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			fn := args[1]
+			return adapt(args[0], "filter", func(it *object.Iterator) func() object.Object {
+				return func() object.Object {
+					for {
+						value := it.Next()
+						if value == nil || value.Type() == object.ERROR_OBJ {
+							return value
+						}
+						keep := object.CallFunction(fn, value)
+						if keep.Type() == object.ERROR_OBJ {
+							return keep
+						}
+						if object.IsTruthy(keep) {
+							return value
+						}
+					}
+				}
+			})
+		},
+	},
+
+	"zip": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "any"}, Return: "iterator"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			iter := object.Iter(args[1])
+			other, ok := iter.(*object.Iterator)
+			if !ok {
+				return iter
+			}
+
+			// Pairs stop as soon as either side runs out
+			return adapt(args[0], "zip", func(it *object.Iterator) func() object.Object {
+				return func() object.Object {
+					left := it.Next()
+					if left == nil || left.Type() == object.ERROR_OBJ {
+						return left
+					}
+					right := other.Next()
+					if right == nil || right.Type() == object.ERROR_OBJ {
+						return right
+					}
+					return &object.Array{Elements: []object.Object{left, right}}
+				}
+			})
+		},
+	},
+
+	"enumerate": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any"}, Return: "iterator"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			return adapt(args[0], "enumerate", func(it *object.Iterator) func() object.Object {
+				i := int64(0)
+				return func() object.Object {
+					value := it.Next()
+					if value == nil || value.Type() == object.ERROR_OBJ {
+						return value
+					}
+					i++
+					return &object.Array{Elements: []object.Object{&object.Integer{Value: i - 1}, value}}
+				}
+			})
+		},
+	},
+
+	"toArray": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any"}, Return: "array"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			return collect(args[0])
+		},
+	},
+}
+
+// Wraps the iterator over source in an adapter, next builds the adapter's Next from the iterator it pulls from
+// Sources that can't be iterated over give the evaluator's error for them
+func adapt(source object.Object, name string, next func(it *object.Iterator) func() object.Object) object.Object {
+	iter := object.Iter(source)
+	it, ok := iter.(*object.Iterator)
+	if !ok {
+		return iter
+	}
+	return &object.Iterator{Name: name, Next: next(it)}
+}
+
+// Pulls every value out of something iterable into an array, which never ends for endless iterators
+func collect(source object.Object) object.Object {
+	iter := object.Iter(source)
+	it, ok := iter.(*object.Iterator)
+	if !ok {
+		return iter
+	}
+
+	elements := []object.Object{}
+	for value := it.Next(); value != nil; value = it.Next() {
+		if value.Type() == object.ERROR_OBJ {
+			return value
+		}
+		elements = append(elements, value)
+	}
+	return &object.Array{Elements: elements}
+}
+
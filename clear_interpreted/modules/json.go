@@ -0,0 +1,416 @@
+package modules
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/ajtroup1/clear/object"
+)
+
+// Objects are read into hashes that keep the order their keys were written in,
+// and hashes are written out in their own order, so parsing and stringifying gives back the same text
+// Whole numbers become integers and everything else with a fraction or exponent becomes a float
+var JSONBuiltins = map[string]*object.Builtin{
+	"parse": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "any"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			str, ok := args[0].(*object.String)
+			if !ok {
+				return &object.Error{Message: "argument must be STRING"}
+			}
+
+			dec := newJSONDecoder(strings.NewReader(str.Value))
+			value, err := decodeJSON(dec)
+			if err == nil {
+				// Anything but whitespace after the value means the text wasn't a single JSON value
+				end := dec.InputOffset()
+				if _, err = dec.Token(); err == io.EOF {
+					return value
+				} else if err == nil {
+					err = &trailingJSON{offset: end}
+				}
+			}
+			return jsonError(err, strings.NewReader(str.Value))
+		},
+	},
+
+	// stringify(value, indent?) writes compact JSON, or JSON indented by a number of spaces or a string
+	// Records are written as objects of their fields, and DateTimes and durations as strings
+	"stringify": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "any"}, Return: "string", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 && len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			indent := ""
+			if len(args) == 2 {
+				switch arg := args[1].(type) {
+				case *object.Integer:
+					if arg.Value < 0 {
+						return &object.Error{Message: "indent can't be negative"}
+					}
+					indent = strings.Repeat(" ", int(arg.Value))
+				case *object.String:
+					indent = arg.Value
+				default:
+					return &object.Error{Message: "indent must be INTEGER or STRING"}
+				}
+			}
+
+			var out bytes.Buffer
+			if err := encodeJSON(&out, args[0], indent, 0, map[object.Object]bool{}); err != nil {
+				return &object.Error{Message: err.Error()}
+			}
+			return &object.String{Value: out.String()}
+		},
+	},
+
+	// Reads a file a value at a time, so files too big to parse at once can still be gone through
+	// A file holding one array gives its elements, otherwise it gives every value in it, like the lines of a JSON Lines file
+	"stream": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "iterator"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			path, ok := args[0].(*object.String)
+			if !ok {
+				return &object.Error{Message: "argument must be STRING"}
+			}
+
+			file, err := os.Open(path.Value)
+			if err != nil {
+				return &object.Error{Message: err.Error()}
+			}
+
+			reader := bufio.NewReader(file)
+			dec := newJSONDecoder(reader)
+			started, inArray, done := false, false, false
+
+			// Stops the iterator, turning a decoding error into an Error placed in the file
+			fail := func(err error) object.Object {
+				done = true
+				file.Close()
+				if err == io.EOF {
+					return nil
+				}
+				reopened, openErr := os.Open(path.Value)
+				if openErr != nil {
+					return &object.Error{Message: err.Error()}
+				}
+				defer reopened.Close()
+				return jsonError(err, reopened)
+			}
+
+			it := &object.Iterator{
+				Name: "json",
+				Next: func() object.Object {
+					if done {
+						return nil
+					}
+					if !started {
+						started = true
+						// Peeking decides whether the values are the elements of an array or the file's values themselves
+						if first, err := peekNonSpace(reader); err == nil && first == '[' {
+							if _, err := dec.Token(); err != nil {
+								return fail(err)
+							}
+							inArray = true
+						}
+					}
+
+					if inArray && !dec.More() {
+						// Consumes the closing bracket, anything after it is an error
+						if _, err := dec.Token(); err != nil {
+							return fail(err)
+						}
+						end := dec.InputOffset()
+						if _, err := dec.Token(); err != io.EOF {
+							if err == nil {
+								err = &trailingJSON{offset: end}
+							}
+							return fail(err)
+						}
+						return fail(io.EOF)
+					}
+					if !inArray && !dec.More() {
+						// Either the end of the file, or a stray closing bracket
+						_, err := dec.Token()
+						if err == nil {
+							err = &trailingJSON{offset: dec.InputOffset() - 1}
+						}
+						return fail(err)
+					}
+
+					value, err := decodeJSON(dec)
+					if err != nil {
+						return fail(err)
+					}
+					return value
+				},
+			}
+			// Iterators that are dropped before the last value still close the file
+			runtime.SetFinalizer(it, func(*object.Iterator) { file.Close() })
+			return it
+		},
+	},
+}
+
+func newJSONDecoder(r io.Reader) *json.Decoder {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	return dec
+}
+
+// Reads the next value from the decoder, keeping the order of the keys in objects
+func decodeJSON(dec *json.Decoder) (object.Object, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+
+	switch tok := tok.(type) {
+	case json.Delim:
+		switch tok {
+		case '[':
+			elements := []object.Object{}
+			for dec.More() {
+				value, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				elements = append(elements, value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return &object.Array{Elements: elements}, nil
+		case '{':
+			hash := object.NewHash()
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key := &object.String{Value: keyTok.(string)}
+				value, err := decodeJSON(dec)
+				if err != nil {
+					return nil, err
+				}
+				hash.Set(key.HashKey(), object.HashPair{Key: key, Value: value})
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return hash, nil
+		}
+	case json.Number:
+		if n, err := tok.Int64(); err == nil {
+			return &object.Integer{Value: n}, nil
+		}
+		f, err := tok.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("number %s is out of range", tok)
+		}
+		return &object.Float{Value: f}, nil
+	case string:
+		return &object.String{Value: tok}, nil
+	case bool:
+		return object.NativeBool(tok), nil
+	case nil:
+		return object.NULL, nil
+	}
+	return nil, fmt.Errorf("unexpected %v", tok)
+}
+
+// Returned when a value is followed by more than whitespace, at the end of the value
+type trailingJSON struct {
+	offset int64
+}
+
+func (t *trailingJSON) Error() string { return "unexpected data after the value" }
+
+// Describes a decoding error, with the line and column it happened at when it's a syntax error
+// The text is read again up to the error to find them, which only happens once something has gone wrong
+func jsonError(err error, text io.Reader) *object.Error {
+	var offset int64
+	var syntaxErr *json.SyntaxError
+	var trailing *trailingJSON
+	switch {
+	case errors.As(err, &syntaxErr):
+		// The offset is just past the byte that was wrong
+		offset = syntaxErr.Offset - 1
+	case errors.As(err, &trailing):
+		offset = trailing.offset
+	case err == io.ErrUnexpectedEOF:
+		offset = math.MaxInt64
+	default:
+		return &object.Error{Message: "invalid JSON: " + err.Error()}
+	}
+
+	line, col := 1, 1
+	reader := bufio.NewReader(text)
+	for i := int64(0); ; i++ {
+		b, readErr := reader.ReadByte()
+		// Trailing data is placed at where it starts rather than where the value ended
+		if readErr != nil || (i >= offset && (trailing == nil || !isJSONSpace(b))) {
+			break
+		}
+		if b == '\n' {
+			line, col = line+1, 1
+		} else {
+			col++
+		}
+	}
+
+	return &object.Error{Message: fmt.Sprintf("invalid JSON at line %d, column %d: %s", line, col, strings.TrimPrefix(err.Error(), "json: "))}
+}
+
+func isJSONSpace(b byte) bool {
+	return b == ' ' || b == '\t' || b == '\n' || b == '\r'
+}
+
+// The first byte that isn't whitespace, without taking it out of the reader
+func peekNonSpace(r *bufio.Reader) (byte, error) {
+	for n := 1; ; n++ {
+		peeked, err := r.Peek(n)
+		if len(peeked) < n {
+			return 0, err
+		}
+		if b := peeked[n-1]; !isJSONSpace(b) {
+			return b, nil
+		}
+	}
+}
+
+// Writes the value as JSON, indenting nested values when indent isn't empty
+func encodeJSON(out *bytes.Buffer, obj object.Object, indent string, depth int, seen map[object.Object]bool) error {
+	switch obj := obj.(type) {
+	case *object.Null:
+		out.WriteString("null")
+	case *object.Boolean:
+		out.WriteString(strconv.FormatBool(obj.Value))
+	case *object.Integer:
+		out.WriteString(strconv.FormatInt(obj.Value, 10))
+	case *object.Float:
+		if math.IsNaN(obj.Value) || math.IsInf(obj.Value, 0) {
+			return fmt.Errorf("can't convert %s to JSON", obj.Inspect())
+		}
+		str := strconv.FormatFloat(obj.Value, 'g', -1, 64)
+		// Whole floats keep a fraction so they're read back as floats
+		if !strings.ContainsAny(str, ".eE") {
+			str += ".0"
+		}
+		out.WriteString(str)
+	case *object.String:
+		out.WriteString(quoteJSON(obj.Value))
+	case *object.DateTime, *object.Duration:
+		out.WriteString(quoteJSON(obj.Inspect()))
+
+	case *object.Array:
+		if seen[obj] {
+			return fmt.Errorf("can't convert an array that contains itself to JSON")
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+
+		elements := obj.Items()
+		return encodeJSONList(out, '[', ']', nil, len(elements), func(i int) error {
+			return encodeJSON(out, elements[i], indent, depth+1, seen)
+		}, indent, depth)
+
+	case *object.Hash:
+		if seen[obj] {
+			return fmt.Errorf("can't convert a hash that contains itself to JSON")
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+
+		pairs := obj.OrderedPairs()
+		keys := make([]string, len(pairs))
+		for i, pair := range pairs {
+			switch key := pair.Key.(type) {
+			case *object.String:
+				keys[i] = key.Value
+			case *object.Integer, *object.Boolean:
+				keys[i] = key.Inspect()
+			default:
+				return fmt.Errorf("can't convert a hash with %s keys to JSON, keys must be strings, integers or booleans", key.Type())
+			}
+		}
+		return encodeJSONList(out, '{', '}', keys, len(pairs), func(i int) error {
+			return encodeJSON(out, pairs[i].Value, indent, depth+1, seen)
+		}, indent, depth)
+
+	case *object.Record:
+		keys := make([]string, len(obj.RecordType.Fields))
+		for i, field := range obj.RecordType.Fields {
+			keys[i] = field.Name
+		}
+		return encodeJSONList(out, '{', '}', keys, len(obj.Values), func(i int) error {
+			return encodeJSON(out, obj.Values[i], indent, depth+1, seen)
+		}, indent, depth)
+
+	default:
+		return fmt.Errorf("can't convert %s to JSON", obj.Type())
+	}
+	return nil
+}
+
+// Writes the n entries of an array, or of an object when there are keys, one per line when indenting
+// value writes the value of the entry at an index
+func encodeJSONList(out *bytes.Buffer, open, close byte, keys []string, n int, value func(i int) error, indent string, depth int) error {
+	out.WriteByte(open)
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			out.WriteByte(',')
+		}
+		if indent != "" {
+			out.WriteByte('\n')
+			out.WriteString(strings.Repeat(indent, depth+1))
+		}
+		if keys != nil {
+			out.WriteString(quoteJSON(keys[i]))
+			out.WriteByte(':')
+			if indent != "" {
+				out.WriteByte(' ')
+			}
+		}
+		if err := value(i); err != nil {
+			return err
+		}
+	}
+	if indent != "" && n > 0 {
+		out.WriteByte('\n')
+		out.WriteString(strings.Repeat(indent, depth))
+	}
+	out.WriteByte(close)
+	return nil
+}
+
+// Quotes a string the way JSON does, leaving characters like < and & as they are
+func quoteJSON(s string) string {
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(out.String(), "\n")
+}
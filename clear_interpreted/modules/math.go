@@ -1,14 +1,22 @@
 package modules
 
 import (
+	"cmp"
 	"fmt"
 	"math"
+	"math/bits"
 
 	"github.com/ajtroup1/clear/object"
 )
 
+// Results are integers when only whole numbers make sense (rounding, gcd and lcm),
+// and floats for everything computed in floating point, like sqrt, log and the trigonometric functions, even for integer arguments
+// abs, min, max and clamp give an integer when every argument is one and a float otherwise
+// pow gives an integer only for an integer raised to a non-negative integer power
+// Invalid arguments to the floating point functions give NaN or an infinity instead of an error, like sqrt(-1) and log(0)
 var MathBuiltins = map[string]*object.Builtin{
 	"abs": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number"}, Return: "number"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
@@ -22,49 +30,323 @@ var MathBuiltins = map[string]*object.Builtin{
 				return &object.Float{Value: math.Abs(args[0].(*object.Float).Value)}
 			}
 
-			fmt.Printf("args[0].Type(): %s\n", args[0].Type())
-
 			return &object.Error{Message: fmt.Sprintf("argument to `abs` not supported, got %s", args[0].Type())}
 		},
 	},
 
-	"round": &object.Builtin{
+	// round, floor, ceil and trunc give the integer nearest the number in their direction
+	// round takes halves away from zero, and trunc drops the fraction, rounding towards zero
+	"round": roundingBuiltin("round", math.Round),
+	"floor": roundingBuiltin("floor", math.Floor),
+	"ceil":  roundingBuiltin("ceil", math.Ceil),
+	"trunc": roundingBuiltin("trunc", math.Trunc),
+
+	"pow": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number", "number"}, Return: "number"},
 		Fn: func(args ...object.Object) object.Object {
-			if len(args) != 1 {
-				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			if len(args) != 2 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
 			}
 
-			if args[0].Type() == object.INTEGER_OBJ {
-				return args[0]
+			// A negative exponent gives a fraction, so only non-negative integer powers stay integers
+			if base, ok := args[0].(*object.Integer); ok {
+				if exponent, ok := args[1].(*object.Integer); ok && exponent.Value >= 0 {
+					return &object.Integer{Value: int64(math.Pow(float64(base.Value), float64(exponent.Value)))}
+				}
 			}
 
-			if args[0].Type() == object.FLOAT_OBJ {
-				return &object.Integer{Value: int64(math.Round(args[0].(*object.Float).Value))}
+			base, baseOk := toNumber(args[0])
+			exponent, exponentOk := toNumber(args[1])
+			if baseOk && exponentOk {
+				return &object.Float{Value: math.Pow(base, exponent)}
 			}
 
-			return &object.Error{Message: fmt.Sprintf("argument to `round` not supported, got %s", args[0].Type())}
+			return &object.Error{Message: fmt.Sprintf("arguments to `pow` not supported, got %s and %s", args[0].Type(), args[1].Type())}
 		},
 	},
 
-	"pow": &object.Builtin{
+	"sqrt":  floatBuiltin(math.Sqrt),
+	"cbrt":  floatBuiltin(math.Cbrt),
+	"exp":   floatBuiltin(math.Exp),
+	"log":   floatBuiltin(math.Log),
+	"log2":  floatBuiltin(math.Log2),
+	"log10": floatBuiltin(math.Log10),
+
+	// Angles are in radians
+	"sin":  floatBuiltin(math.Sin),
+	"cos":  floatBuiltin(math.Cos),
+	"tan":  floatBuiltin(math.Tan),
+	"asin": floatBuiltin(math.Asin),
+	"acos": floatBuiltin(math.Acos),
+	"atan": floatBuiltin(math.Atan),
+
+	// atan2(y, x) is the angle of the point (x, y), using the signs of both to pick the quadrant
+	"atan2": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number", "number"}, Return: "float"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
 			}
 
-			if args[0].Type() == object.INTEGER_OBJ && args[1].Type() == object.INTEGER_OBJ {
-				return &object.Integer{Value: int64(math.Pow(float64(args[0].(*object.Integer).Value), float64(args[1].(*object.Integer).Value)))}
+			y, yOk := toNumber(args[0])
+			x, xOk := toNumber(args[1])
+			if !yOk || !xOk {
+				return &object.Error{Message: fmt.Sprintf("arguments to `atan2` not supported, got %s and %s", args[0].Type(), args[1].Type())}
 			}
 
-			if args[0].Type() == object.FLOAT_OBJ && args[1].Type() == object.FLOAT_OBJ {
-				return &object.Float{Value: math.Pow(args[0].(*object.Float).Value, args[1].(*object.Float).Value)}
+			return &object.Float{Value: math.Atan2(y, x)}
+		},
+	},
+
+	"min": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number", "number"}, Return: "number", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			return extremeNumber("min", args, -1)
+		},
+	},
+
+	"max": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number", "number"}, Return: "number", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			return extremeNumber("max", args, 1)
+		},
+	},
+
+	// clamp(x, lo, hi) limits x to between lo and hi
+	"clamp": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number", "number", "number"}, Return: "number"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 3 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=3", len(args))}
 			}
 
-			if args[0].Type() == object.FLOAT_OBJ && args[1].Type() == object.INTEGER_OBJ {
-				return &object.Float{Value: math.Pow(args[0].(*object.Float).Value, float64(args[1].(*object.Integer).Value))}
+			values := make([]float64, 3)
+			for i, arg := range args {
+				value, ok := toNumber(arg)
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("argument to `clamp` not supported, got %s", arg.Type())}
+				}
+				values[i] = value
+			}
+			if values[1] > values[2] {
+				return &object.Error{Message: fmt.Sprintf("lower bound %s is greater than upper bound %s", args[1].Inspect(), args[2].Inspect())}
 			}
 
-			return &object.Error{Message: fmt.Sprintf("arguments to `pow` not supported, got %s and %s", args[0].Type(), args[1].Type())}
+			if allIntegers(args) {
+				x, lo, hi := args[0].(*object.Integer).Value, args[1].(*object.Integer).Value, args[2].(*object.Integer).Value
+				return &object.Integer{Value: min(max(x, lo), hi)}
+			}
+			return &object.Float{Value: min(max(values[0], values[1]), values[2])}
+		},
+	},
+
+	// The greatest common divisor, which is never negative, and 0 only when both are 0
+// gcd and lcm give an error instead of wrapping around when the result doesn't fit in an integer
+	"gcd": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int", "int"}, Return: "int"},
+		Fn: func(args ...object.Object) object.Object {
+			a, b, err := integerPair("gcd", args)
+			if err != nil {
+				return err
+			}
+			divisor := gcd(a, b)
+			if divisor > math.MaxInt64 {
+				return &object.Error{Message: fmt.Sprintf("integer overflow: gcd(%d, %d)", a, b)}
+			}
+			return &object.Integer{Value: int64(divisor)}
+		},
+	},
+
+	// The least common multiple, which is never negative, and 0 when either is 0
+	"lcm": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int", "int"}, Return: "int"},
+		Fn: func(args ...object.Object) object.Object {
+			a, b, err := integerPair("lcm", args)
+			if err != nil {
+				return err
+			}
+			if a == 0 || b == 0 {
+				return &object.Integer{Value: 0}
+			}
+			hi, lcm := bits.Mul64(abs(a)/gcd(a, b), abs(b))
+			if hi != 0 || lcm > math.MaxInt64 {
+				return &object.Error{Message: fmt.Sprintf("integer overflow: lcm(%d, %d)", a, b)}
+			}
+			return &object.Integer{Value: int64(lcm)}
+		},
+	},
+
+	// Integers are never NaN or infinite
+	"isNaN": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			value, err := floatArg("isNaN", args)
+			if err != nil {
+				return err
+			}
+			return object.NativeBool(math.IsNaN(value))
+		},
+	},
+
+	"isInf": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			value, err := floatArg("isInf", args)
+			if err != nil {
+				return err
+			}
+			return object.NativeBool(math.IsInf(value, 0))
 		},
 	},
 }
+
+// math.PI and the like, which are floats
+// INF is positive infinity, -math.INF is negative infinity, and NAN is never equal to anything, even itself
+var MathConstants = map[string]object.Object{
+	"PI":  &object.Float{Value: math.Pi},
+	"E":   &object.Float{Value: math.E},
+	"INF": &object.Float{Value: math.Inf(1)},
+	"NAN": &object.Float{Value: math.NaN()},
+}
+
+// A function of one number that's computed in floating point, so it always returns a float
+func floatBuiltin(fn func(float64) float64) *object.Builtin {
+	return &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number"}, Return: "float"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+
+			value, ok := toNumber(args[0])
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("argument must be INTEGER or FLOAT, got %s", args[0].Type())}
+			}
+
+			return &object.Float{Value: fn(value)}
+		},
+	}
+}
+
+// A function rounding a number to an integer, integers are already whole and returned as they are
+func roundingBuiltin(name string, fn func(float64) float64) *object.Builtin {
+	return &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number"}, Return: "int"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+
+			switch arg := args[0].(type) {
+			case *object.Integer:
+				return arg
+			case *object.Float:
+				rounded := fn(arg.Value)
+				// NaN fails both comparisons, and 2^63 itself is already too big for an int
+				if !(rounded >= -(1<<63) && rounded < 1<<63) {
+					return &object.Error{Message: fmt.Sprintf("cannot %s %s to an integer", name, arg.Inspect())}
+				}
+				return &object.Integer{Value: int64(rounded)}
+			}
+
+			return &object.Error{Message: fmt.Sprintf("argument to `%s` not supported, got %s", name, args[0].Type())}
+		},
+	}
+}
+
+func toNumber(obj object.Object) (float64, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value), true
+	case *object.Float:
+		return obj.Value, true
+	}
+	return 0, false
+}
+
+func allIntegers(args []object.Object) bool {
+	for _, arg := range args {
+		if arg.Type() != object.INTEGER_OBJ {
+			return false
+		}
+	}
+	return true
+}
+
+// The smallest of the numbers when sign is -1, or the largest when it's 1
+// NaN is neither, so any NaN makes the result NaN
+func extremeNumber(name string, args []object.Object, sign int) object.Object {
+	if len(args) < 1 {
+		return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want >= 1", len(args))}
+	}
+
+	var best float64
+	var bestInt int64
+	for i, arg := range args {
+		value, ok := toNumber(arg)
+		if !ok {
+			return &object.Error{Message: fmt.Sprintf("argument to `%s` not supported, got %s", name, arg.Type())}
+		}
+		if math.IsNaN(value) {
+			return &object.Float{Value: value}
+		}
+
+		// Integers are compared as integers, since large ones lose precision as floats
+		if integer, ok := arg.(*object.Integer); ok && allIntegers(args) {
+			if i == 0 || cmp.Compare(integer.Value, bestInt) == sign {
+				bestInt = integer.Value
+			}
+			continue
+		}
+		if i == 0 || cmp.Compare(value, best) == sign {
+			best = value
+		}
+	}
+
+	if allIntegers(args) {
+		return &object.Integer{Value: bestInt}
+	}
+	return &object.Float{Value: best}
+}
+
+func integerPair(name string, args []object.Object) (int64, int64, object.Object) {
+	if len(args) != 2 {
+		return 0, 0, &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
+	}
+
+	a, aOk := args[0].(*object.Integer)
+	b, bOk := args[1].(*object.Integer)
+	if !aOk || !bOk {
+		return 0, 0, &object.Error{Message: fmt.Sprintf("arguments to `%s` must be INTEGER, got %s and %s", name, args[0].Type(), args[1].Type())}
+	}
+
+	return a.Value, b.Value, nil
+}
+
+// Works on the magnitudes, since gcd(MinInt64, 0) is 2^63, one more than int64 can hold
+func gcd(a, b int64) uint64 {
+	x, y := abs(a), abs(b)
+	for y != 0 {
+		x, y = y, x%y
+	}
+	return x
+}
+
+func abs(n int64) uint64 {
+	if n < 0 {
+		return -uint64(n)
+	}
+	return uint64(n)
+}
+
+func floatArg(name string, args []object.Object) (float64, object.Object) {
+	if len(args) != 1 {
+		return 0, &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+	}
+
+	value, ok := toNumber(args[0])
+	if !ok {
+		return 0, &object.Error{Message: fmt.Sprintf("argument to `%s` not supported, got %s", name, args[0].Type())}
+	}
+	return value, nil
+}
@@ -2,6 +2,12 @@ package modules
 
 import (
 	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/ajtroup1/clear/evaluator"
@@ -54,6 +60,90 @@ func TestArraysBuiltins(t *testing.T) {
 		evaluated := testEval(tt.input)
 		testExpectedObject(t, evaluated, tt.expected)
 	}
+
+	inspectTests := []struct {
+		input    string
+		expected string
+	}{
+		{`let xs = [3, 1.5, 2, -1]; [xs.sorted(), xs]`, "[[-1, 1.500000, 2, 3], [3, 1.500000, 2, -1]]"},
+		{`let xs = [3, 1, 2]; let same = xs.sort() === xs; [xs, same]`, "[[1, 2, 3], true]"},
+		{`["pear", "apple", "fig"].sorted()`, "[apple, fig, pear]"},
+		{`[1, 3, 2].sorted(fn(a, b) { b - a })`, "[3, 2, 1]"},
+		{`[[2, "b"], [1, "a"], [2, "a"]].sorted(fn(a, b) { a[0] - b[0] })`, "[[1, a], [2, b], [2, a]]"},
+		{`[time.hours(2), time.minutes(5)].sorted()`, "[5m0s, 2h0m0s]"},
+		{`type M { c: int }; impl M { fn compare(self, o) { self.c - o.c } } [M(3), M(1)].sorted()`, "[M { c: 1 }, M { c: 3 }]"},
+		{`type V { x: int }; impl V { fn __lt__(self, o) { self.x < o.x } } [V(3), V(1), V(2)].max()`, "V { x: 3 }"},
+		{`[1, "a"].sort()`, "cannot compare STRING with INTEGER"},
+		{`type P { x: int }; [P(1), P(2)].sort()`, "cannot compare P with P"},
+		{`[1, 2].sort(fn(a, b) { "a" })`, "compare function must return INTEGER, got STRING"},
+		{`let xs = freeze([2, 1]); xs.sort()`, "cannot sort a frozen array"},
+		{`[[1, 2, 3, 4].slice(1, -1), [1, 2].slice(5), [1, 2, 3].slice(-2), [1, 2, 3].slice(2, 1)]`, "[[2, 3], [], [2, 3], []]"},
+		{`let xs = [1, 4]; xs.insert(1, 2, 3); xs.insert(-1, 9); xs.insert(5, 10)`, "[1, 2, 3, 9, 4, 10]"},
+		{`[1, 2].insert(3, 0)`, "index out of range: 3 (length 2)"},
+		{`let xs = [1, 2, 3]; [xs.removeAt(-1), xs.removeAt(0), xs]`, "[3, 1, [2]]"},
+		{`[].removeAt(0)`, "index out of range: 0 (length 0)"},
+		{`let xs = freeze([1]); xs.insert(0, 0)`, "cannot insert into a frozen array"},
+		{`[[1, 2, 1].indexOf(1), [[1]].indexOf([1]), [1].indexOf(2)]`, "[0, 0, -1]"},
+		{`arrays.concat([1], [2, 3], [])`, "[1, 2, 3]"},
+		{`arrays.concat([1], 2)`, "argument 2 must be ARRAY, got INTEGER"},
+		{`[1, "a", true].join(", ")`, "1, a, true"},
+		{`[1, 1.0, "a", [1], [1], "a", 2, 1.5, 1.5].unique()`, "[1, a, [1], 2, 1.500000]"},
+		{`[[1, [2, [3, [4]]]].flatten(), [1, [2, [3, [4]]]].flatten(2), [1, [2, [3, [4]]]].flatten(-1)]`, "[[1, 2, [3, [4]]], [1, 2, 3, [4]], [1, 2, 3, 4]]"},
+		{`let xs = [1]; xs.push(xs); xs.flatten(-1)`, "cannot flatten an array that contains itself"},
+		{`arrays.zip([1, 2, 3], ["a", "b"], [true, false])`, "[[1, a, true], [2, b, false]]"},
+		{`[[1, 2, 3, 4, 5].chunk(2), [].chunk(3)]`, "[[[1, 2], [3, 4], [5]], []]"},
+		{`[1].chunk(0)`, "chunk size must be positive, got 0"},
+		{`[[1, 2, 2, 3].union([3, 4, 1]), [1, 2, 2, 3].intersection([3, 2.0, 5]), [1, 2, 2, 3].difference([2])]`, "[[1, 2, 3, 4], [2, 3], [1, 3]]"},
+		{`[[[1], "a"].union([[1], "b"]), [[1], {"a": 1}].intersection([{"a": 1}]), [].difference([1])]`, "[[[1], a, b], [{a: 1}], []]"},
+		{`arrays.union([1], 2)`, "argument 2 must be ARRAY, got INTEGER"},
+		{`[arrays.range(0, 5), arrays.range(5, 0, -2), arrays.range(3, 3)]`, "[[0, 1, 2, 3, 4], [5, 3, 1], []]"},
+		{`arrays.range(0, 5, 0)`, "range step cannot be 0"},
+		{`[[1, 2, 3].sum(), [1, 2.5].sum(), [].sum()]`, "[6, 3.500000, 0]"},
+		{`[1, "a"].sum()`, "cannot sum STRING, only numbers"},
+		{`[[3, 1, 2].min(), [3, 1, 2].max(), [].min(), ["b", "c", "a"].max(), [1, 2].min(fn(a, b) { b - a })]`, "[1, 3, null, c, 2]"},
+	}
+
+	for _, tt := range inspectTests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+// A sort that fails partway through leaves the array as it was
+func TestSortFailureLeavesArray(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`let xs = [3, 1, "a", 2]; xs.sort()`, "[3, 1, a, 2]"},
+		{`let xs = [3, 1, 2]; let calls = []; xs.sort(fn(a, b) { calls.push(1); if (calls.len() > 1) { "bad" } else { a - b } })`, "[3, 1, 2]"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input, logger.NewLogger(), false)
+		p := parser.New(l, logger.NewLogger(), false)
+		program := p.ParseProgram()
+		env := object.NewEnvironment()
+		Register(env)
+
+		evaluated := evaluator.Eval(program, env)
+		if _, ok := evaluated.(*object.Error); !ok {
+			t.Errorf("expected an error for %q, got %s", tt.input, evaluated.Inspect())
+			continue
+		}
+		xs, _ := env.Get("xs")
+		if xs.Inspect() != tt.expected {
+			t.Errorf("array changed by a failed sort in %q. expected=%q, got=%q", tt.input, tt.expected, xs.Inspect())
+		}
+	}
 }
 
 func TestStringsBuiltins(t *testing.T) {
@@ -84,6 +174,102 @@ func TestStringsBuiltins(t *testing.T) {
 		evaluated := testEval(tt.input)
 		testExpectedObject(t, evaluated, tt.expected)
 	}
+
+	inspectTests := []struct {
+		input    string
+		expected string
+	}{
+		{`"abcabc".contains("ca")`, "true"},
+		{`["abcabc".indexOf("c"), "abcabc".lastIndexOf("c"), "abc".indexOf("x")]`, "[2, 5, -1]"},
+		{`["aaaa".count("aa"), "abc".count("")]`, "[2, 4]"},
+		{`"ab".repeat(3)`, "ababab"},
+		{`"ab".repeat(-1)`, "count can't be negative, got -1"},
+		{`["7".padLeft(3, "0"), "ab".padRight(5, "xy"), "abc".padLeft(2), "a".padLeft(3) + "|"]`, "[007, abxyx, abc,   a|]"},
+		{`"a".padLeft(3, "")`, "padding can't be empty"},
+		{`"abc".chars()`, "[a, b, c]"},
+		{`"".chars()`, "[]"},
+		{`strings.join(["a", 1, true], ", ")`, "a, 1, true"},
+		{`strings.join([], ", ")`, ""},
+		{`"hello big  world".title()`, "Hello Big  World"},
+		{`["123".isDigit(), "12a".isDigit(), "".isDigit(), "abC".isAlpha(), "ab1".isAlpha()]`, "[true, false, false, true, false]"},
+		{`strings.format("%s=%05.2f %d %v", "x", 3.14159, 42, [1])`, "x=03.14 42 [1]"},
+		{`strings.format("plain")`, "plain"},
+		{`strings.format("%x|%-4d|%+.1e|%t|%q", 255, 7, 1500.0, true, "a")`, `ff|7   |+1.5e+03|true|"a"`},
+		{`strings.format("%z", 1)`, "unsupported format verb %z"},
+		{`strings.format("%5.1b", 1)`, "unsupported format verb %5.1b"},
+		{`strings.format("100%")`, "format \"100%\" ends without a verb after %"},
+		{`if ("abc".contains("z")) { 1 } else { 2 }`, "2"},
+		{`[!"12a".isDigit(), !"ab1".isAlpha(), !"".isDigit()]`, "[true, true, true]"},
+		{`let b = strings.builder("a"); if (b.reset()) { 1 } else { 2 }`, "2"},
+		{`"a".contains(1)`, "argument 1 must be STRING, got INTEGER"},
+		{`strings.chars(1)`, "argument must be STRING, got INTEGER"},
+		{`let b = strings.builder("n:"); for (i in 1..3) { b.write(i, ","); } b.write("!").build()`, "n:1,2,3,!"},
+		{`let b = strings.builder(); b.writeLine("a").write("b"); b.build().split("b")`, "[a\n, ]"},
+		{`let b = strings.builder("abc"); let n = b.len(); b.reset(); [n, b.len(), b.build()]`, "[3, 0, ]"},
+		{`strings.builder("x")`, "<builder x>"},
+		{`strings.builder("x").upper()`, "BUILDER has no member 'upper'"},
+		{`strings.write(strings.builder("x"), "y").build()`, "xy"},
+		{`strings.write("x", 1)`, "first argument must be BUILDER, got STRING"},
+	}
+
+	for _, tt := range inspectTests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+
+	// Positions count characters, which only differ from bytes outside ASCII
+	if index := StringsBuiltins["indexOf"].Fn(&object.String{Value: "héllo"}, &object.String{Value: "l"}); index.Inspect() != "2" {
+		t.Errorf("wrong index of l in héllo. expected=2, got=%s", index.Inspect())
+	}
+	if padded := StringsBuiltins["padLeft"].Fn(&object.String{Value: "é"}, &object.Integer{Value: 3}, &object.String{Value: "ü"}); padded.Inspect() != "üüé" {
+		t.Errorf("wrong padding. expected=%q, got=%q", "üüé", padded.Inspect())
+	}
+	if alpha := StringsBuiltins["isAlpha"].Fn(&object.String{Value: "äß"}); alpha.Inspect() != "true" {
+		t.Errorf("expected äß to be alphabetic")
+	}
+}
+
+func TestHashesBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`mod hashes: *; let h = {"b": 1, "a": 2, "c": 3}; hashes.keys(h);`, "[b, a, c]"},
+		{`mod hashes: *; let h = {"b": 1, "a": 2, "c": 3}; hashes.values(h);`, "[1, 2, 3]"},
+		{`mod hashes: *; let h = {"b": 1, "a": 2}; hashes.entries(h);`, "[[b, 1], [a, 2]]"},
+		{`mod hashes: *; let h = {"b": 1, "a": 2}; hashes.has(h, "a");`, "true"},
+		{`mod hashes: *; let h = {"b": 1, "a": 2}; hashes.has(h, "z");`, "false"},
+		{`mod hashes: *; let h = {"a": 1}; if (hashes.has(h, "z")) { "yes" } else { "no" }`, "no"},
+		{`mod hashes: *; !hashes.has({"a": 1}, "z");`, "true"},
+		{`mod hashes: *; let h = {"b": 1, "a": 2, "c": 3}; hashes.delete(h, "a"); h;`, "{b: 1, c: 3}"},
+		{`mod hashes: *; let h = {"b": 1}; hashes.size(hashes.delete(h, "b"));`, "0"},
+		{`mod hashes: *; hashes.merge({"a": 1, "b": 2}, {"c": 3, "a": 4});`, "{a: 4, b: 2, c: 3}"},
+		{`mod hashes: *; hashes.size({"a": 1, "b": 2});`, "2"},
+		{`mod hashes: *; hashes.fromEntries([["x", 1], ["y", [2]]]);`, "{x: 1, y: [2]}"},
+		{`mod hashes: *; let h = {"b": 1, "a": 2}; hashes.fromEntries(hashes.entries(h)) == h;`, "true"},
+		{`mod hashes: *; hashes.fromEntries([["x"]]);`, "ERROR: entry 0 must be an ARRAY of [key, value], got [x]"},
+		{`mod hashes: *; hashes.keys([1]);`, "ERROR: argument must be HASH, got ARRAY"},
+		{`mod hashes: *; hashes.has({}, fn() { 1 });`, "ERROR: unusable as hash key: FUNCTION"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated == nil {
+			t.Errorf("no object returned for %q", tt.input)
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. got=%q, want=%q", tt.input, evaluated.Inspect(), tt.expected)
+		}
+	}
 }
 
 func TestMathBuiltins(t *testing.T) {
@@ -100,12 +286,63 @@ func TestMathBuiltins(t *testing.T) {
 		{"mod math: [round]; math.round(5.4);", 5},
 		{"mod math: [pow]; math.pow(2, 3);", 8},
 		{"mod math: [pow]; math.pow(2.5, 3);", 15.625},
+		{"mod math: [pow]; math.pow(2, -1);", 0.5},
+		{"mod math: [pow]; math.pow(4, 0.5);", 2.0},
+		{"mod math: [pow]; math.pow(-2, 3);", -8},
 	}
 
 	for _, tt := range tests {
 		evaluated := testEval(tt.input)
 		testExpectedObject(t, evaluated, tt.expected)
 	}
+
+	inspectTests := []struct {
+		input    string
+		expected string
+	}{
+		{`[math.sqrt(16), math.cbrt(-27), math.exp(0), math.log(math.E), math.log2(8), math.log10(1000)]`, "[4.000000, -3.000000, 1.000000, 1.000000, 3.000000, 3.000000]"},
+		{`[math.sin(0), math.cos(math.PI), math.atan2(1, 1) * 4, math.asin(1) * 2, math.acos(1), math.atan(0), math.tan(0)]`, "[0.000000, -1.000000, 3.141593, 3.141593, 0.000000, 0.000000, 0.000000]"},
+		{`[math.isNaN(math.sqrt(-1)), math.isInf(math.log(0)), math.isNaN(1), math.isInf(math.INF), math.isInf(-math.INF), math.NAN == math.NAN]`, "[true, true, false, true, true, false]"},
+		{`[math.floor(2.7), math.ceil(2.1), math.trunc(-2.7), math.floor(-2.1), math.round(-2.5), math.ceil(3)]`, "[2, 3, -2, -3, -3, 3]"},
+		{`math.floor(math.NAN)`, "cannot floor NaN to an integer"},
+		{`math.round(math.INF)`, "cannot round +Inf to an integer"},
+		{`[math.min(3, 1, 2), math.max(1, 2.5), math.min(2, 1.5, 1), math.max(7), math.max(9007199254740993, 9007199254740992)]`, "[1, 2.500000, 1.000000, 7, 9007199254740993]"},
+		{`math.isNaN(math.min(1, math.NAN))`, "true"},
+		{`if (math.isNaN(1.5)) { "nan" } else { "number" }`, "number"},
+		{`[!math.isInf(1), !math.isNaN(math.sqrt(-1))]`, "[true, false]"},
+		{`math.max("a", 1)`, "argument to `max` not supported, got STRING"},
+		{`[math.clamp(15, 0, 10), math.clamp(-1, 0, 10), math.clamp(0.5, 1, 2), math.clamp(5, 0, 10)]`, "[10, 0, 1.000000, 5]"},
+		{`math.clamp(1, 2, 1)`, "lower bound 2 is greater than upper bound 1"},
+		{`[math.gcd(12, -18), math.gcd(0, 0), math.lcm(4, 6), math.lcm(-4, 6), math.lcm(0, 5)]`, "[6, 0, 12, 12, 0]"},
+		{`math.gcd(1.5, 2)`, "arguments to `gcd` must be INTEGER, got FLOAT and INTEGER"},
+		{`math.lcm(9223372036854775807, 2)`, "integer overflow: lcm(9223372036854775807, 2)"},
+		{`math.gcd(-9223372036854775807 - 1, 0)`, "integer overflow: gcd(-9223372036854775808, 0)"},
+		{`[math.gcd(-9223372036854775807 - 1, 6), math.lcm(3037000499, 3037000499)]`, "[2, 3037000499]"},
+		{`math.pow(2, "a")`, "arguments to `pow` not supported, got INTEGER and STRING"},
+		{`[math.PI, math.E]`, "[3.141593, 2.718282]"},
+		{`mod math: [PI, sqrt]; sqrt(PI * PI)`, "3.141593"},
+		{`mod math: [PI]; PI = 3;`, "cannot assign to constant: PI"},
+		{`math.TAU`, "function not found in module 'math': TAU"},
+		{`[math.stats.mean([1, 2, 3, 4]), math.stats.median([3, 1, 2, 10]), math.stats.median([5, 1, 3])]`, "[2.500000, 2.500000, 3.000000]"},
+		{`math.stats.stddev([2, 4, 4, 4, 5, 5, 7, 9])`, "2.000000"},
+		{`[math.stats.percentile([1, 2, 3, 4, 5], 90), math.stats.percentile([5, 1], 0), math.stats.percentile([5, 1], 100), math.stats.percentile([7], 50)]`, "[4.600000, 1.000000, 5.000000, 7.000000]"},
+		{`math.stats.percentile([1], 101)`, "percentile must be between 0 and 100, got 101"},
+		{`math.stats.mean([])`, "cannot take the mean of an empty array"},
+		{`math.stats.median([1, "a"])`, "cannot take the median of STRING, only numbers"},
+	}
+
+	for _, tt := range inspectTests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
 }
 
 func TestRandBuiltins(t *testing.T) {
@@ -175,6 +412,298 @@ func TestOSBuiltins(t *testing.T) {
 	}
 }
 
+func TestMemberAccess(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"abc".upper()`, "ABC"},
+		{`let s = "  hi  "; s.trimSpace().upper()`, "HI"},
+		{`"a,b".split(",").len()`, 2},
+		{"let arr = [1, 2]; arr.push(3); arr.len();", 3},
+		{"[3, 2, 1].reverse()", []int{1, 2, 3}},
+		{`let h = {"a": 1, "b": 2}; h.keys().len()`, 2},
+		{`let h = {"size": 10}; h.size`, 10},
+		{"let m = math; m.abs(-2)", 2},
+		{"let f = arrays.first; f([7, 8])", 7},
+		{"let first = [4, 5].first; first()", 4},
+		{"math.nope(1)", "function not found in module 'math': nope"},
+		{`"abc".nope()`, "STRING has no member 'nope'"},
+		{"let f = fn() { math.abs(-5) }; f()", 5},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if expected, ok := tt.expected.(string); ok {
+			if errObj, isErr := evaluated.(*object.Error); isErr {
+				if errObj.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+				}
+				continue
+			}
+		}
+		testExpectedObject(t, evaluated, tt.expected)
+	}
+}
+
+func TestNestedModules(t *testing.T) {
+	input := "outer.inner.double(21)"
+
+	log := logger.NewLogger()
+	l := lexer.New(input, log, false)
+	p := parser.New(l, log, false)
+	program := p.ParseProgram()
+	env := object.NewEnvironment()
+	env.SetModule("outer", map[string]*object.Builtin{})
+	env.SetModule("outer.inner", map[string]*object.Builtin{
+		"double": {Fn: func(args ...object.Object) object.Object {
+			return &object.Integer{Value: args[0].(*object.Integer).Value * 2}
+		}},
+	})
+
+	testExpectedObject(t, evaluator.Eval(program, env), 42)
+}
+
+func TestCollectionSignatures(t *testing.T) {
+	for _, module := range []string{"arrays", "strings", "hashes"} {
+		for name, builtin := range Modules[module] {
+			if builtin.Signature == nil {
+				t.Errorf("%s.%s has no signature", module, name)
+			}
+		}
+	}
+
+	tests := []struct {
+		builtin  *object.Builtin
+		expected string
+	}{
+		{ArraysBuiltins["push"], "fn<T>(array<T>, ...T) -> array<T>"},
+		{ArraysBuiltins["first"], "fn<T>(array<T>) -> T"},
+		{HashesBuiltins["keys"], "fn<K, V>(hash<K, V>) -> array<K>"},
+		{StringsBuiltins["split"], "fn(string, string) -> array<string>"},
+	}
+
+	for _, tt := range tests {
+		if actual := tt.builtin.Signature.String(); actual != tt.expected {
+			t.Errorf("wrong signature. expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestJSONBuiltins(t *testing.T) {
+	parse := JSONBuiltins["parse"].Fn
+	stringify := JSONBuiltins["stringify"].Fn
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"b": [1, 2.5, true, null], "a": {"x": "y"}}`, "{b: [1, 2.500000, true, null], a: {x: y}}"},
+		{`  "text"  `, "text"},
+		{`1e3`, "1000.000000"},
+		{`99999999999999999999`, "100000000000000000000.000000"},
+		// The decoder's own descriptions differ between Go versions, so only where the error is gets checked
+		{`{"a": }`, "invalid JSON at line 1, column 7:"},
+		{"{\n  \"a\": [1,\n", "invalid JSON at line 2, column 11:"},
+		{`[1, 2] 3`, "invalid JSON at line 1, column 8: unexpected data after the value"},
+		{``, "invalid JSON at line 1, column 1: unexpected EOF"},
+	}
+
+	for _, tt := range tests {
+		result := parse(&object.String{Value: tt.input})
+		if errObj, ok := result.(*object.Error); ok {
+			if !strings.HasPrefix(errObj.Message, tt.expected) {
+				t.Errorf("wrong error message for parse(%q). expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if result.Inspect() != tt.expected {
+			t.Errorf("wrong result for parse(%q). expected=%q, got=%q", tt.input, tt.expected, result.Inspect())
+		}
+	}
+
+	// Parsing and stringifying keeps the order of keys and whether numbers are floats
+	text := `{"z":1,"a":[2.0,"<&>",{}],"m":null}`
+	if result := stringify(parse(&object.String{Value: text})); result.Inspect() != text {
+		t.Errorf("wrong round trip. expected=%q, got=%q", text, result.Inspect())
+	}
+
+	// false and null are the shared values, so they branch as false wherever they're nested
+	parsed := parse(&object.String{Value: `{"off": false, "none": null, "list": [false, {"deep": null}]}`}).(*object.Hash)
+	for _, pair := range parsed.OrderedPairs() {
+		values := []object.Object{pair.Value}
+		if list, ok := pair.Value.(*object.Array); ok {
+			values = []object.Object{list.Items()[0], list.Items()[1].(*object.Hash).OrderedPairs()[0].Value}
+		}
+		for _, value := range values {
+			if value != object.FALSE && value != object.NULL {
+				t.Errorf("parsed %s isn't the shared false or null value", value.Inspect())
+			}
+		}
+	}
+
+	indented := stringify(parse(&object.String{Value: `{"a": [1], "b": []}`}), &object.Integer{Value: 2})
+	if expected := "{\n  \"a\": [\n    1\n  ],\n  \"b\": []\n}"; indented.Inspect() != expected {
+		t.Errorf("wrong indented JSON. expected=%q, got=%q", expected, indented.Inspect())
+	}
+
+	dir := t.TempDir()
+	files := map[string]string{
+		"array.json":  "[\n  {\"id\": 1},\n  {\"id\": 2}\n]\n",
+		"lines.jsonl": "{\"id\": 1}\n{\"id\": 2}\n",
+		"bad.jsonl":   "{\"id\": 1}\n{\"id\": }\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	evalTests := []struct {
+		input    string
+		expected string
+	}{
+		{`json.stringify({"s": "a", "n": [1, 1.5, false], 2: true})`, `{"s":"a","n":[1,1.5,false],"2":true}`},
+		{`type P { name, born }; json.stringify(P("ann", time.date(2000, 1, 2)))`, `{"name":"ann","born":"2000-01-02T00:00:00Z"}`},
+		{`json.stringify([1], "	")`, "[\n\t1\n]"},
+		{`if (json.parse("false")) { "yes" } else { "no" }`, "no"},
+		{`if (json.parse("null")) { "yes" } else { "no" }`, "no"},
+		{`let v = json.parse("[false, [null, true]]"); [!v[0], !v[1][0], !v[1][1]]`, "[true, true, false]"},
+		{`json.stream("` + filepath.Join(dir, "array.json") + `").map(fn(x) { x["id"] }).toArray()`, "[1, 2]"},
+		{`json.stream("` + filepath.Join(dir, "lines.jsonl") + `").map(fn(x) { x["id"] }).toArray()`, "[1, 2]"},
+		{`json.stream("` + filepath.Join(dir, "bad.jsonl") + `").toArray()`, "invalid JSON at line 2, column 8:"},
+		{`json.stringify(fn(x) { x })`, "can't convert FUNCTION to JSON"},
+		{`json.stringify({[1]: 2})`, "can't convert a hash with ARRAY keys to JSON, keys must be strings, integers or booleans"},
+		{`let a = [1]; a.push(a); json.stringify(a)`, "can't convert an array that contains itself to JSON"},
+	}
+
+	for _, tt := range evalTests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if !strings.HasPrefix(errObj.Message, tt.expected) {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestHTTPBuiltins(t *testing.T) {
+	// Echoes the request back, so the tests can check what was sent
+	echo := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Header().Set("X-Method", r.Method)
+		w.Header().Add("X-Many", "a")
+		w.Header().Add("X-Many", "b")
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		fmt.Fprintf(w, "%s|%s|%s", r.Header.Get("Content-Type"), r.Header.Get("X-Token"), body)
+	}))
+	defer echo.Close()
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`http.get("` + echo.URL + `").status`, "200"},
+		{`http.get("` + echo.URL + `/missing").status`, "404"},
+		{`http.get("` + echo.URL + `", {"headers": {"X-Token": "t"}}).body`, "|t|"},
+		{`http.get("` + echo.URL + `").headers["X-Many"]`, "a, b"},
+		{`http.post("` + echo.URL + `", "raw").body`, "||raw"},
+		{`http.post("` + echo.URL + `", {"a": [1]}).body`, `application/json||{"a":[1]}`},
+		{`http.request("PUT", "` + echo.URL + `").headers["X-Method"]`, "PUT"},
+		{`http.get("` + echo.URL + `", {"timeout": "soon"})`, "timeout must be DURATION or INTEGER, got STRING"},
+		{`http.get("http://localhost:0")`, "Get \"http://localhost:0\":"},
+
+		// Servers are stopped at the end of each program, otherwise it would keep running
+		{`let s = http.serve(0, fn(req) { req.method + " " + req.path + " " + req.query["q"] });
+		  let r = http.get(s.url() + "/a/b?q=1"); s.stop(); r.body`, "GET /a/b 1"},
+		{`let s = http.serve(0, fn(req) { {"status": 201, "headers": {"X-A": "b"}, "body": req.json()} });
+		  let r = http.post(s.url(), [1, 2]); s.stop(); [r.status, r.headers["X-A"], r.json()]`, "[201, b, [1, 2]]"},
+		{`let s = http.serve(0, fn(req) { {"n": 1} });
+		  let r = http.get(s.url()); s.stop(); r.headers["Content-Type"] + " " + r.body`, `application/json {"n":1}`},
+		{`let s = http.serve(0, fn(req) { 1 + req.body });
+		  let r = http.get(s.url()); s.stop(); r.status`, "500"},
+		{`let s = http.serve(0, fn(req) { s.stop() });
+		  [http.get(s.url()).body, s.stop()]`, "[true, false]"},
+		{`let s = http.serve(0, fn(req) { 1 }); s.stop(); if (s.stop()) { "again" } else { "once" }`, "once"},
+		{`let s = http.serve(0, http.router({
+		    "GET /users/:id": fn(req) { req.params["id"] },
+		    "/files/*": fn(req) { req.path },
+		  }));
+		  let r = [http.get(s.url() + "/users/7").body, http.get(s.url() + "/files/a/b").body,
+		    http.post(s.url() + "/users/7", "").status, http.get(s.url() + "/users").status];
+		  s.stop(); r`, "[7, /files/a/b, 405, 404]"},
+		{`http.serve(-1, fn(req) { req })`, "listen tcp: address -1: invalid port"},
+		{`http.serve(0, 1)`, "second argument must be FUNCTION"},
+		{`http.router({"/": 1})`, `the handler for "/" must be FUNCTION, got INTEGER`},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if !strings.HasPrefix(errObj.Message, tt.expected) {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestRegexBuiltins(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`regex.compile("a+b")`, "<regex a+b>"},
+		{`regex.compile("a+b").pattern()`, "a+b"},
+		{`let re = regex.compile("\d+"); [re.match("ab12"), re.match("ab")]`, "[true, false]"},
+		{`regex.match("^a+$", "aab")`, "false"},
+		{`if (regex.match("^a+$", "aab")) { "match" } else { "no match" }`, "no match"},
+		{`let g = regex.groups("(a)(x)?", "a"); [!regex.match("b", "a"), !regex.find("b", "a"), !g[2]]`, "[true, true, true]"},
+		{`regex.compile("\d+").find("ab12cd345")`, "12"},
+		{`regex.compile("\d+").find("abc")`, "null"},
+		{`regex.compile("\d+").findAll("1 22 333")`, "[1, 22, 333]"},
+		{`regex.compile("\d+").findAll("1 22 333", 2)`, "[1, 22]"},
+		{`regex.compile("\d+").findAll("abc")`, "[]"},
+		{`regex.compile("(?P<key>\w+)=(\w+)?").groups("a b=")`, "{key: b, 2: null}"},
+		{`regex.compile("(?P<key>\w+)=(\w+)").groups("x=1 y=2")["key"]`, "x"},
+		{`regex.compile("(\w+)=").groups("none")`, "null"},
+		{`regex.compile("(?P<k>\w+)=(\w+)").replace("a=1 b=2", "$2:${k}")`, "1:a 2:b"},
+		{`regex.compile("\d").replace("a1b2", "$$")`, "a$b$"},
+		{`regex.compile("\w+").replace("ab cd", strings.upper)`, "AB CD"},
+		{`regex.compile("\w+").replace("ab cd", fn(m) { m.len() })`, "replacement function must return STRING, got INTEGER"},
+		{`regex.compile("\w+").replace("ab cd", fn(m) { 1 + m })`, "type mismatch: INTEGER + STRING"},
+		{`regex.compile("\w+").replace("ab", 1)`, "replacement must be STRING or FUNCTION, got INTEGER"},
+		{`regex.split("\s*,\s*", "a , b,c")`, "[a, b, c]"},
+		{`regex.split(",", "a,b,c", 2)`, "[a, b,c]"},
+		{`regex.compile("(a")`, "invalid regex `(a`: missing closing )"},
+		{`regex.compile("a\q")`, "invalid regex `a\\q`: invalid escape sequence `\\q`"},
+		{`regex.match("[z-a]", "a")`, "invalid regex `[z-a]`: invalid character class range `z-a`"},
+		{`regex.match(1, "a")`, "first argument to `match` must be REGEX or STRING, got INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if errObj, ok := evaluated.(*object.Error); ok {
+			if errObj.Message != tt.expected {
+				t.Errorf("wrong error message for %q. expected=%q, got=%q", tt.input, tt.expected, errObj.Message)
+			}
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong result for %q. expected=%q, got=%q", tt.input, tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
 func testEval(input string) object.Object {
 	fmt.Print()
 	log := logger.NewLogger()
@@ -191,6 +720,15 @@ func testExpectedObject(t *testing.T, obj object.Object, expected interface{}) {
 	switch expected := expected.(type) {
 	case int:
 		testIntegerObject(t, obj, int64(expected))
+	case float64:
+		result, ok := obj.(*object.Float)
+		if !ok {
+			t.Errorf("object is not Float. got=%T (%+v)", obj, obj)
+			return
+		}
+		if result.Value != expected {
+			t.Errorf("object has wrong value. got=%f, want=%f", result.Value, expected)
+		}
 	case string:
 		str, ok := obj.(*object.String)
 		if !ok {
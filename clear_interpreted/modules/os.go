@@ -6,6 +6,7 @@ import (
 
 var OSBuiltins = map[string]*object.Builtin{
 	"exit": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int"}, Return: "any"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: "wrong number of arguments"}
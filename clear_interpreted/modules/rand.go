@@ -10,6 +10,7 @@ import (
 
 var RandBuiltins = map[string]*object.Builtin{
 	"rand": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int", "int"}, Return: "int"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: "wrong number of arguments"}
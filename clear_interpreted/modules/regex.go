@@ -0,0 +1,252 @@
+package modules
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+
+	"github.com/ajtroup1/clear/object"
+)
+
+// Patterns use Go's RE2 syntax, so there's no backtracking and matching always takes linear time
+// Every function takes a compiled regex or a pattern string, which is compiled for that one call
+var RegexBuiltins = map[string]*object.Builtin{
+	"compile": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "regex"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			pattern, ok := args[0].(*object.String)
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("pattern must be STRING, got %s", args[0].Type())}
+			}
+
+			return compileRegex(pattern.Value)
+		},
+	},
+
+	// Reports whether the regex matches anywhere in the string, use ^ and $ to match all of it
+	"match": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "string"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			re, str, err := regexArgs("match", args, 2)
+			if err != nil {
+				return err
+			}
+			return object.NativeBool(re.MatchString(str))
+		},
+	},
+
+	// The first match, or null when there's none
+	"find": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "string"}, Return: "any"},
+		Fn: func(args ...object.Object) object.Object {
+			re, str, err := regexArgs("find", args, 2)
+			if err != nil {
+				return err
+			}
+
+			loc := re.FindStringIndex(str)
+			if loc == nil {
+				return object.NULL
+			}
+			return &object.String{Value: str[loc[0]:loc[1]]}
+		},
+	},
+
+	// findAll(re, str, n?) finds every match that doesn't overlap the one before, or the first n of them
+	"findAll": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "string", "int"}, Return: "array<string>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			re, str, err := regexArgs("findAll", args, 3)
+			if err != nil {
+				return err
+			}
+			n, err := regexLimit(args)
+			if err != nil {
+				return err
+			}
+			return stringArray(re.FindAllString(str, n))
+		},
+	},
+
+	// The groups of the first match, or null when there's none
+	// Named groups are keyed by their name and the others by their number, starting from 1,
+	// and groups that didn't take part in the match are null
+	"groups": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "string"}, Return: "any"},
+		Fn: func(args ...object.Object) object.Object {
+			re, str, err := regexArgs("groups", args, 2)
+			if err != nil {
+				return err
+			}
+
+			loc := re.FindStringSubmatchIndex(str)
+			if loc == nil {
+				return object.NULL
+			}
+
+			groups := object.NewHash()
+			for i, name := range re.SubexpNames()[1:] {
+				var key object.Object = &object.Integer{Value: int64(i + 1)}
+				if name != "" {
+					key = &object.String{Value: name}
+				}
+
+				var value object.Object = object.NULL
+				if start, end := loc[2*i+2], loc[2*i+3]; start >= 0 {
+					value = &object.String{Value: str[start:end]}
+				}
+
+				hashKey, _ := object.HashKeyOf(key)
+				groups.Set(hashKey, object.HashPair{Key: key, Value: value})
+			}
+			return groups
+		},
+	},
+
+	// replace(re, str, replacement) replaces every match
+	// A string replacement can refer to groups as $1 or ${name}, use $$ for a plain $
+	// A function replacement is called with each match and returns what to put in its place
+	"replace": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "string", "any"}, Return: "string"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 3 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+			re, str, err := regexArgs("replace", args, 3)
+			if err != nil {
+				return err
+			}
+
+			switch replacement := args[2].(type) {
+			case *object.String:
+				return &object.String{Value: re.ReplaceAllString(str, replacement.Value)}
+
+			case *object.Function, *object.Builtin:
+				var failed object.Object
+				result := re.ReplaceAllStringFunc(str, func(match string) string {
+					if failed != nil {
+						return ""
+					}
+					replaced := object.CallFunction(replacement, &object.String{Value: match})
+					if s, ok := replaced.(*object.String); ok {
+						return s.Value
+					}
+					if replaced.Type() == object.ERROR_OBJ {
+						failed = replaced
+					} else {
+						failed = &object.Error{Message: fmt.Sprintf("replacement function must return STRING, got %s", replaced.Type())}
+					}
+					return ""
+				})
+				if failed != nil {
+					return failed
+				}
+				return &object.String{Value: result}
+
+			default:
+				return &object.Error{Message: fmt.Sprintf("replacement must be STRING or FUNCTION, got %s", args[2].Type())}
+			}
+		},
+	},
+
+	// split(re, str, n?) splits the string around each match, into at most n parts when n is given
+	"split": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any", "string", "int"}, Return: "array<string>", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			re, str, err := regexArgs("split", args, 3)
+			if err != nil {
+				return err
+			}
+			n, err := regexLimit(args)
+			if err != nil {
+				return err
+			}
+			return stringArray(re.Split(str, n))
+		},
+	},
+
+	// The pattern the regex was compiled from
+	"pattern": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"regex"}, Return: "string"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			re, ok := args[0].(*object.Regex)
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("argument must be REGEX, got %s", args[0].Type())}
+			}
+			return &object.String{Value: re.Value.String()}
+		},
+	},
+}
+
+// Compiles the pattern, describing what's wrong with it when it isn't valid
+func compileRegex(pattern string) object.Object {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			msg := fmt.Sprintf("invalid regex `%s`: %s", pattern, syntaxErr.Code)
+			if syntaxErr.Expr != "" && syntaxErr.Expr != pattern {
+				msg += fmt.Sprintf(" `%s`", syntaxErr.Expr)
+			}
+			return &object.Error{Message: msg}
+		}
+		return &object.Error{Message: fmt.Sprintf("invalid regex `%s`: %s", pattern, err)}
+	}
+	return &object.Regex{Value: re}
+}
+
+// Checks the regex and string every function starts with, allowing up to max arguments in all
+func regexArgs(name string, args []object.Object, max int) (*regexp.Regexp, string, object.Object) {
+	if len(args) < 2 || len(args) > max {
+		return nil, "", &object.Error{Message: "wrong number of arguments"}
+	}
+
+	var re *regexp.Regexp
+	switch arg := args[0].(type) {
+	case *object.Regex:
+		re = arg.Value
+	case *object.String:
+		compiled := compileRegex(arg.Value)
+		if compiled.Type() == object.ERROR_OBJ {
+			return nil, "", compiled
+		}
+		re = compiled.(*object.Regex).Value
+	default:
+		return nil, "", &object.Error{Message: fmt.Sprintf("first argument to `%s` must be REGEX or STRING, got %s", name, args[0].Type())}
+	}
+
+	str, ok := args[1].(*object.String)
+	if !ok {
+		return nil, "", &object.Error{Message: fmt.Sprintf("second argument to `%s` must be STRING, got %s", name, args[1].Type())}
+	}
+	return re, str.Value, nil
+}
+
+// The optional third argument limiting how many results there are, -1 for all of them
+func regexLimit(args []object.Object) (int, object.Object) {
+	if len(args) < 3 {
+		return -1, nil
+	}
+	n, ok := args[2].(*object.Integer)
+	if !ok {
+		return 0, &object.Error{Message: fmt.Sprintf("third argument must be INTEGER, got %s", args[2].Type())}
+	}
+	return int(n.Value), nil
+}
+
+func stringArray(values []string) *object.Array {
+	array := &object.Array{Elements: make([]object.Object, 0, len(values))}
+	for _, value := range values {
+		array.Elements = append(array.Elements, &object.String{Value: value})
+	}
+	return array
+}
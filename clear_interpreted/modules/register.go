@@ -2,15 +2,36 @@ package modules
 
 import "github.com/ajtroup1/clear/object"
 
+// Every module by the name it is registered under
+// The checker reads the signatures of these functions, so it has to be able to find them without an environment
+var Modules = map[string]map[string]*object.Builtin{
+	"math":       MathBuiltins,
+	"math.stats": StatsBuiltins,
+	"strings":    StringsBuiltins,
+	"arrays":     ArraysBuiltins,
+	"hashes":     HashesBuiltins,
+	"rand":       RandBuiltins,
+	"io":         IOBuiltins,
+	"os":         OSBuiltins,
+	"time":       TimeBuiltins,
+	"file":       FileBuiltins,
+	"iter":       IterBuiltins,
+	"sync":       SyncBuiltins,
+	"json":       JSONBuiltins,
+	"http":       HTTPBuiltins,
+	"regex":      RegexBuiltins,
+}
+
+// The values modules have besides their functions, read by the checker like Modules
+var Constants = map[string]map[string]object.Object{
+	"math": MathConstants,
+}
+
 func Register(env *object.Environment) {
-	env.SetModule("math", MathBuiltins)
-	env.SetModule("strings", StringsBuiltins)
-	env.SetModule("arrays", ArraysBuiltins)
-	env.SetModule("rand", RandBuiltins)
-	env.SetModule("io", IOBuiltins)
-	env.SetModule("os", OSBuiltins)
-	env.SetModule("time", TimeBuiltins)
-	env.SetModule("file", FileBuiltins)
-	// env.SetModule("json", JSONBuiltins)
-	// env.SetModule("http", HTTPBuiltins)
+	for name, functions := range Modules {
+		env.SetModule(name, functions)
+	}
+	for name, constants := range Constants {
+		env.SetModuleConstants(name, constants)
+	}
 }
@@ -0,0 +1,120 @@
+package modules
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/ajtroup1/clear/object"
+)
+
+// Statistics over arrays of numbers, registered as math.stats
+// Every result is a float, even for arrays of integers, and empty arrays are an error since they have no statistics
+var StatsBuiltins = map[string]*object.Builtin{
+	"mean": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array<number>"}, Return: "float"},
+		Fn: func(args ...object.Object) object.Object {
+			values, err := statsArgs("mean", args, 1)
+			if err != nil {
+				return err
+			}
+			return &object.Float{Value: mean(values)}
+		},
+	},
+
+	// The middle value once they're sorted, or the mean of the two middle values when there's an even number of them
+	"median": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array<number>"}, Return: "float"},
+		Fn: func(args ...object.Object) object.Object {
+			values, err := statsArgs("median", args, 1)
+			if err != nil {
+				return err
+			}
+			sort.Float64s(values)
+			return &object.Float{Value: percentile(values, 50)}
+		},
+	},
+
+	// The population standard deviation, dividing by the number of values rather than one less
+	"stddev": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array<number>"}, Return: "float"},
+		Fn: func(args ...object.Object) object.Object {
+			values, err := statsArgs("stddev", args, 1)
+			if err != nil {
+				return err
+			}
+
+			m := mean(values)
+			var squares float64
+			for _, value := range values {
+				squares += (value - m) * (value - m)
+			}
+			return &object.Float{Value: math.Sqrt(squares / float64(len(values)))}
+		},
+	},
+
+	// percentile(arr, p) is the value p percent of the way through the sorted values, for p from 0 to 100
+	// Between two values, it's interpolated linearly, so percentile(arr, 50) is the median
+	"percentile": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array<number>", "number"}, Return: "float"},
+		Fn: func(args ...object.Object) object.Object {
+			values, err := statsArgs("percentile", args, 2)
+			if err != nil {
+				return err
+			}
+
+			p, ok := toNumber(args[1])
+			if !ok {
+				return &object.Error{Message: fmt.Sprintf("second argument must be INTEGER or FLOAT, got %s", args[1].Type())}
+			}
+			if !(p >= 0 && p <= 100) {
+				return &object.Error{Message: fmt.Sprintf("percentile must be between 0 and 100, got %s", args[1].Inspect())}
+			}
+
+			sort.Float64s(values)
+			return &object.Float{Value: percentile(values, p)}
+		},
+	},
+}
+
+// Checks the arguments, returning the numbers in the array as floats
+func statsArgs(name string, args []object.Object, want int) ([]float64, object.Object) {
+	if len(args) != want {
+		return nil, &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=%d", len(args), want)}
+	}
+
+	arr, ok := args[0].(*object.Array)
+	if !ok {
+		return nil, &object.Error{Message: fmt.Sprintf("first argument must be ARRAY, got %s", args[0].Type())}
+	}
+	elements := arr.Items()
+	if len(elements) == 0 {
+		return nil, &object.Error{Message: fmt.Sprintf("cannot take the %s of an empty array", name)}
+	}
+
+	values := make([]float64, len(elements))
+	for i, el := range elements {
+		value, ok := toNumber(el)
+		if !ok {
+			return nil, &object.Error{Message: fmt.Sprintf("cannot take the %s of %s, only numbers", name, el.Type())}
+		}
+		values[i] = value
+	}
+	return values, nil
+}
+
+func mean(values []float64) float64 {
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	return sum / float64(len(values))
+}
+
+// The values have to be sorted already
+func percentile(values []float64, p float64) float64 {
+	rank := p / 100 * float64(len(values)-1)
+	lower := int(math.Floor(rank))
+	upper := int(math.Ceil(rank))
+	return values[lower] + (values[upper]-values[lower])*(rank-float64(lower))
+}
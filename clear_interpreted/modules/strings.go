@@ -3,22 +3,28 @@ package modules
 import (
 	"fmt"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/ajtroup1/clear/object"
 )
 
 var StringsBuiltins = map[string]*object.Builtin{
 	"len": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"any"}, Return: "int"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
 			}
 			switch arg := args[0].(type) {
 			case *object.Array:
-				return &object.Integer{Value: int64(len(arg.Elements))}
+				return &object.Integer{Value: int64(len(arg.Items()))}
 
 			case *object.String:
 				return &object.Integer{Value: int64(len(arg.Value))}
+
+			case *object.StringBuilder:
+				return &object.Integer{Value: int64(arg.Len())}
 			default:
 				return &object.Error{Message: fmt.Sprintf("argument to `len` not supported, got type %s", arg.Type())}
 			}
@@ -26,6 +32,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"concat": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "string", Variadic: true},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) < 2 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want >= 2", len(args))}
@@ -50,6 +57,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"concatDelim": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "string", Variadic: true},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) < 2 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want >= 2", len(args))}
@@ -85,6 +93,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"split": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "array<string>"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
@@ -113,6 +122,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"lower": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "string"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
@@ -128,6 +138,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"upper": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "string"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
@@ -143,6 +154,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"replace": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string", "string"}, Return: "string"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 3 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=3", len(args))}
@@ -163,6 +175,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"trimSpace": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "string"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 1 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
@@ -178,6 +191,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"trimPrefix": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "string"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
@@ -197,6 +211,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"trimSuffix": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "string"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
@@ -216,6 +231,7 @@ var StringsBuiltins = map[string]*object.Builtin{
 	},
 
 	"hasPrefix": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "bool"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
@@ -230,11 +246,12 @@ var StringsBuiltins = map[string]*object.Builtin{
 			strArg := args[0].(*object.String)
 			prefixArg := args[1].(*object.String)
 
-			return &object.Boolean{Value: strings.HasPrefix(strArg.Value, prefixArg.Value)}
+			return object.NativeBool(strings.HasPrefix(strArg.Value, prefixArg.Value))
 		},
 	},
 
 	"hasSuffix": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "bool"},
 		Fn: func(args ...object.Object) object.Object {
 			if len(args) != 2 {
 				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
@@ -249,7 +266,431 @@ var StringsBuiltins = map[string]*object.Builtin{
 			strArg := args[0].(*object.String)
 			suffixArg := args[1].(*object.String)
 
-			return &object.Boolean{Value: strings.HasSuffix(strArg.Value, suffixArg.Value)}
+			return object.NativeBool(strings.HasSuffix(strArg.Value, suffixArg.Value))
+		},
+	},
+
+	"contains": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkStringArgs(args, 2); err != nil {
+				return err
+			}
+
+			strArg := args[0].(*object.String)
+			subArg := args[1].(*object.String)
+
+			return object.NativeBool(strings.Contains(strArg.Value, subArg.Value))
+		},
+	},
+
+	// Like indexing, the position counts characters rather than bytes, and is -1 when the string isn't found
+	"indexOf": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "int"},
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkStringArgs(args, 2); err != nil {
+				return err
+			}
+
+			strArg := args[0].(*object.String)
+			subArg := args[1].(*object.String)
+
+			return &object.Integer{Value: charIndex(strArg.Value, strings.Index(strArg.Value, subArg.Value))}
+		},
+	},
+
+	"lastIndexOf": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "int"},
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkStringArgs(args, 2); err != nil {
+				return err
+			}
+
+			strArg := args[0].(*object.String)
+			subArg := args[1].(*object.String)
+
+			return &object.Integer{Value: charIndex(strArg.Value, strings.LastIndex(strArg.Value, subArg.Value))}
+		},
+	},
+
+	// Counts the times the string appears without overlapping, an empty string is counted between every character
+	"count": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string"}, Return: "int"},
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkStringArgs(args, 2); err != nil {
+				return err
+			}
+
+			strArg := args[0].(*object.String)
+			subArg := args[1].(*object.String)
+
+			return &object.Integer{Value: int64(strings.Count(strArg.Value, subArg.Value))}
+		},
+	},
+
+	"repeat": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "int"}, Return: "string"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
+			}
+
+			if args[0].Type() != object.STRING_OBJ {
+				return &object.Error{Message: fmt.Sprintf("first argument must be STRING, got %s", args[0].Type())}
+			}
+
+			if args[1].Type() != object.INTEGER_OBJ {
+				return &object.Error{Message: fmt.Sprintf("second argument must be INTEGER, got %s", args[1].Type())}
+			}
+
+			strArg := args[0].(*object.String)
+			countArg := args[1].(*object.Integer)
+			if countArg.Value < 0 {
+				return &object.Error{Message: fmt.Sprintf("count can't be negative, got %d", countArg.Value)}
+			}
+
+			return &object.String{Value: strings.Repeat(strArg.Value, int(countArg.Value))}
+		},
+	},
+
+	// padLeft(str, width, pad?) pads the start of the string to width characters, with spaces unless pad is given
+	// Strings already as wide are left alone
+	"padLeft": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "int", "string"}, Return: "string", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			padding, err := padArgs(args)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: padding + args[0].(*object.String).Value}
+		},
+	},
+
+	// padRight(str, width, pad?) is padLeft for the end of the string
+	"padRight": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "int", "string"}, Return: "string", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			padding, err := padArgs(args)
+			if err != nil {
+				return err
+			}
+			return &object.String{Value: args[0].(*object.String).Value + padding}
+		},
+	},
+
+	"chars": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "array<string>"},
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkStringArgs(args, 1); err != nil {
+				return err
+			}
+
+			strArg := args[0].(*object.String)
+
+			array := &object.Array{}
+			for _, char := range strArg.Value {
+				array.Elements = append(array.Elements, &object.String{Value: string(char)})
+			}
+
+			return array
+		},
+	},
+
+	// Joins the elements of an array with the delimiter between them, elements that aren't strings are joined as they're printed
+	"join": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array", "string"}, Return: "string"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2", len(args))}
+			}
+
+			if args[0].Type() != object.ARRAY_OBJ {
+				return &object.Error{Message: fmt.Sprintf("first argument must be ARRAY, got %s", args[0].Type())}
+			}
+
+			if args[1].Type() != object.STRING_OBJ {
+				return &object.Error{Message: fmt.Sprintf("second argument must be STRING, got %s", args[1].Type())}
+			}
+
+			arrArg := args[0].(*object.Array)
+			delimiterArg := args[1].(*object.String)
+
+			return &object.String{Value: joinElements(arrArg, delimiterArg.Value)}
 		},
 	},
+
+	// Capitalizes the first letter of every word, leaving the rest as they are
+	"title": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "string"},
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkStringArgs(args, 1); err != nil {
+				return err
+			}
+
+			strArg := args[0].(*object.String)
+
+			var out strings.Builder
+			startOfWord := true
+			for _, char := range strArg.Value {
+				if startOfWord {
+					out.WriteRune(unicode.ToTitle(char))
+				} else {
+					out.WriteRune(char)
+				}
+				startOfWord = unicode.IsSpace(char)
+			}
+
+			return &object.String{Value: out.String()}
+		},
+	},
+
+	// Reports whether the string is all digits, false when it's empty
+	"isDigit": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkStringArgs(args, 1); err != nil {
+				return err
+			}
+			return object.NativeBool(allChars(args[0].(*object.String).Value, unicode.IsDigit))
+		},
+	},
+
+	// Reports whether the string is all letters, false when it's empty
+	"isAlpha": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			if err := checkStringArgs(args, 1); err != nil {
+				return err
+			}
+			return object.NativeBool(allChars(args[0].(*object.String).Value, unicode.IsLetter))
+		},
+	},
+
+	// format(format, values...) formats the values like io.printf, returning the string instead of printing it
+	// Verbs can have flags, a width and a precision, but only %v, %s, %d, %f, %g, %e, %t, %q, %x, %X and %% are supported
+	"format": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "any"}, Return: "string", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) < 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want >= 1", len(args))}
+			}
+
+			if args[0].Type() != object.STRING_OBJ {
+				return &object.Error{Message: fmt.Sprintf("first argument must be STRING, got %s", args[0].Type())}
+			}
+
+			formatArg := args[0].(*object.String)
+			if err := checkFormat(formatArg.Value); err != nil {
+				return err
+			}
+			if len(args) == 1 {
+				return &object.String{Value: formatArg.Value}
+			}
+
+			return &object.String{Value: fmt.Sprintf(formatArg.Value, formatValues(args[1:])...)}
+		},
+	},
+
+	// builder(str?) makes a string builder, for building a string up in a loop without copying it every time
+	// It starts out holding str when that's given
+	"builder": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "builder", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) > 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want <= 1", len(args))}
+			}
+
+			builder := &object.StringBuilder{}
+			if len(args) == 1 {
+				if args[0].Type() != object.STRING_OBJ {
+					return &object.Error{Message: fmt.Sprintf("argument must be STRING, got %s", args[0].Type())}
+				}
+				builder.Write(args[0].(*object.String).Value)
+			}
+
+			return builder
+		},
+	},
+
+	// write(builder, values...) adds the values to the end of the builder, values that aren't strings as they're printed
+	// It returns the builder, so writes can be chained
+	"write": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"builder", "any"}, Return: "builder", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			return writeBuilder(args, "")
+		},
+	},
+
+	// writeLine(builder, values...) is write followed by a newline
+	"writeLine": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"builder", "any"}, Return: "builder", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			return writeBuilder(args, "\n")
+		},
+	},
+
+	// The string built so far
+	"build": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"builder"}, Return: "string"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+
+			if args[0].Type() != object.BUILDER_OBJ {
+				return &object.Error{Message: fmt.Sprintf("argument must be BUILDER, got %s", args[0].Type())}
+			}
+
+			return &object.String{Value: args[0].(*object.StringBuilder).String()}
+		},
+	},
+
+	// Empties the builder so it can be used again
+	"reset": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"builder"}, Return: "null"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=1", len(args))}
+			}
+
+			if args[0].Type() != object.BUILDER_OBJ {
+				return &object.Error{Message: fmt.Sprintf("argument must be BUILDER, got %s", args[0].Type())}
+			}
+
+			args[0].(*object.StringBuilder).Reset()
+			return object.NULL
+		},
+	},
+}
+
+// Checks every verb in the format is one format supports, rather than letting Go print its own complaint in the result
+func checkFormat(format string) object.Object {
+	for i := 0; i < len(format); i++ {
+		if format[i] != '%' {
+			continue
+		}
+
+		start := i
+		i++
+		for i < len(format) && strings.ContainsRune("+-# 0123456789.", rune(format[i])) {
+			i++
+		}
+		if i == len(format) {
+			return &object.Error{Message: fmt.Sprintf("format %q ends without a verb after %%", format)}
+		}
+		if !strings.ContainsRune("vsdfgetqxX%", rune(format[i])) {
+			return &object.Error{Message: fmt.Sprintf("unsupported format verb %s", format[start:i+1])}
+		}
+	}
+	return nil
+}
+
+// Checks there are want arguments and that they're all strings
+func checkStringArgs(args []object.Object, want int) object.Object {
+	if len(args) != want {
+		return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=%d", len(args), want)}
+	}
+
+	for i, arg := range args {
+		if arg.Type() != object.STRING_OBJ {
+			if want == 1 {
+				return &object.Error{Message: fmt.Sprintf("argument must be STRING, got %s", arg.Type())}
+			}
+			return &object.Error{Message: fmt.Sprintf("argument %d must be STRING, got %s", i, arg.Type())}
+		}
+	}
+
+	return nil
+}
+
+// Joins the elements of an array, used by strings.join and arrays.join
+func joinElements(arr *object.Array, delimiter string) string {
+	elements := arr.Items()
+	parts := make([]string, len(elements))
+	for i, element := range elements {
+		if str, ok := element.(*object.String); ok {
+			parts[i] = str.Value
+		} else {
+			parts[i] = element.Inspect()
+		}
+	}
+	return strings.Join(parts, delimiter)
+}
+
+// Turns a byte offset into the string into the number of characters before it, leaving -1 as it is
+func charIndex(str string, offset int) int64 {
+	if offset < 0 {
+		return -1
+	}
+	return int64(utf8.RuneCountInString(str[:offset]))
+}
+
+// Checks the arguments to padLeft and padRight, returning the padding the string needs
+func padArgs(args []object.Object) (string, object.Object) {
+	if len(args) != 2 && len(args) != 3 {
+		return "", &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want=2 or 3", len(args))}
+	}
+
+	if args[0].Type() != object.STRING_OBJ {
+		return "", &object.Error{Message: fmt.Sprintf("first argument must be STRING, got %s", args[0].Type())}
+	}
+
+	if args[1].Type() != object.INTEGER_OBJ {
+		return "", &object.Error{Message: fmt.Sprintf("second argument must be INTEGER, got %s", args[1].Type())}
+	}
+
+	pad := " "
+	if len(args) == 3 {
+		if args[2].Type() != object.STRING_OBJ {
+			return "", &object.Error{Message: fmt.Sprintf("third argument must be STRING, got %s", args[2].Type())}
+		}
+		pad = args[2].(*object.String).Value
+		if pad == "" {
+			return "", &object.Error{Message: "padding can't be empty"}
+		}
+	}
+
+	missing := int(args[1].(*object.Integer).Value) - utf8.RuneCountInString(args[0].(*object.String).Value)
+	if missing <= 0 {
+		return "", nil
+	}
+
+	// Longer pads are repeated and cut off, so the result is always exactly width characters
+	padChars := []rune(strings.Repeat(pad, missing/utf8.RuneCountInString(pad)+1))
+	return string(padChars[:missing]), nil
+}
+
+func allChars(str string, is func(rune) bool) bool {
+	if str == "" {
+		return false
+	}
+	for _, char := range str {
+		if !is(char) {
+			return false
+		}
+	}
+	return true
+}
+
+func writeBuilder(args []object.Object, end string) object.Object {
+	if len(args) < 1 {
+		return &object.Error{Message: fmt.Sprintf("wrong number of arguments. got=%d, want >= 1", len(args))}
+	}
+
+	if args[0].Type() != object.BUILDER_OBJ {
+		return &object.Error{Message: fmt.Sprintf("first argument must be BUILDER, got %s", args[0].Type())}
+	}
+
+	pieces := make([]string, 0, len(args))
+	for _, arg := range args[1:] {
+		if str, ok := arg.(*object.String); ok {
+			pieces = append(pieces, str.Value)
+		} else {
+			pieces = append(pieces, arg.Inspect())
+		}
+	}
+	builder := args[0].(*object.StringBuilder)
+	builder.Write(append(pieces, end)...)
+
+	return builder
 }
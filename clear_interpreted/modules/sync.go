@@ -0,0 +1,98 @@
+package modules
+
+import "github.com/ajtroup1/clear/object"
+
+// Helpers for tasks started with spawn and the data they share, also callable as methods: task.wait(), m.lock()
+var SyncBuiltins = map[string]*object.Builtin{
+	"wait": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"task"}, Return: "any"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			task, ok := args[0].(*object.Task)
+			if !ok {
+				return &object.Error{Message: "argument must be TASK"}
+			}
+
+			// Errors the task ran into are returned to whoever waits on it
+			return task.Wait()
+		},
+	},
+
+	"waitAll": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"array"}, Return: "array"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			arr, ok := args[0].(*object.Array)
+			if !ok {
+				return &object.Error{Message: "argument must be ARRAY"}
+			}
+
+			tasks := arr.Items()
+			results := make([]object.Object, len(tasks))
+			for i, el := range tasks {
+				task, ok := el.(*object.Task)
+				if !ok {
+					return &object.Error{Message: "array must only contain TASK values"}
+				}
+				results[i] = task.Wait()
+				if results[i].Type() == object.ERROR_OBJ {
+					return results[i]
+				}
+			}
+			return &object.Array{Elements: results}
+		},
+	},
+
+	"mutex": &object.Builtin{
+		Signature: &object.Signature{Params: []string{}, Return: "mutex"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 0 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			return object.NewMutex()
+		},
+	},
+
+	"lock": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"mutex"}, Return: "null"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			m, ok := args[0].(*object.Mutex)
+			if !ok {
+				return &object.Error{Message: "argument must be MUTEX"}
+			}
+
+			m.Lock()
+			return object.NULL
+		},
+	},
+
+	"unlock": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"mutex"}, Return: "null"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			m, ok := args[0].(*object.Mutex)
+			if !ok {
+				return &object.Error{Message: "argument must be MUTEX"}
+			}
+
+			if !m.Unlock() {
+				return &object.Error{Message: "unlock of unlocked mutex"}
+			}
+			return object.NULL
+		},
+	},
+}
@@ -1,15 +1,480 @@
 package modules
 
 import (
+	"fmt"
 	"time"
+	_ "time/tzdata" // so time zones can be found on systems without a zone database
 
 	"github.com/ajtroup1/clear/object"
 )
 
+// Timers made with after and every keep the program running until they have stopped,
+// their callbacks are called once the program's last statement has run, or while it sleeps
+// DateTimes, durations and timers are also callable as methods: t.year(), d.totalHours(), t.cancel()
 var TimeBuiltins = map[string]*object.Builtin{
 	"now": &object.Builtin{
+		Signature: &object.Signature{Params: []string{}, Return: "datetime"},
 		Fn: func(args ...object.Object) object.Object {
-			return &object.String{Value: time.Now().String()}
+			if len(args) != 0 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			return &object.DateTime{Value: time.Now()}
+		},
+	},
+
+	// date(year, month, day, hour?, minute?, second?) in UTC, use in() to show it in another zone
+	// Parts out of range are an error rather than rolling over, so date(2026, 2, 30) doesn't become March 2nd
+	"date": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int", "int", "int", "int"}, Return: "datetime", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) < 3 || len(args) > 6 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			parts := [6]int{}
+			for i, arg := range args {
+				n, ok := arg.(*object.Integer)
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("argument %d must be INTEGER", i+1)}
+				}
+				parts[i] = int(n.Value)
+			}
+
+			if err := checkDate(parts); err != nil {
+				return err
+			}
+
+			return &object.DateTime{Value: time.Date(parts[0], time.Month(parts[1]), parts[2], parts[3], parts[4], parts[5], 0, time.UTC)}
+		},
+	},
+
+	"fromUnix": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int"}, Return: "datetime"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			seconds, ok := args[0].(*object.Integer)
+			if !ok {
+				return &object.Error{Message: "argument must be INTEGER"}
+			}
+
+			return &object.DateTime{Value: time.Unix(seconds.Value, 0).UTC()}
+		},
+	},
+
+	// parse(layout, value, zone?) reads a DateTime written in the layout, see layouts for the names it can be
+	// Values without an offset of their own are read in the zone, which defaults to UTC
+	"parse": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string", "string", "string"}, Return: "datetime", Variadic: true},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 && len(args) != 3 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			layout, ok := args[0].(*object.String)
+			if !ok {
+				return &object.Error{Message: "first argument must be STRING"}
+			}
+			value, ok := args[1].(*object.String)
+			if !ok {
+				return &object.Error{Message: "second argument must be STRING"}
+			}
+
+			zone := time.UTC
+			if len(args) == 3 {
+				name, ok := args[2].(*object.String)
+				if !ok {
+					return &object.Error{Message: "third argument must be STRING"}
+				}
+				loc, err := time.LoadLocation(name.Value)
+				if err != nil {
+					return &object.Error{Message: "unknown time zone: " + name.Value}
+				}
+				zone = loc
+			}
+
+			t, err := time.ParseInLocation(layoutOf(layout.Value), value.Value, zone)
+			if err != nil {
+				return &object.Error{Message: fmt.Sprintf("can't parse %q as %s", value.Value, layout.Value)}
+			}
+			return &object.DateTime{Value: t}
+		},
+	},
+
+	"format": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"datetime", "string"}, Return: "string"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			t, ok := args[0].(*object.DateTime)
+			if !ok {
+				return &object.Error{Message: "first argument must be DATETIME"}
+			}
+			layout, ok := args[1].(*object.String)
+			if !ok {
+				return &object.Error{Message: "second argument must be STRING"}
+			}
+
+			return &object.String{Value: t.Value.Format(layoutOf(layout.Value))}
+		},
+	},
+
+	// The same instant shown in another zone, like "Europe/Paris", "UTC" or "Local"
+	"in": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"datetime", "string"}, Return: "datetime"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 2 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			t, ok := args[0].(*object.DateTime)
+			if !ok {
+				return &object.Error{Message: "first argument must be DATETIME"}
+			}
+			name, ok := args[1].(*object.String)
+			if !ok {
+				return &object.Error{Message: "second argument must be STRING"}
+			}
+
+			loc, err := time.LoadLocation(name.Value)
+			if err != nil {
+				return &object.Error{Message: "unknown time zone: " + name.Value}
+			}
+			return &object.DateTime{Value: t.Value.In(loc)}
+		},
+	},
+
+	"zone": dateTimePart("string", func(t time.Time) object.Object {
+		return &object.String{Value: t.Location().String()}
+	}),
+	"year": dateTimePart("int", func(t time.Time) object.Object {
+		return &object.Integer{Value: int64(t.Year())}
+	}),
+	"month": dateTimePart("int", func(t time.Time) object.Object {
+		return &object.Integer{Value: int64(t.Month())}
+	}),
+	"day": dateTimePart("int", func(t time.Time) object.Object {
+		return &object.Integer{Value: int64(t.Day())}
+	}),
+	"hour": dateTimePart("int", func(t time.Time) object.Object {
+		return &object.Integer{Value: int64(t.Hour())}
+	}),
+	"minute": dateTimePart("int", func(t time.Time) object.Object {
+		return &object.Integer{Value: int64(t.Minute())}
+	}),
+	"second": dateTimePart("int", func(t time.Time) object.Object {
+		return &object.Integer{Value: int64(t.Second())}
+	}),
+	"weekday": dateTimePart("string", func(t time.Time) object.Object {
+		return &object.String{Value: t.Weekday().String()}
+	}),
+	"unix": dateTimePart("int", func(t time.Time) object.Object {
+		return &object.Integer{Value: t.Unix()}
+	}),
+	"since": dateTimePart("duration", func(t time.Time) object.Object {
+		return &object.Duration{Value: time.Since(t)}
+	}),
+
+	// Moves by calendar years, months and days, which durations can't do since months differ in length
+	"addDate": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"datetime", "int", "int", "int"}, Return: "datetime"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 4 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			t, ok := args[0].(*object.DateTime)
+			if !ok {
+				return &object.Error{Message: "first argument must be DATETIME"}
+			}
+			parts := [3]int{}
+			for i, arg := range args[1:] {
+				n, ok := arg.(*object.Integer)
+				if !ok {
+					return &object.Error{Message: fmt.Sprintf("argument %d must be INTEGER", i+2)}
+				}
+				parts[i] = int(n.Value)
+			}
+
+			return &object.DateTime{Value: t.Value.AddDate(parts[0], parts[1], parts[2])}
+		},
+	},
+
+	// Rounds a DateTime or duration down to a multiple of the duration
+	// DateTimes are rounded as instants, so truncating to a day gives midnight in UTC rather than in their zone
+	"truncate": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"T", "duration"}, Return: "T"},
+		Fn: func(args ...object.Object) object.Object {
+			return roundTime(args, false)
 		},
 	},
+
+	// Rounds a DateTime or duration to the nearest multiple of the duration, halfway values round up
+	"round": &object.Builtin{
+		Signature: &object.Signature{TypeParams: []string{"T"}, Params: []string{"T", "duration"}, Return: "T"},
+		Fn: func(args ...object.Object) object.Object {
+			return roundTime(args, true)
+		},
+	},
+
+	// Reads a duration like "1h30m" or "250ms"
+	"duration": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"string"}, Return: "duration"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			s, ok := args[0].(*object.String)
+			if !ok {
+				return &object.Error{Message: "argument must be STRING"}
+			}
+
+			d, err := time.ParseDuration(s.Value)
+			if err != nil {
+				return &object.Error{Message: fmt.Sprintf("can't parse %q as a duration", s.Value)}
+			}
+			return &object.Duration{Value: d}
+		},
+	},
+
+	"milliseconds": durationOf(time.Millisecond),
+	"seconds":      durationOf(time.Second),
+	"minutes":      durationOf(time.Minute),
+	"hours":        durationOf(time.Hour),
+	"days":         durationOf(24 * time.Hour),
+
+	"totalMilliseconds": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"duration"}, Return: "int"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			d, ok := args[0].(*object.Duration)
+			if !ok {
+				return &object.Error{Message: "argument must be DURATION"}
+			}
+
+			return &object.Integer{Value: d.Value.Milliseconds()}
+		},
+	},
+	"totalSeconds": durationIn(time.Second),
+	"totalMinutes": durationIn(time.Minute),
+	"totalHours":   durationIn(time.Hour),
+
+	"sleep": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int"}, Return: "null"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			ms, ok := args[0].(*object.Integer)
+			if !ok {
+				return &object.Error{Message: "argument must be INTEGER"}
+			}
+			if ms.Value < 0 {
+				return &object.Error{Message: "can't sleep for a negative duration"}
+			}
+
+			// Timers that come due in the meantime have their callbacks called while waiting
+			if err := object.RunEvents(time.After(time.Duration(ms.Value) * time.Millisecond)); err != nil {
+				return err
+			}
+			return object.NULL
+		},
+	},
+
+	"after": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int", "fn"}, Return: "timer"},
+		Fn: func(args ...object.Object) object.Object {
+			return startTimer(args, false)
+		},
+	},
+
+	"every": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"int", "fn"}, Return: "timer"},
+		Fn: func(args ...object.Object) object.Object {
+			return startTimer(args, true)
+		},
+	},
+
+	"cancel": &object.Builtin{
+		Signature: &object.Signature{Params: []string{"timer"}, Return: "bool"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			t, ok := args[0].(*object.Timer)
+			if !ok {
+				return &object.Error{Message: "argument must be TIMER"}
+			}
+
+			// False when the timer already fired or was cancelled before
+			return object.NativeBool(t.Cancel())
+		},
+	},
+}
+
+// Checks the arguments of after and every, starting the timer when they're valid
+func startTimer(args []object.Object, repeat bool) object.Object {
+	if len(args) != 2 {
+		return &object.Error{Message: "wrong number of arguments"}
+	}
+
+	ms, ok := args[0].(*object.Integer)
+	if !ok {
+		return &object.Error{Message: "first argument must be INTEGER"}
+	}
+	if ms.Value <= 0 {
+		return &object.Error{Message: "interval must be positive"}
+	}
+
+	switch args[1].(type) {
+	case *object.Function, *object.Builtin:
+	default:
+		return &object.Error{Message: "second argument must be FUNCTION"}
+	}
+
+	return object.NewTimer(time.Duration(ms.Value)*time.Millisecond, repeat, args[1])
+}
+
+// Layouts are written like Go's, as the way a reference time of Mon Jan 2 15:04:05 MST 2006 would look
+// These names can be used instead of writing a common layout out
+var layouts = map[string]string{
+	"RFC3339":  time.RFC3339,
+	"RFC1123":  time.RFC1123,
+	"RFC822":   time.RFC822,
+	"ANSIC":    time.ANSIC,
+	"Kitchen":  time.Kitchen,
+	"DateTime": time.DateTime,
+	"DateOnly": time.DateOnly,
+	"TimeOnly": time.TimeOnly,
+}
+
+// Returns an error for the first of date's year, month, day, hour, minute and second that's out of range
+func checkDate(parts [6]int) object.Object {
+	year, month := parts[0], parts[1]
+	if month < 1 || month > 12 {
+		return &object.Error{Message: fmt.Sprintf("month %d is out of range, want 1 to 12", month)}
+	}
+
+	// Day 0 of the next month is the last day of this one
+	days := time.Date(year, time.Month(month)+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if parts[2] < 1 || parts[2] > days {
+		return &object.Error{Message: fmt.Sprintf("day %d is out of range for %04d-%02d, want 1 to %d", parts[2], year, month, days)}
+	}
+
+	limits := []struct {
+		name string
+		max  int
+	}{{"hour", 23}, {"minute", 59}, {"second", 59}}
+	for i, limit := range limits {
+		if value := parts[3+i]; value < 0 || value > limit.max {
+			return &object.Error{Message: fmt.Sprintf("%s %d is out of range, want 0 to %d", limit.name, value, limit.max)}
+		}
+	}
+	return nil
+}
+
+func layoutOf(layout string) string {
+	if named, ok := layouts[layout]; ok {
+		return named
+	}
+	return layout
+}
+
+// Builds a function that reads one thing off a DateTime, like its year
+func dateTimePart(result string, part func(t time.Time) object.Object) *object.Builtin {
+	return &object.Builtin{
+		Signature: &object.Signature{Params: []string{"datetime"}, Return: result},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			t, ok := args[0].(*object.DateTime)
+			if !ok {
+				return &object.Error{Message: "argument must be DATETIME"}
+			}
+
+			return part(t.Value)
+		},
+	}
+}
+
+// Builds a function that makes a duration of some number of units, fractions included: time.hours(1.5)
+func durationOf(unit time.Duration) *object.Builtin {
+	return &object.Builtin{
+		Signature: &object.Signature{Params: []string{"number"}, Return: "duration"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			switch n := args[0].(type) {
+			case *object.Integer:
+				return &object.Duration{Value: time.Duration(n.Value) * unit}
+			case *object.Float:
+				return &object.Duration{Value: time.Duration(n.Value * float64(unit))}
+			}
+			return &object.Error{Message: "argument must be INTEGER or FLOAT"}
+		},
+	}
+}
+
+// Builds a function that tells how many units long a duration is, as a float
+func durationIn(unit time.Duration) *object.Builtin {
+	return &object.Builtin{
+		Signature: &object.Signature{Params: []string{"duration"}, Return: "float"},
+		Fn: func(args ...object.Object) object.Object {
+			if len(args) != 1 {
+				return &object.Error{Message: "wrong number of arguments"}
+			}
+
+			d, ok := args[0].(*object.Duration)
+			if !ok {
+				return &object.Error{Message: "argument must be DURATION"}
+			}
+
+			whole, rest := d.Value/unit, d.Value%unit
+			return &object.Float{Value: float64(whole) + float64(rest)/float64(unit)}
+		},
+	}
+}
+
+// Truncates or rounds a DateTime or duration to a multiple of a duration
+func roundTime(args []object.Object, nearest bool) object.Object {
+	if len(args) != 2 {
+		return &object.Error{Message: "wrong number of arguments"}
+	}
+
+	d, ok := args[1].(*object.Duration)
+	if !ok {
+		return &object.Error{Message: "second argument must be DURATION"}
+	}
+	if d.Value <= 0 {
+		return &object.Error{Message: "duration must be positive"}
+	}
+
+	switch value := args[0].(type) {
+	case *object.DateTime:
+		if nearest {
+			return &object.DateTime{Value: value.Value.Round(d.Value)}
+		}
+		return &object.DateTime{Value: value.Value.Truncate(d.Value)}
+	case *object.Duration:
+		if nearest {
+			return &object.Duration{Value: value.Value.Round(d.Value)}
+		}
+		return &object.Duration{Value: value.Value.Truncate(d.Value)}
+	}
+	return &object.Error{Message: "first argument must be DATETIME or DURATION"}
 }
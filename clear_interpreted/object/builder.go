@@ -0,0 +1,54 @@
+package object
+
+import (
+	"strings"
+	"sync"
+)
+
+const BUILDER_OBJ = "BUILDER"
+
+// The functions of the strings module builders have as methods, the rest only make sense for strings
+var BuilderMethods = []string{"write", "writeLine", "build", "len", "reset"}
+
+// Builds up a string piece by piece, without copying what's already written each time like + does
+// It's safe to write to from several tasks at once, each write is added as a whole
+type StringBuilder struct {
+	Position
+
+	mu      sync.Mutex
+	builder strings.Builder
+}
+
+func (b *StringBuilder) Type() ObjectType { return BUILDER_OBJ }
+func (b *StringBuilder) Inspect() string  { return "<builder " + b.String() + ">" }
+func (b *StringBuilder) Line() int        { return b.Position.Line }
+func (b *StringBuilder) Col() int         { return b.Position.Col }
+
+// Adds the pieces to the end, without another write coming between them
+func (b *StringBuilder) Write(pieces ...string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, piece := range pieces {
+		b.builder.WriteString(piece)
+	}
+}
+
+// The string built so far
+func (b *StringBuilder) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.builder.String()
+}
+
+// The length in bytes of the string built so far
+func (b *StringBuilder) Len() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.builder.Len()
+}
+
+func (b *StringBuilder) Reset() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.builder.Reset()
+}
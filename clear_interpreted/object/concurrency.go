@@ -0,0 +1,168 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	CHANNEL_OBJ = "CHANNEL"
+	TASK_OBJ    = "TASK"
+	MUTEX_OBJ   = "MUTEX"
+)
+
+// Passes values between tasks. Unbuffered channels hand each value straight to a receiver,
+// buffered ones hold up to their capacity before senders have to wait
+// Closing never closes the Go channel underneath, so sending to a closed channel is an error instead of a panic
+type Channel struct {
+	Position
+	values chan Object
+	closed chan struct{}
+	once   sync.Once
+}
+
+func NewChannel(capacity int) *Channel {
+	return &Channel{values: make(chan Object, capacity), closed: make(chan struct{})}
+}
+
+func (c *Channel) Type() ObjectType { return CHANNEL_OBJ }
+func (c *Channel) Inspect() string {
+	return fmt.Sprintf("<channel %d/%d>", len(c.values), cap(c.values))
+}
+func (c *Channel) Line() int { return c.Position.Line }
+func (c *Channel) Col() int  { return c.Position.Col }
+
+// Waits until the value is received or buffered, reporting false when the channel is closed
+func (c *Channel) Send(value Object) bool {
+	if c.IsClosed() {
+		return false
+	}
+	select {
+	case c.values <- value:
+		return true
+	case <-c.closed:
+		return false
+	}
+}
+
+// Waits for the next value, reporting false once the channel is closed and every value sent before that is received
+func (c *Channel) Recv() (Object, bool) {
+	select {
+	case value := <-c.values:
+		return value, true
+	case <-c.closed:
+		return c.Drain()
+	}
+}
+
+// Takes a value the channel already holds without waiting, used once it is closed
+func (c *Channel) Drain() (Object, bool) {
+	select {
+	case value := <-c.values:
+		return value, true
+	default:
+		return nil, false
+	}
+}
+
+// Reports false when the channel was already closed
+func (c *Channel) Close() bool {
+	closed := false
+	c.once.Do(func() {
+		close(c.closed)
+		closed = true
+	})
+	return closed
+}
+
+func (c *Channel) IsClosed() bool {
+	select {
+	case <-c.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// The Go channels underneath, for selecting over several channels at once
+// Values are sent and received on the first, the second is closed along with the channel
+func (c *Channel) Chans() (chan Object, chan struct{}) {
+	return c.values, c.closed
+}
+
+// A call started with spawn, running on its own goroutine
+type Task struct {
+	Position
+	done   chan struct{}
+	result Object
+}
+
+func NewTask() *Task {
+	return &Task{done: make(chan struct{})}
+}
+
+func (t *Task) Type() ObjectType { return TASK_OBJ }
+func (t *Task) Inspect() string {
+	if t.Done() {
+		return "<task done>"
+	}
+	return "<task running>"
+}
+func (t *Task) Line() int { return t.Position.Line }
+func (t *Task) Col() int  { return t.Position.Col }
+
+// Records what the call returned, letting everyone waiting on the task go on
+func (t *Task) Finish(result Object) {
+	t.result = result
+	close(t.done)
+}
+
+// Waits for the call to return and gives back its result, which may be an Error
+func (t *Task) Wait() Object {
+	<-t.done
+	return t.result
+}
+
+func (t *Task) Done() bool {
+	select {
+	case <-t.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// Guards data shared between tasks. Unlocking a mutex that isn't locked is an error rather than a crash,
+// which is why it's built on a channel instead of a sync.Mutex
+type Mutex struct {
+	Position
+	held chan struct{}
+}
+
+func NewMutex() *Mutex {
+	return &Mutex{held: make(chan struct{}, 1)}
+}
+
+func (m *Mutex) Type() ObjectType { return MUTEX_OBJ }
+func (m *Mutex) Inspect() string {
+	if len(m.held) > 0 {
+		return "<mutex locked>"
+	}
+	return "<mutex>"
+}
+func (m *Mutex) Line() int { return m.Position.Line }
+func (m *Mutex) Col() int  { return m.Position.Col }
+
+func (m *Mutex) Lock() {
+	m.held <- struct{}{}
+}
+
+// Reports false when the mutex wasn't locked
+func (m *Mutex) Unlock() bool {
+	select {
+	case <-m.held:
+		return true
+	default:
+		return false
+	}
+}
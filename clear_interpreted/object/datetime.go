@@ -0,0 +1,46 @@
+package object
+
+import (
+	"hash/fnv"
+	"time"
+)
+
+const (
+	DATETIME_OBJ = "DATETIME"
+	DURATION_OBJ = "DURATION"
+)
+
+// An instant in time along with the time zone it's shown in
+// Two DateTimes are equal when they're the same instant, even if their zones differ
+type DateTime struct {
+	Position
+	Value time.Time
+}
+
+func (dt *DateTime) Type() ObjectType { return DATETIME_OBJ }
+func (dt *DateTime) Inspect() string  { return dt.Value.Format(time.RFC3339Nano) }
+func (dt *DateTime) Line() int        { return dt.Position.Line }
+func (dt *DateTime) Col() int         { return dt.Position.Col }
+
+// Hashed by the instant alone, so equal DateTimes in different zones are the same key
+func (dt *DateTime) HashKey() HashKey {
+	h := fnv.New64a()
+	utc := dt.Value.UTC()
+	h.Write([]byte(utc.Format(time.RFC3339Nano)))
+	return HashKey{Type: dt.Type(), Value: h.Sum64()}
+}
+
+// The time between two instants, down to the nanosecond
+type Duration struct {
+	Position
+	Value time.Duration
+}
+
+func (d *Duration) Type() ObjectType { return DURATION_OBJ }
+func (d *Duration) Inspect() string  { return d.Value.String() }
+func (d *Duration) Line() int        { return d.Position.Line }
+func (d *Duration) Col() int         { return d.Position.Col }
+
+func (d *Duration) HashKey() HashKey {
+	return HashKey{Type: d.Type(), Value: uint64(d.Value)}
+}
@@ -1,25 +1,68 @@
 package object
 
+import "sync"
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
 	return env
 }
 
+// Match arms and select cases get a scope of their own for the names their pattern binds,
+// but assigning any other name inside them assigns it in the scope around them, as it would in an if block
+func NewArmEnvironment(outer *Environment) *Environment {
+	env := NewEnclosedEnvironment(outer)
+	env.arm = true
+	return env
+}
+
 func NewEnvironment() *Environment {
 	s := make(map[string]Object)
+	c := make(map[string]bool)
 	m := make(map[string]map[string]*Builtin)
-	return &Environment{store: s, outer: nil, Modules: m}
+	mc := make(map[string]map[string]Object)
+	return &Environment{store: s, constants: c, outer: nil, Modules: m, ModuleConstants: mc}
 }
 
+// Spawned tasks share the environments their functions close over, so every scope guards its own maps
 type Environment struct {
-	store   map[string]Object
-	outer   *Environment
-	Modules map[string]map[string]*Builtin
+	mu        sync.RWMutex
+	store     map[string]Object
+	constants map[string]bool // names declared with `const` in this scope
+	outer     *Environment
+	Modules   map[string]map[string]*Builtin
+
+	ModuleConstants map[string]map[string]Object // values modules have besides functions, like math.PI
+
+	yield func(Object) Object // set in the scope a generator runs in
+	arm   bool                // made by NewArmEnvironment, never changed afterwards
+}
+
+// Makes this scope the body of a generator, which yield expressions inside it hand their values to
+func (e *Environment) SetYield(yield func(Object) Object) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.yield = yield
+}
+
+// Hands a value to the generator whose body the scope is in
+// Yield only parses directly inside generators, so the nearest scope that can yield is the right one
+func (e *Environment) Yield(val Object) (Object, bool) {
+	for scope := e; scope != nil; scope = scope.outer {
+		scope.mu.RLock()
+		yield := scope.yield
+		scope.mu.RUnlock()
+		if yield != nil {
+			return yield(val), true
+		}
+	}
+	return nil, false
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
+	e.mu.RLock()
 	obj, ok := e.store[name]
+	e.mu.RUnlock()
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.Get(name)
 	}
@@ -27,12 +70,63 @@ func (e *Environment) Get(name string) (Object, bool) {
 }
 
 func (e *Environment) Set(name string, val Object) Object {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.store[name] = val
+	return val
+}
+
+// Binds a name that can't be reassigned afterwards
+func (e *Environment) SetConst(name string, val Object) Object {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.store[name] = val
+	e.constants[name] = true
 	return val
 }
 
+// Reports whether the name refers to a constant, looking in the scope that defines it
+func (e *Environment) IsConst(name string) bool {
+	for scope := e; scope != nil; scope = scope.outer {
+		scope.mu.RLock()
+		_, ok := scope.store[name]
+		constant := scope.constants[name]
+		scope.mu.RUnlock()
+		if ok {
+			return constant
+		}
+	}
+	return false
+}
+
+// Reports whether the name is declared as a constant in this exact scope,
+// since shadowing a constant from an outer scope is allowed
+func (e *Environment) IsLocalConst(name string) bool {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.constants[name]
+}
+
+// Assigns a variable like Set, except that inside a match arm or select case it's assigned in the scope around it,
+// unless the arm's pattern bound the name itself. Functions still get a variable of their own
+func (e *Environment) Assign(name string, val Object) Object {
+	scope := e
+	for scope.arm && scope.outer != nil {
+		scope.mu.RLock()
+		_, bound := scope.store[name]
+		scope.mu.RUnlock()
+		if bound {
+			break
+		}
+		scope = scope.outer
+	}
+	return scope.Set(name, val)
+}
+
 func (e *Environment) GetModule(name string) (map[string]*Builtin, bool) {
+	e.mu.RLock()
 	obj, ok := e.Modules[name]
+	e.mu.RUnlock()
 	if !ok && e.outer != nil {
 		obj, ok = e.outer.GetModule(name)
 	}
@@ -40,5 +134,23 @@ func (e *Environment) GetModule(name string) (map[string]*Builtin, bool) {
 }
 
 func (e *Environment) SetModule(name string, val map[string]*Builtin) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
 	e.Modules[name] = val
 }
+
+func (e *Environment) GetModuleConstants(module string) (map[string]Object, bool) {
+	e.mu.RLock()
+	obj, ok := e.ModuleConstants[module]
+	e.mu.RUnlock()
+	if !ok && e.outer != nil {
+		obj, ok = e.outer.GetModuleConstants(module)
+	}
+	return obj, ok
+}
+
+func (e *Environment) SetModuleConstants(module string, vals map[string]Object) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.ModuleConstants[module] = vals
+}
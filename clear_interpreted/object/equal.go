@@ -0,0 +1,142 @@
+package object
+
+// Equals reports whether two objects are structurally equal
+//   - Numbers compare by value, so 1 == 1.0
+//   - Strings, booleans and null compare by value
+//   - DateTimes are equal when they're the same instant, durations when they're the same length
+//   - Arrays compare element by element and hashes compare pair by pair
+//   - Records are equal when they have the same type and equal fields
+//   - Enum values are equal when they are the same variant with equal fields
+//   - Anything else (functions, builtins, ...) is only equal to itself
+//
+// Self-referencing arrays and hashes are handled by assuming any pair of
+// objects that is already being compared further up the stack is equal
+func Equals(a, b Object) bool {
+	return equals(a, b, map[comparison]bool{})
+}
+
+// Same reports whether two objects are identical, which is what the `===`
+// operator evaluates
+// Primitive values are identical when they have the same type and value,
+// while arrays, hashes and functions must be the exact same reference
+func Same(a, b Object) bool {
+	switch a := a.(type) {
+	case *Integer:
+		b, ok := b.(*Integer)
+		return ok && a.Value == b.Value
+	case *Float:
+		b, ok := b.(*Float)
+		return ok && a.Value == b.Value
+	case *String:
+		b, ok := b.(*String)
+		return ok && a.Value == b.Value
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value
+	case *Null:
+		_, ok := b.(*Null)
+		return ok
+	case *DateTime:
+		b, ok := b.(*DateTime)
+		return ok && a.Value.Equal(b.Value)
+	case *Duration:
+		b, ok := b.(*Duration)
+		return ok && a.Value == b.Value
+	default:
+		return a == b
+	}
+}
+
+// A pair of objects currently being compared, used for cycle protection
+type comparison struct {
+	a, b Object
+}
+
+func equals(a, b Object, seen map[comparison]bool) bool {
+	if a == b {
+		return true
+	}
+
+	switch a := a.(type) {
+	case *Integer:
+		switch b := b.(type) {
+		case *Integer:
+			return a.Value == b.Value
+		case *Float:
+			return float64(a.Value) == b.Value
+		}
+		return false
+	case *Float:
+		switch b := b.(type) {
+		case *Float:
+			return a.Value == b.Value
+		case *Integer:
+			return a.Value == float64(b.Value)
+		}
+		return false
+	case *String, *Boolean, *Null, *DateTime, *Duration:
+		return Same(a, b)
+	}
+
+	pair := comparison{a: a, b: b}
+	if seen[pair] {
+		return true
+	}
+	seen[pair] = true
+	defer delete(seen, pair)
+
+	switch a := a.(type) {
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok {
+			return false
+		}
+		as, bs := a.Items(), b.Items()
+		if len(as) != len(bs) {
+			return false
+		}
+		for i := range as {
+			if !equals(as[i], bs[i], seen) {
+				return false
+			}
+		}
+		return true
+	case *Hash:
+		b, ok := b.(*Hash)
+		if !ok || a.Len() != b.Len() {
+			return false
+		}
+		for _, key := range a.Keys() {
+			pair, inA := a.Get(key)
+			other, inB := b.Get(key)
+			if !inA || !inB || !equals(pair.Value, other.Value, seen) {
+				return false
+			}
+		}
+		return true
+	case *Record:
+		b, ok := b.(*Record)
+		if !ok || a.RecordType != b.RecordType {
+			return false
+		}
+		for i := range a.Values {
+			if !equals(a.Values[i], b.Values[i], seen) {
+				return false
+			}
+		}
+		return true
+	case *EnumValue:
+		b, ok := b.(*EnumValue)
+		if !ok || a.Variant != b.Variant {
+			return false
+		}
+		for i := range a.Values {
+			if !equals(a.Values[i], b.Values[i], seen) {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
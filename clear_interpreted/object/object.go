@@ -4,7 +4,9 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
 	"strings"
+	"sync"
 
 	"github.com/ajtroup1/clear/ast"
 )
@@ -21,6 +23,9 @@ const (
 	STRING_OBJ  = "STRING"
 	ARRAY_OBJ   = "ARRAY"
 	HASH_OBJ    = "HASH"
+	RANGE_OBJ   = "RANGE"
+
+	ITERATOR_OBJ = "ITERATOR"
 
 	BREAK_OBJ    = "BREAK"
 	CONTINUE_OBJ = "CONTINUE"
@@ -29,6 +34,17 @@ const (
 
 	FUNCTION_OBJ = "FUNCTION"
 	BUILTIN_OBJ  = "BUILTIN"
+	MODULE_OBJ   = "MODULE"
+
+	RECORD_TYPE_OBJ = "RECORD_TYPE"
+	RECORD_OBJ      = "RECORD"
+
+	ENUM_OBJ         = "ENUM"
+	ENUM_VARIANT_OBJ = "ENUM_VARIANT"
+	ENUM_VALUE_OBJ   = "ENUM_VALUE"
+
+	TYPE_PARAMETER_OBJ = "TYPE_PARAMETER"
+	INTERFACE_OBJ      = "INTERFACE"
 )
 
 type Object interface {
@@ -90,6 +106,31 @@ func (n *Null) Inspect() string  { return "null" }
 func (n *Null) Line() int        { return 0 }
 func (n *Null) Col() int         { return 0 }
 
+// Shared true, false and null values, so they aren't allocated again for every result
+var (
+	NULL  = &Null{}
+	TRUE  = &Boolean{Value: true}
+	FALSE = &Boolean{Value: false}
+)
+
+func NativeBool(value bool) *Boolean {
+	if value {
+		return TRUE
+	}
+	return FALSE
+}
+
+// Null and false are the only falsy values, whether or not they're the shared ones
+func IsTruthy(obj Object) bool {
+	switch obj := obj.(type) {
+	case *Null:
+		return false
+	case *Boolean:
+		return obj.Value
+	}
+	return true
+}
+
 type ReturnValue struct {
 	Position
 	Value Object
@@ -111,11 +152,17 @@ func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
 func (e *Error) Line() int        { return e.Position.Line }
 func (e *Error) Col() int         { return e.Position.Col }
 
+// ParameterTypes and ReturnType hold the optional annotations of the literal,
+// which are checked whenever the function is called
 type Function struct {
 	Position
-	Parameters []*ast.Identifier
-	Body       *ast.BlockStatement
-	Env        *Environment
+	TypeParameters []*ast.Identifier
+	Parameters     []*ast.Identifier
+	ParameterTypes []*ast.TypeExpr
+	ReturnType     *ast.TypeExpr
+	Body           *ast.BlockStatement
+	Env            *Environment
+	Generator      bool // calling it returns an iterator over what its body yields
 }
 
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
@@ -123,14 +170,32 @@ func (f *Function) Inspect() string {
 	var out bytes.Buffer
 
 	params := []string{}
-	for _, p := range f.Parameters {
+	for i, p := range f.Parameters {
+		if i < len(f.ParameterTypes) && f.ParameterTypes[i] != nil {
+			params = append(params, p.String()+": "+f.ParameterTypes[i].String())
+			continue
+		}
 		params = append(params, p.String())
 	}
 
 	out.WriteString("fn")
+	if f.Generator {
+		out.WriteString("*")
+	}
+	if len(f.TypeParameters) > 0 {
+		typeParams := []string{}
+		for _, tp := range f.TypeParameters {
+			typeParams = append(typeParams, tp.String())
+		}
+		out.WriteString("<" + strings.Join(typeParams, ", ") + ">")
+	}
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
-	out.WriteString(") {\n")
+	out.WriteString(") ")
+	if f.ReturnType != nil {
+		out.WriteString("-> " + f.ReturnType.String() + " ")
+	}
+	out.WriteString("{\n")
 	out.WriteString(f.Body.String())
 	out.WriteString("\n}")
 
@@ -141,9 +206,35 @@ func (f *Function) Col() int  { return f.Position.Col }
 
 type BuiltinFunction func(args ...Object) Object
 
+// The Signature is optional, builtins without one aren't type checked
 type Builtin struct {
 	Position
-	Fn BuiltinFunction
+	Fn        BuiltinFunction
+	Signature *Signature
+}
+
+// Describes the parameter and return types of a builtin for the type checker,
+// using the same type names as annotations ("int", "string", "array<int>", "any", ...)
+// Generic builtins name their type parameters in TypeParams, fn<T>(array<T>) -> T
+// Variadic builtins accept any number of arguments of their last parameter's type
+type Signature struct {
+	TypeParams []string
+	Params     []string
+	Return     string
+	Variadic   bool
+}
+
+func (s *Signature) String() string {
+	params := make([]string, len(s.Params))
+	copy(params, s.Params)
+	if s.Variadic && len(params) > 0 {
+		params[len(params)-1] = "..." + params[len(params)-1]
+	}
+	typeParams := ""
+	if len(s.TypeParams) > 0 {
+		typeParams = "<" + strings.Join(s.TypeParams, ", ") + ">"
+	}
+	return "fn" + typeParams + "(" + strings.Join(params, ", ") + ") -> " + s.Return
 }
 
 func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
@@ -151,9 +242,232 @@ func (b *Builtin) Inspect() string  { return "builtin function" }
 func (b *Builtin) Line() int        { return b.Position.Line }
 func (b *Builtin) Col() int         { return b.Position.Col }
 
+// A registered module referenced by name, such as `math` in `math.sqrt`
+// Nested modules are registered under dotted names (ex. "math.stats")
+type Module struct {
+	Position
+	Name      string
+	Functions map[string]*Builtin
+}
+
+func (m *Module) Type() ObjectType { return MODULE_OBJ }
+func (m *Module) Inspect() string  { return "module " + m.Name }
+func (m *Module) Line() int        { return m.Position.Line }
+func (m *Module) Col() int         { return m.Position.Col }
+
+// A single field of a record type, the type name and default are optional
+type RecordField struct {
+	Name     string
+	TypeName *ast.TypeExpr
+	Default  ast.Expression
+}
+
+// Declared with `type Point { x, y }`, calling it constructs a Record
+// Defaults are evaluated in Env, the scope the type was declared in
+// Methods are attached with `impl`, and take the record as their first argument
+type RecordType struct {
+	Position
+	Name    string
+	Fields  []RecordField
+	Env     *Environment
+	Methods map[string]*Function
+}
+
+func (rt *RecordType) Type() ObjectType { return RECORD_TYPE_OBJ }
+func (rt *RecordType) Inspect() string {
+	fields := []string{}
+	for _, field := range rt.Fields {
+		decl := field.Name
+		if field.TypeName != nil {
+			decl += ": " + field.TypeName.String()
+		}
+		if field.Default != nil {
+			decl += " = " + field.Default.String()
+		}
+		fields = append(fields, decl)
+	}
+	return "type " + rt.Name + " { " + strings.Join(fields, ", ") + " }"
+}
+func (rt *RecordType) Line() int { return rt.Position.Line }
+func (rt *RecordType) Col() int  { return rt.Position.Col }
+
+// Index of the field with the given name, or -1 if there is none
+func (rt *RecordType) FieldIndex(name string) int {
+	for i, field := range rt.Fields {
+		if field.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// An instance of a record type, Values line up with the type's Fields
+type Record struct {
+	Position
+	RecordType *RecordType
+	Values     []Object
+}
+
+func (r *Record) Type() ObjectType { return RECORD_OBJ }
+func (r *Record) Inspect() string {
+	if str, ok := inspectStringer(r); ok {
+		return str
+	}
+
+	fields := []string{}
+	for i, field := range r.RecordType.Fields {
+		fields = append(fields, field.Name+": "+r.Values[i].Inspect())
+	}
+	return r.RecordType.Name + " { " + strings.Join(fields, ", ") + " }"
+}
+func (r *Record) Line() int { return r.Position.Line }
+func (r *Record) Col() int  { return r.Position.Col }
+
+func (r *Record) Get(name string) (Object, bool) {
+	i := r.RecordType.FieldIndex(name)
+	if i < 0 {
+		return nil, false
+	}
+	return r.Values[i], true
+}
+
+// Declared with `enum Color { Red, Green, Blue }`
+// Variants are kept in declaration order, which is the order they are iterated in
+type Enum struct {
+	Position
+	Name     string
+	Variants []*EnumVariant
+	Methods  map[string]*Function // attached with `impl`, like the methods of a record type
+}
+
+func (e *Enum) Type() ObjectType { return ENUM_OBJ }
+func (e *Enum) Inspect() string {
+	variants := []string{}
+	// Values are only shown when they were set explicitly, not counted up from the last one
+	next := int64(0)
+	for _, variant := range e.Variants {
+		decl := variant.Name
+		if variant.Unit == nil {
+			decl += "(" + strings.Join(variant.Fields, ", ") + ")"
+		}
+		if variant.Value != next {
+			decl += fmt.Sprintf(" = %d", variant.Value)
+		}
+		next = variant.Value + 1
+		variants = append(variants, decl)
+	}
+	return "enum " + e.Name + " { " + strings.Join(variants, ", ") + " }"
+}
+func (e *Enum) Line() int { return e.Position.Line }
+func (e *Enum) Col() int  { return e.Position.Col }
+
+func (e *Enum) Variant(name string) (*EnumVariant, bool) {
+	for _, variant := range e.Variants {
+		if variant.Name == name {
+			return variant, true
+		}
+	}
+	return nil, false
+}
+
+// The variant with the given integer value
+func (e *Enum) VariantOf(value int64) (*EnumVariant, bool) {
+	for _, variant := range e.Variants {
+		if variant.Value == value {
+			return variant, true
+		}
+	}
+	return nil, false
+}
+
+// A single variant of an enum
+// Variants with fields are constructors, calling them creates an EnumValue,
+// while variants without fields have a single shared value, Unit
+type EnumVariant struct {
+	Position
+	Enum   *Enum
+	Name   string
+	Value  int64
+	Fields []string
+	Unit   *EnumValue // nil for variants with fields
+}
+
+func (v *EnumVariant) Type() ObjectType { return ENUM_VARIANT_OBJ }
+func (v *EnumVariant) Inspect() string {
+	return v.Enum.Name + "." + v.Name + "(" + strings.Join(v.Fields, ", ") + ")"
+}
+func (v *EnumVariant) Line() int { return v.Position.Line }
+func (v *EnumVariant) Col() int  { return v.Position.Col }
+
+// What the variant evaluates to: its value for variants without fields,
+// otherwise the variant itself so it can be called
+func (v *EnumVariant) Member() Object {
+	if v.Unit != nil {
+		return v.Unit
+	}
+	return v
+}
+
+// A value of an enum, Values line up with the variant's Fields
+type EnumValue struct {
+	Position
+	Variant *EnumVariant
+	Values  []Object
+}
+
+func (ev *EnumValue) Type() ObjectType { return ENUM_VALUE_OBJ }
+func (ev *EnumValue) Inspect() string {
+	if str, ok := inspectStringer(ev); ok {
+		return str
+	}
+
+	out := ev.Variant.Enum.Name + "." + ev.Variant.Name
+	if ev.Variant.Unit == nil {
+		values := []string{}
+		for _, value := range ev.Values {
+			values = append(values, value.Inspect())
+		}
+		out += "(" + strings.Join(values, ", ") + ")"
+	}
+	return out
+}
+func (ev *EnumValue) Line() int { return ev.Position.Line }
+func (ev *EnumValue) Col() int  { return ev.Position.Col }
+
+func (ev *EnumValue) Get(name string) (Object, bool) {
+	for i, field := range ev.Variant.Fields {
+		if field == name {
+			return ev.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+// Frozen arrays and hashes can't be modified, see Freeze
+// Tasks can share an array, so once it's been made its elements are read with Items and changed with Update
 type Array struct {
 	Position
 	Elements []Object
+	Frozen   bool
+
+	mu sync.RWMutex
+}
+
+// The elements as they are now, which must not be written to
+// Update never writes over elements it has already handed out, so they can be read while another task changes the array
+func (ao *Array) Items() []Object {
+	ao.mu.RLock()
+	defer ao.mu.RUnlock()
+	return ao.Elements
+}
+
+// Replaces the elements with what fn returns, with no other task reading or changing them in between
+// fn mustn't write over the elements it's given, only append to them or build a new slice,
+// and cutting them shorter has to cut their capacity too, so appending later doesn't write over the old ones
+func (ao *Array) Update(fn func(elements []Object) []Object) {
+	ao.mu.Lock()
+	defer ao.mu.Unlock()
+	ao.Elements = fn(ao.Elements)
 }
 
 func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
@@ -161,7 +475,7 @@ func (ao *Array) Inspect() string {
 	var out bytes.Buffer
 
 	elements := []string{}
-	for _, el := range ao.Elements {
+	for _, el := range ao.Items() {
 		elements = append(elements, el.Inspect())
 	}
 
@@ -174,6 +488,97 @@ func (ao *Array) Inspect() string {
 func (ao *Array) Line() int { return ao.Position.Line }
 func (ao *Array) Col() int  { return ao.Position.Col }
 
+// A lazily evaluated sequence of integers from Start to End
+// End is only part of the sequence when the range is Inclusive
+type Range struct {
+	Position
+	Start     int64
+	End       int64
+	Step      int64
+	Inclusive bool
+}
+
+func (r *Range) Type() ObjectType { return RANGE_OBJ }
+func (r *Range) Inspect() string {
+	var out bytes.Buffer
+
+	out.WriteString(fmt.Sprintf("%d", r.Start))
+	if r.Inclusive {
+		out.WriteString("..")
+	} else {
+		out.WriteString("..<")
+	}
+	out.WriteString(fmt.Sprintf("%d", r.End))
+	if r.Step != 1 && r.Step != -1 {
+		out.WriteString(fmt.Sprintf(" step %d", r.Step))
+	}
+
+	return out.String()
+}
+func (r *Range) Line() int { return r.Position.Line }
+func (r *Range) Col() int  { return r.Position.Col }
+
+// Each calls fn with every integer in the range until fn returns false
+func (r *Range) Each(fn func(int64) bool) {
+	for i := r.Start; r.contains(i); i += r.Step {
+		if !fn(i) {
+			return
+		}
+		// Stop before the next step overflows past the end
+		if (r.Step > 0 && i > math.MaxInt64-r.Step) || (r.Step < 0 && i < math.MinInt64-r.Step) {
+			return
+		}
+	}
+}
+
+// Steps through the range as values are asked for, so long ranges are never collected
+func (r *Range) Iterator() *Iterator {
+	i, done := r.Start, false
+	return &Iterator{
+		Name:     "range",
+		Position: r.Position,
+		Next: func() Object {
+			if done || !r.contains(i) {
+				done = true
+				return nil
+			}
+			n := i
+			if (r.Step > 0 && i > math.MaxInt64-r.Step) || (r.Step < 0 && i < math.MinInt64-r.Step) {
+				done = true
+			} else {
+				i += r.Step
+			}
+			return &Integer{Value: n}
+		},
+	}
+}
+
+func (r *Range) contains(i int64) bool {
+	switch {
+	case r.Step > 0 && r.Inclusive:
+		return i <= r.End
+	case r.Step > 0:
+		return i < r.End
+	case r.Inclusive:
+		return i >= r.End
+	default:
+		return i > r.End
+	}
+}
+
+// A lazy sequence, producing its values one at a time as Next is called
+// Next returns nil once the sequence is exhausted, or an Error when producing a value failed
+type Iterator struct {
+	Position
+	Name string // what produces the values, like the generator function's name
+	Next func() Object
+}
+
+func (it *Iterator) Type() ObjectType { return ITERATOR_OBJ }
+func (it *Iterator) Inspect() string  { return "<iterator " + it.Name + ">" }
+func (it *Iterator) Line() int        { return it.Position.Line }
+func (it *Iterator) Col() int         { return it.Position.Col }
+
 type HashKey struct {
 	Type  ObjectType
 	Value uint64
@@ -197,20 +602,152 @@ func (s *String) HashKey() HashKey {
 	return HashKey{Type: s.Type(), Value: h.Sum64()}
 }
 
+// Arrays are usable as hash keys as long as all of their elements are
+// Self-referencing arrays are not, since they can't be hashed in finite time
+func (ao *Array) HashKey() (HashKey, bool) {
+	return arrayHashKey(ao, map[*Array]bool{})
+}
+
+func arrayHashKey(ao *Array, seen map[*Array]bool) (HashKey, bool) {
+	if seen[ao] {
+		return HashKey{}, false
+	}
+	seen[ao] = true
+	defer delete(seen, ao)
+
+	h := fnv.New64a()
+	for _, el := range ao.Items() {
+		var key HashKey
+		var ok bool
+		if nested, isArray := el.(*Array); isArray {
+			key, ok = arrayHashKey(nested, seen)
+		} else {
+			key, ok = HashKeyOf(el)
+		}
+		if !ok {
+			return HashKey{}, false
+		}
+		fmt.Fprintf(h, "%s:%d;", key.Type, key.Value)
+	}
+	return HashKey{Type: ao.Type(), Value: h.Sum64()}, true
+}
+
+// Enum values are usable as hash keys as long as all of their fields are
+// Variants are told apart by identity, so equally named enums don't collide
+func (ev *EnumValue) HashKey() (HashKey, bool) {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%p;", ev.Variant)
+	for _, value := range ev.Values {
+		key, ok := HashKeyOf(value)
+		if !ok {
+			return HashKey{}, false
+		}
+		fmt.Fprintf(h, "%s:%d;", key.Type, key.Value)
+	}
+	return HashKey{Type: ev.Type(), Value: h.Sum64()}, true
+}
+
+// HashKeyOf returns the hash key of any object usable as a hash key
+// The second return value is false when the object can't be used as a key
+func HashKeyOf(obj Object) (HashKey, bool) {
+	switch obj := obj.(type) {
+	case *Array:
+		return obj.HashKey()
+	case *EnumValue:
+		return obj.HashKey()
+	case Hashable:
+		return obj.HashKey(), true
+	default:
+		return HashKey{}, false
+	}
+}
+
 type HashPair struct {
 	Key   Object
 	Value Object
 }
+
+// Hashes remember the order their keys were inserted in, which is the order
+// they are inspected and iterated in
+// Pairs should only be modified through Set and Delete to keep the order intact
+// Tasks can share a hash, so its pairs are only reached through its methods, which lock it
 type Hash struct {
 	Position
-	Pairs map[HashKey]HashPair
+	Pairs  map[HashKey]HashPair
+	Order  []HashKey
+	Frozen bool
+
+	mu sync.RWMutex
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]HashPair)}
+}
+
+// Set inserts or updates a pair
+// Updating an existing key keeps its original position
+func (h *Hash) Set(key HashKey, pair HashPair) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.Pairs[key]; !exists {
+		h.Order = append(h.Order, key)
+	}
+	h.Pairs[key] = pair
+}
+
+func (h *Hash) Get(key HashKey) (HashPair, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	pair, ok := h.Pairs[key]
+	return pair, ok
+}
+
+// Delete removes a pair and reports whether it existed
+func (h *Hash) Delete(key HashKey) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if _, exists := h.Pairs[key]; !exists {
+		return false
+	}
+	delete(h.Pairs, key)
+	for i, k := range h.Order {
+		if k == key {
+			h.Order = append(h.Order[:i], h.Order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// OrderedPairs returns all pairs in insertion order
+func (h *Hash) OrderedPairs() []HashPair {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	pairs := make([]HashPair, 0, len(h.Order))
+	for _, key := range h.Order {
+		pairs = append(pairs, h.Pairs[key])
+	}
+	return pairs
+}
+
+// The keys in insertion order
+func (h *Hash) Keys() []HashKey {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return append([]HashKey(nil), h.Order...)
+}
+
+func (h *Hash) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.Pairs)
 }
 
 func (h *Hash) Type() ObjectType { return HASH_OBJ }
 func (h *Hash) Inspect() string {
 	var out bytes.Buffer
 	pairs := []string{}
-	for _, pair := range h.Pairs {
+	for _, pair := range h.OrderedPairs() {
 		pairs = append(pairs, fmt.Sprintf("%s: %s",
 			pair.Key.Inspect(), pair.Value.Inspect()))
 	}
@@ -243,3 +780,175 @@ func (b *Break) Type() ObjectType { return BREAK_OBJ }
 func (b *Break) Inspect() string  { return "break" }
 func (b *Break) Line() int        { return b.Position.Line }
 func (b *Break) Col() int         { return b.Position.Col }
+
+// Makes arrays and hashes immutable, along with every array or hash they contain
+// Values that are already frozen are skipped, which also stops self-references from recursing forever
+func Freeze(obj Object) Object {
+	switch obj := obj.(type) {
+	case *Array:
+		obj.mu.Lock()
+		frozen := obj.Frozen
+		obj.Frozen = true
+		obj.mu.Unlock()
+		if frozen {
+			return obj
+		}
+		for _, el := range obj.Items() {
+			Freeze(el)
+		}
+	case *Hash:
+		obj.mu.Lock()
+		frozen := obj.Frozen
+		obj.Frozen = true
+		obj.mu.Unlock()
+		if frozen {
+			return obj
+		}
+		for _, pair := range obj.OrderedPairs() {
+			Freeze(pair.Key)
+			Freeze(pair.Value)
+		}
+	}
+	return obj
+}
+
+// Reports whether the value is a frozen array or hash
+func IsFrozen(obj Object) bool {
+	switch obj := obj.(type) {
+	case *Array:
+		obj.mu.RLock()
+		defer obj.mu.RUnlock()
+		return obj.Frozen
+	case *Hash:
+		obj.mu.RLock()
+		defer obj.mu.RUnlock()
+		return obj.Frozen
+	}
+	return false
+}
+
+// Type parameters of a generic function are bound in the scope of each call,
+// so annotations in its body can name them. They match any value
+type TypeParameter struct {
+	Position
+	Name string
+}
+
+func (tp *TypeParameter) Type() ObjectType { return TYPE_PARAMETER_OBJ }
+func (tp *TypeParameter) Inspect() string  { return tp.Name }
+func (tp *TypeParameter) Line() int        { return tp.Position.Line }
+func (tp *TypeParameter) Col() int         { return tp.Position.Col }
+
+// Declared with `interface Shape { area(); perimeter(); }`
+// A value satisfies an interface when the methods attached to its type include every method
+// of the interface, taking the same number of arguments
+type Interface struct {
+	Position
+	Name    string
+	Methods []InterfaceMethod
+}
+
+// Params are the names of the arguments the method takes after the receiver
+type InterfaceMethod struct {
+	Name   string
+	Params []string
+}
+
+func (i *Interface) Type() ObjectType { return INTERFACE_OBJ }
+func (i *Interface) Inspect() string {
+	methods := []string{}
+	for _, method := range i.Methods {
+		methods = append(methods, method.Name+"("+strings.Join(method.Params, ", ")+");")
+	}
+	return "interface " + i.Name + " { " + strings.Join(methods, " ") + " }"
+}
+func (i *Interface) Line() int { return i.Position.Line }
+func (i *Interface) Col() int  { return i.Position.Col }
+
+// Describes why a type with the given methods doesn't satisfy the interface, or "" when it does
+func (i *Interface) Unsatisfied(methods map[string]*Function) string {
+	for _, want := range i.Methods {
+		method, ok := methods[want.Name]
+		if !ok {
+			return "missing method " + want.Name
+		}
+		if len(method.Parameters) == 0 {
+			return fmt.Sprintf("method %s is missing its receiver", want.Name)
+		}
+		if got := len(method.Parameters) - 1; got != len(want.Params) {
+			return fmt.Sprintf("method %s takes %d arguments, want %d", want.Name, got, len(want.Params))
+		}
+	}
+	return ""
+}
+
+func (i *Interface) SatisfiedBy(obj Object) bool {
+	return i.Unsatisfied(MethodsOf(obj)) == ""
+}
+
+// Interfaces the interpreter itself relies on, available everywhere without being declared
+//   - Stringer: toString() is used whenever the value is shown, by println and the REPL
+//   - Iterable: iter() returns what a for-in loop or spread iterates over in its place
+//   - Comparable: compare(other) returns a negative integer, zero or a positive integer, for < and >
+var Protocols = map[string]*Interface{
+	"Stringer":   {Name: "Stringer", Methods: []InterfaceMethod{{Name: "toString"}}},
+	"Iterable":   {Name: "Iterable", Methods: []InterfaceMethod{{Name: "iter"}}},
+	"Comparable": {Name: "Comparable", Methods: []InterfaceMethod{{Name: "compare", Params: []string{"other"}}}},
+}
+
+// The methods user types define to overload operators, by operator
+// != is the negation of __eq__, and compound assignments like += use the method of the operator they're built on
+var OperatorMethods = map[string]string{
+	"+":  "__add__",
+	"-":  "__sub__",
+	"*":  "__mul__",
+	"/":  "__div__",
+	"%":  "__mod__",
+	"==": "__eq__",
+	"<":  "__lt__",
+	">":  "__gt__",
+}
+
+// The methods attached to the type of a value, nil for values whose type can't have methods
+func MethodsOf(obj Object) map[string]*Function {
+	switch obj := obj.(type) {
+	case *Record:
+		return obj.RecordType.Methods
+	case *EnumValue:
+		return obj.Variant.Enum.Methods
+	}
+	return nil
+}
+
+// Calls a method attached to the type of the receiver, reporting whether it has one
+// Methods are evaluated by the evaluator, which sets this when it is loaded
+var CallMethod func(receiver Object, name string, args ...Object) (Object, bool)
+
+// Calls a function value, set by the evaluator like CallMethod so builtins can take callbacks
+var CallFunction func(fn Object, args ...Object) Object
+
+// Applies an arithmetic operator to two numbers the way the evaluator does, set by it like CallFunction,
+// so builtins doing arithmetic give the same results and errors, like integer overflow in checked mode
+var Arithmetic func(operator string, left, right Object) Object
+
+// Turns anything a for-in loop can go over into an iterator, returning an Error for anything else
+// Set by the evaluator, since Iterable values need their iter method called
+var Iter func(obj Object) Object
+
+// Values implementing Stringer are shown with their toString method, or __str__ if they spell it like an operator method
+func inspectStringer(obj Object) (string, bool) {
+	if CallMethod == nil {
+		return "", false
+	}
+	result, ok := CallMethod(obj, "toString")
+	if !ok {
+		result, ok = CallMethod(obj, "__str__")
+	}
+	if !ok {
+		return "", false
+	}
+	if str, isString := result.(*String); isString {
+		return str.Value, true
+	}
+	return result.Inspect(), true
+}
@@ -1,6 +1,11 @@
 package object
 
-import "testing"
+import (
+	"testing"
+	"time"
+
+	"github.com/ajtroup1/clear/ast"
+)
 
 func TestStringHashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello World"}
@@ -17,3 +22,79 @@ func TestStringHashKey(t *testing.T) {
 		t.Errorf("strings with different content have same hash keys")
 	}
 }
+
+func TestDateTimeHashKey(t *testing.T) {
+	utc := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	paris := &DateTime{Value: utc.In(time.FixedZone("CEST", 2*60*60))}
+	same := &DateTime{Value: utc}
+	later := &DateTime{Value: utc.Add(time.Second)}
+
+	if same.HashKey() != paris.HashKey() || !Equals(same, paris) {
+		t.Errorf("the same instant in different zones should be equal and have the same hash key")
+	}
+	if same.HashKey() == later.HashKey() || Equals(same, later) {
+		t.Errorf("different instants should not be equal or have the same hash key")
+	}
+}
+
+func TestEqualsSelfReferencingArrays(t *testing.T) {
+	a := &Array{Elements: []Object{&Integer{Value: 1}}}
+	a.Elements = append(a.Elements, a)
+	b := &Array{Elements: []Object{&Integer{Value: 1}}}
+	b.Elements = append(b.Elements, b)
+	c := &Array{Elements: []Object{&Integer{Value: 2}}}
+	c.Elements = append(c.Elements, c)
+
+	if !Equals(a, b) {
+		t.Errorf("self-referencing arrays with same content are not equal")
+	}
+	if Equals(a, c) {
+		t.Errorf("self-referencing arrays with different content are equal")
+	}
+	if Same(a, b) {
+		t.Errorf("different arrays are reported as the same reference")
+	}
+}
+
+func TestIsTruthy(t *testing.T) {
+	tests := []struct {
+		obj      Object
+		expected bool
+	}{
+		{TRUE, true},
+		{FALSE, false},
+		{NULL, false},
+		{&Boolean{Value: true}, true},
+		{&Boolean{Value: false}, false},
+		{&Null{}, false},
+		{&Integer{Value: 0}, true},
+		{&String{Value: ""}, true},
+	}
+
+	for _, tt := range tests {
+		if IsTruthy(tt.obj) != tt.expected {
+			t.Errorf("wrong truthiness for %T %s. expected=%t", tt.obj, tt.obj.Inspect(), tt.expected)
+		}
+	}
+}
+
+func TestInterfaceUnsatisfied(t *testing.T) {
+	iface := &Interface{Name: "Shape", Methods: []InterfaceMethod{{Name: "area"}}}
+	receiver := &ast.Identifier{Value: "self"}
+
+	tests := []struct {
+		methods  map[string]*Function
+		expected string
+	}{
+		{map[string]*Function{"area": {Parameters: []*ast.Identifier{receiver}}}, ""},
+		{map[string]*Function{}, "missing method area"},
+		{map[string]*Function{"area": {}}, "method area is missing its receiver"},
+		{map[string]*Function{"area": {Parameters: []*ast.Identifier{receiver, {Value: "by"}}}}, "method area takes 1 arguments, want 0"},
+	}
+
+	for _, tt := range tests {
+		if got := iface.Unsatisfied(tt.methods); got != tt.expected {
+			t.Errorf("wrong reason. got=%q, want=%q", got, tt.expected)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package object
+
+import "regexp"
+
+const REGEX_OBJ = "REGEX"
+
+// A compiled regular expression, in Go's RE2 syntax
+type Regex struct {
+	Position
+	Value *regexp.Regexp
+}
+
+func (r *Regex) Type() ObjectType { return REGEX_OBJ }
+func (r *Regex) Inspect() string  { return "<regex " + r.Value.String() + ">" }
+func (r *Regex) Line() int        { return r.Position.Line }
+func (r *Regex) Col() int         { return r.Position.Col }
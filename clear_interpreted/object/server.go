@@ -0,0 +1,56 @@
+package object
+
+import "sync"
+
+const SERVER_OBJ = "SERVER"
+
+// A running HTTP server, which keeps the program running until it's stopped
+type Server struct {
+	Position
+	URL string
+
+	shutdown func() // stops taking new requests and waits for the ones being handled
+	stopped  chan struct{}
+	once     sync.Once
+}
+
+func NewServer(url string, shutdown func()) *Server {
+	s := &Server{URL: url, shutdown: shutdown, stopped: make(chan struct{})}
+	Hold(s)
+	return s
+}
+
+func (s *Server) Type() ObjectType { return SERVER_OBJ }
+func (s *Server) Inspect() string {
+	if s.Stopped() {
+		return "<server stopped>"
+	}
+	return "<server " + s.URL + ">"
+}
+func (s *Server) Line() int { return s.Position.Line }
+func (s *Server) Col() int  { return s.Position.Col }
+
+// Shuts the server down, reporting false when it was already stopped
+// Requests being handled are finished in the background, so a handler can stop its own server,
+// and the program keeps running until they are
+func (s *Server) Cancel() bool {
+	cancelled := false
+	s.once.Do(func() {
+		close(s.stopped)
+		go func() {
+			s.shutdown()
+			Release(s)
+		}()
+		cancelled = true
+	})
+	return cancelled
+}
+
+func (s *Server) Stopped() bool {
+	select {
+	case <-s.stopped:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,163 @@
+package object
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const TIMER_OBJ = "TIMER"
+
+// Calls a function once after a delay, or every interval until it is cancelled
+// Timers only decide when their callback is due, the event loop is what calls it
+type Timer struct {
+	Position
+	Interval time.Duration
+	Repeat   bool
+	Fn       Object
+
+	stop chan struct{}
+	once sync.Once
+}
+
+func (t *Timer) Type() ObjectType { return TIMER_OBJ }
+func (t *Timer) Inspect() string {
+	if t.Stopped() {
+		return "<timer stopped>"
+	}
+	if t.Repeat {
+		return fmt.Sprintf("<timer every %s>", t.Interval)
+	}
+	return fmt.Sprintf("<timer after %s>", t.Interval)
+}
+func (t *Timer) Line() int { return t.Position.Line }
+func (t *Timer) Col() int  { return t.Position.Col }
+
+// Makes a timer and starts counting down, so the event loop has to wait for it from now on
+func NewTimer(interval time.Duration, repeat bool, fn Object) *Timer {
+	t := &Timer{Interval: interval, Repeat: repeat, Fn: fn, stop: make(chan struct{})}
+	Hold(t)
+
+	go func() {
+		defer Release(t)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-t.stop:
+				return
+			case <-ticker.C:
+			}
+
+			// Waits until the loop takes the callback, so a slow callback delays the next tick instead of piling up calls
+			select {
+			case events.due <- t:
+			case <-t.stop:
+				return
+			}
+			if !t.Repeat {
+				t.Cancel()
+				return
+			}
+		}
+	}()
+
+	return t
+}
+
+// Stops the timer from firing again, reporting false when it was already stopped
+func (t *Timer) Cancel() bool {
+	cancelled := false
+	t.once.Do(func() {
+		close(t.stop)
+		cancelled = true
+	})
+	return cancelled
+}
+
+func (t *Timer) Stopped() bool {
+	select {
+	case <-t.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// Something that keeps the program running until it stops, like a timer or a server
+type Pending interface {
+	Cancel() bool
+}
+
+// Keeps track of what is still pending and hands over the timer callbacks that are due
+type eventLoop struct {
+	mu      sync.Mutex
+	pending map[Pending]bool
+	due     chan *Timer
+	wake    chan struct{} // signalled whenever something stops, so a waiting loop can see if it's the last one
+}
+
+var events = &eventLoop{pending: map[Pending]bool{}, due: make(chan *Timer), wake: make(chan struct{}, 1)}
+
+// Keeps the program running until Release is called with the same value
+func Hold(p Pending) {
+	events.mu.Lock()
+	defer events.mu.Unlock()
+	events.pending[p] = true
+}
+
+func Release(p Pending) {
+	events.mu.Lock()
+	delete(events.pending, p)
+	events.mu.Unlock()
+
+	select {
+	case events.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (l *eventLoop) idle() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return len(l.pending) == 0
+}
+
+// Cancels everything that hasn't stopped yet, so a program that ends with an error doesn't leave it behind
+// for whatever runs next (like the next line in the REPL)
+func StopPending() {
+	events.mu.Lock()
+	pending := make([]Pending, 0, len(events.pending))
+	for p := range events.pending {
+		pending = append(pending, p)
+	}
+	events.mu.Unlock()
+
+	for _, p := range pending {
+		p.Cancel()
+	}
+}
+
+// Calls the callbacks of timers as they come due, one at a time on the calling goroutine
+// With no deadline it runs until nothing is pending, otherwise it returns once the deadline passes
+// The first callback to return an error stops the loop and the error is returned
+func RunEvents(deadline <-chan time.Time) *Error {
+	for {
+		if deadline == nil && events.idle() {
+			return nil
+		}
+
+		select {
+		case t := <-events.due:
+			result := CallFunction(t.Fn)
+			if err, ok := result.(*Error); ok {
+				t.Cancel()
+				return err
+			}
+		case <-events.wake:
+		case <-deadline:
+			return nil
+		}
+	}
+}
@@ -37,19 +37,15 @@ func (p *Parser) parseExpression(precedence int) ast.Expression {
 }
 
 func (p *Parser) parseIdentifier() ast.Expression {
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	if p.peekTokenIs(token.DOT) {
-		return p.parseModuleAccess()
-	}
-
-	return ident
+	return &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 }
 
-func (p *Parser) parseModuleAccess() ast.Expression {
-	var val string
-	val += p.curToken.Literal
-	p.nextToken()
-	if !p.peekTokenIs(token.IDENT) {
+// Parses `object.property`, where the object is any expression already parsed
+// Keywords are allowed as property names, so `ch.close` or `x.type` still work
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: object}
+
+	if !p.peekTokenIs(token.IDENT) && !token.IsKeyword(p.peekToken.Literal) {
 		msg := fmt.Sprintf("expected next token to be IDENT, got %v instead", p.peekToken.Type)
 		err := errors.Error{
 			Message: msg,
@@ -62,8 +58,10 @@ func (p *Parser) parseModuleAccess() ast.Expression {
 		return nil
 	}
 	p.nextToken()
-	access := &ast.Identifier{Token: p.curToken, Value: val + "." + p.curToken.Literal}
-	return access
+
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp
 }
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
@@ -243,49 +241,302 @@ func (p *Parser) parseIfExpression() ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseMatchExpression() ast.Expression {
+	expression := &ast.MatchExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	p.nextToken()
+	expression.Subject = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) && !p.peekTokenIs(token.EOF) {
+		p.nextToken()
+
+		arm := p.parseMatchArm()
+		if arm == nil {
+			return nil
+		}
+		expression.Arms = append(expression.Arms, arm)
+
+		// Arms may be separated by commas or semicolons, but don't have to be
+		if p.peekTokenIs(token.COMMA) || p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return expression
+}
+
+func (p *Parser) parseMatchArm() *ast.MatchArm {
+	pattern := p.parsePattern()
+	if pattern == nil {
+		return nil
+	}
+
+	var guard ast.Expression
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		p.nextToken()
+		guard = p.parseExpression(LOWEST)
+	}
+
+	if !p.expectPeek(token.FAT_ARROW) {
+		return nil
+	}
+	arm := &ast.MatchArm{Token: p.curToken, Pattern: pattern, Guard: guard}
+
+	arm.Body = p.parseArmBody()
+	if arm.Body == nil {
+		return nil
+	}
+
+	return arm
+}
+
+// Parses the body after a '=>', either a block or a lone expression wrapped in one
+func (p *Parser) parseArmBody() *ast.BlockStatement {
+	arrow := p.curToken
+
+	p.nextToken()
+	if p.curTokenIs(token.LBRACE) {
+		return p.parseBlockStatement()
+	}
+
+	body := p.parseExpression(LOWEST)
+	if body == nil {
+		return nil
+	}
+	return &ast.BlockStatement{
+		Token:      arrow,
+		Statements: []ast.Statement{&ast.ExpressionStatement{Token: arrow, Expression: body}},
+	}
+}
+
+func (p *Parser) parseSpawnExpression() ast.Expression {
+	exp := &ast.SpawnExpression{Token: p.curToken}
+
+	p.nextToken()
+	call, ok := p.parseExpression(PREFIX).(*ast.CallExpression)
+	if !ok {
+		err := errors.Error{
+			Message: "spawn needs a function call, like `spawn worker(job)`",
+			Line:    exp.Token.Line,
+			Col:     exp.Token.Col,
+			Stage:   "Parsing",
+			Context: p.l.Lines[exp.Token.Line-1],
+		}
+		p.Errors = append(p.Errors, &err)
+		return nil
+	}
+	exp.Call = call
+
+	return exp
+}
+
+func (p *Parser) parseSelectExpression() ast.Expression {
+	expression := &ast.SelectExpression{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RBRACE) && !p.peekTokenIs(token.EOF) {
+		p.nextToken()
+
+		c := p.parseSelectCase()
+		if c == nil {
+			return nil
+		}
+		expression.Cases = append(expression.Cases, c)
+
+		// Cases may be separated by commas or semicolons, but don't have to be
+		if p.peekTokenIs(token.COMMA) || p.peekTokenIs(token.SEMICOLON) {
+			p.nextToken()
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return expression
+}
+
+// Cases are `recv(ch)`, `name = recv(ch)`, `send(ch, value)` or `_`
+// recv and send are only special here, elsewhere they're the builtins of the same name
+func (p *Parser) parseSelectCase() *ast.SelectCase {
+	c := &ast.SelectCase{}
+	start := p.curToken
+
+	if p.curTokenIs(token.IDENT) && p.curToken.Literal != "_" && p.peekTokenIs(token.ASSIGN) {
+		c.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		p.nextToken()
+		p.nextToken()
+	}
+
+	if !p.curTokenIs(token.IDENT) || p.curToken.Literal != "_" || c.Name != nil {
+		call, ok := p.parseExpression(LOWEST).(*ast.CallExpression)
+		if !ok || !isSelectOperation(call, c.Name != nil) {
+			err := errors.Error{
+				Message: "select cases must be recv(ch), name = recv(ch), send(ch, value) or _",
+				Line:    start.Line,
+				Col:     start.Col,
+				Stage:   "Parsing",
+				Context: p.l.Lines[start.Line-1],
+			}
+			p.Errors = append(p.Errors, &err)
+			return nil
+		}
+		c.Operation = call
+	}
+
+	if !p.expectPeek(token.FAT_ARROW) {
+		return nil
+	}
+	c.Token = p.curToken
+
+	c.Body = p.parseArmBody()
+	if c.Body == nil {
+		return nil
+	}
+
+	return c
+}
+
+func isSelectOperation(call *ast.CallExpression, named bool) bool {
+	fn, ok := call.Function.(*ast.Identifier)
+	if !ok {
+		return false
+	}
+	switch fn.Value {
+	case "recv":
+		return len(call.Arguments) == 1
+	case "send":
+		return len(call.Arguments) == 2 && !named
+	}
+	return false
+}
+
 func (p *Parser) parseFunctionLiteral() ast.Expression {
-	lit := &ast.FunctionLiteral{Token: p.curToken}
+	return p.parseFunction(p.curToken)
+}
+
+// Parses what follows `fn` (or the name after it in a declaration), starting on the token before the parameters
+func (p *Parser) parseFunction(fnToken token.Token) ast.Expression {
+	lit := &ast.FunctionLiteral{Token: fnToken, Generator: fnToken.Literal == "fn*"}
+
+	if p.peekTokenIs(token.LT) {
+		p.nextToken()
+		lit.TypeParameters = p.parseTypeParameters()
+		if lit.TypeParameters == nil {
+			return nil
+		}
+	}
 
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
 
-	lit.Parameters = p.parseFunctionParameters()
+	lit.Parameters, lit.ParameterTypes = p.parseFunctionParameters()
+	if lit.Parameters == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.ARROW) {
+		p.nextToken()
+		lit.ReturnType = p.parseType()
+		if lit.ReturnType == nil {
+			return nil
+		}
+	}
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
+	// Functions nested in a generator aren't generators themselves, so they can't yield for it
+	outer := p.inGenerator
+	p.inGenerator = lit.Generator
 	lit.Body = p.parseBlockStatement()
+	p.inGenerator = outer
 
 	return lit
 }
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	identifiers := []*ast.Identifier{}
+// Type parameters of a generic function, `<T>` or `<K, V>`, starting on the '<' token
+func (p *Parser) parseTypeParameters() []*ast.Identifier {
+	params := []*ast.Identifier{}
 
-	if p.peekTokenIs(token.RPAREN) {
+	for {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		params = append(params, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
 		p.nextToken()
-		return identifiers
 	}
 
-	p.nextToken()
+	if !p.expectPeek(token.GT) {
+		return nil
+	}
 
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, ident)
+	return params
+}
 
-	for p.peekTokenIs(token.COMMA) {
+// Each parameter may be annotated with a type, `a: int`
+// The returned types line up with the parameters, with nil for unannotated ones
+func (p *Parser) parseFunctionParameters() ([]*ast.Identifier, []*ast.TypeExpr) {
+	identifiers := []*ast.Identifier{}
+	types := []*ast.TypeExpr{}
+
+	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
+		return identifiers, types
+	}
+
+	for {
 		p.nextToken()
 		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 		identifiers = append(identifiers, ident)
+
+		var typeName *ast.TypeExpr
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			typeName = p.parseType()
+			if typeName == nil {
+				return nil, nil
+			}
+		}
+		types = append(types, typeName)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
 	}
 
 	if !p.expectPeek(token.RPAREN) {
-		return nil
+		return nil, nil
 	}
 
-	return identifiers
+	return identifiers, types
 }
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
@@ -319,28 +570,66 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 	return list
 }
 
+// Parses both plain index expressions (arr[i]) and slices (arr[start:end:step])
+// Encountering a `:` inside the brackets is what makes it a slice
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	bracket := p.curToken
 	p.nextToken()
-	exp.Index = p.parseExpression(LOWEST)
+
+	var start ast.Expression
+	if !p.curTokenIs(token.COLON) {
+		start = p.parseExpression(LOWEST)
+		if !p.peekTokenIs(token.COLON) {
+			if !p.expectPeek(token.RBRACKET) {
+				return nil
+			}
+			return &ast.IndexExpression{Token: bracket, Left: left, Index: start}
+		}
+		p.nextToken()
+	}
+
+	slice := &ast.SliceExpression{Token: bracket, Left: left, Start: start}
+
+	if !p.peekTokenIs(token.COLON) && !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		slice.End = p.parseExpression(LOWEST)
+	}
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		if !p.peekTokenIs(token.RBRACKET) {
+			p.nextToken()
+			slice.Step = p.parseExpression(LOWEST)
+		}
+	}
+
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
 	}
-	return exp
+
+	return slice
 }
 
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.curToken}
-	hash.Pairs = make(map[ast.Expression]ast.Expression)
+	hash.Pairs = []*ast.HashLiteralPair{}
 	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
 		key := p.parseExpression(LOWEST)
+		// Spread entries have no value of their own
+		if _, ok := key.(*ast.SpreadExpression); ok {
+			hash.Pairs = append(hash.Pairs, &ast.HashLiteralPair{Key: key})
+			if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+			continue
+		}
 		if !p.expectPeek(token.COLON) {
 			return nil
 		}
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
-		hash.Pairs[key] = value
+		hash.Pairs = append(hash.Pairs, &ast.HashLiteralPair{Key: key, Value: value})
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
 		}
@@ -351,6 +640,57 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	return hash
 }
 
+func (p *Parser) parseSpreadExpression() ast.Expression {
+	exp := &ast.SpreadExpression{Token: p.curToken}
+	p.nextToken()
+	// Everything up to the next comma is spread, so [...0..3] spreads the whole range
+	exp.Value = p.parseExpression(LOWEST)
+	return exp
+}
+
+func (p *Parser) parseYieldExpression() ast.Expression {
+	exp := &ast.YieldExpression{Token: p.curToken}
+	if !p.inGenerator {
+		err := errors.Error{
+			Message: "yield is only allowed inside a generator, declared with fn*",
+			Line:    p.curToken.Line,
+			Col:     p.curToken.Col,
+			Stage:   "Parsing",
+			Context: p.l.Lines[p.curToken.Line-1],
+		}
+		p.Errors = append(p.Errors, &err)
+		return nil
+	}
+
+	p.nextToken()
+	exp.Value = p.parseExpression(LOWEST)
+	if exp.Value == nil {
+		return nil
+	}
+	return exp
+}
+
+func (p *Parser) parseRangeExpression(start ast.Expression) ast.Expression {
+	exp := &ast.RangeExpression{
+		Token:     p.curToken,
+		Start:     start,
+		Inclusive: p.curTokenIs(token.RANGE),
+	}
+
+	precedence := p.curPrecedence()
+	p.nextToken()
+	exp.End = p.parseExpression(precedence)
+
+	// `step` is only special right after a range, so it stays usable as an identifier
+	if p.peekTokenIs(token.IDENT) && p.peekToken.Literal == "step" {
+		p.nextToken()
+		p.nextToken()
+		exp.Step = p.parseExpression(precedence)
+	}
+
+	return exp
+}
+
 func (p *Parser) parseNewInstanceExpression() ast.Expression {
 	exp := &ast.NewInstanceExpression{Token: p.curToken}
 	
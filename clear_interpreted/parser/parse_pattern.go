@@ -0,0 +1,255 @@
+package parser
+
+import (
+	"fmt"
+
+	"github.com/ajtroup1/clear/ast"
+	"github.com/ajtroup1/clear/errors"
+	"github.com/ajtroup1/clear/token"
+)
+
+// Patterns are parsed separately from expressions, since names in a pattern
+// introduce bindings instead of referring to existing variables
+// The current token is the first token of the pattern
+func (p *Parser) parsePattern() ast.Pattern {
+	var pattern ast.Pattern
+
+	switch p.curToken.Type {
+	case token.IDENT:
+		if p.peekTokenIs(token.DOT) {
+			pattern = p.parseVariantPattern()
+		} else if p.curToken.Literal == "_" {
+			pattern = &ast.WildcardPattern{Token: p.curToken}
+		} else {
+			pattern = &ast.BindingPattern{Token: p.curToken, Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+		}
+	case token.INT, token.FLOAT, token.STRING, token.TRUE, token.FALSE, token.MINUS:
+		pattern = p.parseLiteralPattern()
+	case token.LBRACKET:
+		pattern = p.parseArrayPattern()
+	case token.LBRACE:
+		pattern = p.parseHashPattern()
+	default:
+		p.patternError(fmt.Sprintf("unexpected %s ('%s') in pattern", p.curToken.Type, p.curToken.Literal))
+		return nil
+	}
+
+	if pattern == nil {
+		return nil
+	}
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		typed := &ast.TypePattern{Token: p.curToken, Pattern: pattern}
+		typed.TypeName = p.parseType()
+		if typed.TypeName == nil {
+			return nil
+		}
+		return typed
+	}
+
+	return pattern
+}
+
+// Enum variants are always qualified by their enum, Color.Red, so they can't be
+// confused with a binding
+func (p *Parser) parseVariantPattern() ast.Pattern {
+	pattern := &ast.VariantPattern{Token: p.curToken, Enum: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+
+	p.nextToken()
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	pattern.Variant = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.peekTokenIs(token.LPAREN) {
+		return pattern
+	}
+	p.nextToken()
+
+	pattern.Fields = []ast.Pattern{}
+	for !p.peekTokenIs(token.RPAREN) {
+		p.nextToken()
+		field := p.parsePattern()
+		if field == nil {
+			return nil
+		}
+		pattern.Fields = append(pattern.Fields, field)
+
+		if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+	p.nextToken()
+
+	return pattern
+}
+
+// Literal patterns may be followed by `..` or `..<` to form a range pattern
+func (p *Parser) parseLiteralPattern() ast.Pattern {
+	start := p.parsePatternLiteral()
+	if start == nil {
+		return nil
+	}
+
+	if !p.peekTokenIs(token.RANGE) && !p.peekTokenIs(token.RANGE_EXCL) {
+		return &ast.LiteralPattern{Token: p.curToken, Value: start}
+	}
+
+	p.nextToken()
+	pattern := &ast.RangePattern{Token: p.curToken, Start: start, Inclusive: p.curTokenIs(token.RANGE)}
+	p.nextToken()
+	pattern.End = p.parsePatternLiteral()
+	if pattern.End == nil {
+		return nil
+	}
+
+	return pattern
+}
+
+// Only literals (and negated numbers) are allowed, not arbitrary expressions
+func (p *Parser) parsePatternLiteral() ast.Expression {
+	switch p.curToken.Type {
+	case token.INT:
+		return p.parseIntegerLiteral()
+	case token.FLOAT:
+		return p.parseFloatLiteral()
+	case token.STRING:
+		return p.parseStringLiteral()
+	case token.TRUE, token.FALSE:
+		return p.parseBoolean()
+	case token.MINUS:
+		exp := &ast.PrefixExpression{Token: p.curToken, Operator: p.curToken.Literal}
+		if !p.peekTokenIs(token.INT) && !p.peekTokenIs(token.FLOAT) {
+			p.patternError(fmt.Sprintf("expected a number after '-' in pattern, got %s instead", p.peekToken.Type))
+			return nil
+		}
+		p.nextToken()
+		exp.Right = p.parsePatternLiteral()
+		return exp
+	default:
+		p.patternError(fmt.Sprintf("expected a literal in pattern, got %s ('%s') instead", p.curToken.Type, p.curToken.Literal))
+		return nil
+	}
+}
+
+func (p *Parser) parseArrayPattern() ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.curToken, Elements: []ast.Pattern{}}
+
+	if p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		return pattern
+	}
+
+	for {
+		p.nextToken()
+
+		if p.curTokenIs(token.ELLIPSIS) {
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+			pattern.Rest = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+			// The rest of the array has to come last
+			if !p.expectPeek(token.RBRACKET) {
+				return nil
+			}
+			return pattern
+		}
+
+		element := p.parseDefaultPattern(p.parsePattern())
+		if element == nil {
+			return nil
+		}
+		pattern.Elements = append(pattern.Elements, element)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return pattern
+}
+
+func (p *Parser) parseHashPattern() ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.curToken, Pairs: []*ast.HashPatternPair{}}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		p.nextToken()
+
+		pair := &ast.HashPatternPair{}
+		switch p.curToken.Type {
+		case token.IDENT:
+			// Bare names are used as string keys, so {name} and {name: n} both read the "name" key
+			pair.Key = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+			if !p.peekTokenIs(token.COLON) {
+				binding := &ast.BindingPattern{Token: p.curToken, Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+				pair.Value = p.parseDefaultPattern(binding)
+				if pair.Value == nil {
+					return nil
+				}
+			}
+		case token.STRING, token.INT, token.TRUE, token.FALSE:
+			pair.Key = p.parsePatternLiteral()
+		default:
+			p.patternError(fmt.Sprintf("unexpected %s ('%s') as hash pattern key", p.curToken.Type, p.curToken.Literal))
+			return nil
+		}
+
+		if pair.Value == nil {
+			if !p.expectPeek(token.COLON) {
+				return nil
+			}
+			p.nextToken()
+			pair.Value = p.parseDefaultPattern(p.parsePattern())
+			if pair.Value == nil {
+				return nil
+			}
+		}
+
+		pattern.Pairs = append(pattern.Pairs, pair)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return pattern
+}
+
+// Wraps the pattern in a DefaultPattern if it is followed by `= default`
+// Defaults only make sense for array elements and hash values, which may be missing
+func (p *Parser) parseDefaultPattern(pattern ast.Pattern) ast.Pattern {
+	if pattern == nil || !p.peekTokenIs(token.ASSIGN) {
+		return pattern
+	}
+
+	p.nextToken()
+	def := &ast.DefaultPattern{Token: p.curToken, Pattern: pattern}
+	p.nextToken()
+	def.Default = p.parseExpression(LOWEST)
+	if def.Default == nil {
+		return nil
+	}
+
+	return def
+}
+
+func (p *Parser) patternError(msg string) {
+	err := errors.Error{
+		Message: msg,
+		Line:    p.curToken.Line,
+		Col:     p.curToken.Col,
+		Stage:   "Parsing",
+		Context: p.curToken.Literal,
+	}
+	p.Errors = append(p.Errors, &err)
+}
@@ -16,7 +16,7 @@ func (p *Parser) parseStatement() ast.Statement {
 	switch p.curToken.Type {
 	case token.MOD:
 		return p.parseModuleStatement()
-	case token.LET:
+	case token.LET, token.CONST:
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
@@ -28,6 +28,20 @@ func (p *Parser) parseStatement() ast.Statement {
 		return p.parseContinueStatement()
 	case token.BREAK:
 		return p.parseBreakStatement()
+	case token.TYPE:
+		return p.parseTypeStatement()
+	case token.ENUM:
+		return p.parseEnumStatement()
+	case token.INTERFACE:
+		return p.parseInterfaceStatement()
+	case token.IMPL:
+		return p.parseImplStatement()
+	case token.FUNCTION:
+		// `fn name(...)` declares a function, anything else is a function literal expression
+		if p.peekTokenIs(token.IDENT) {
+			return p.parseFunctionStatement()
+		}
+		return p.parseExpressionOrAssignStatement()
 	default:
 		// If no explicit statement keyword is defined, it's either an expression or an assignment statement
 		if p.debug {
@@ -153,20 +167,41 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 		p.log.AppendParser(fmt.Sprintf("%d. Steps in parsing let statement:\n", p.encounterCount))
 		p.log.AppendParser(fmt.Sprintf("\n\ta. Assigning token to the statement to track positioning [line: %d, col: %d]\n", p.curToken.Line, p.curToken.Col))
 	}
-	stmt := &ast.LetStatement{Token: p.curToken}
+	// `const` declarations are let statements that can't be reassigned later
+	stmt := &ast.LetStatement{Token: p.curToken, Constant: p.curTokenIs(token.CONST)}
 
-	if !p.expectPeek(token.IDENT) {
-		return nil
-	}
+	if p.peekTokenIs(token.LBRACKET) || p.peekTokenIs(token.LBRACE) {
+		p.nextToken()
+		if p.debug {
+			p.log.AppendParser("\n\tb. Encountered a destructuring pattern, parsing it instead of a single identifier\n")
+		}
+		stmt.Pattern = p.parsePattern()
+		if stmt.Pattern == nil {
+			return nil
+		}
+	} else {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
 
-	if p.debug {
-		p.log.AppendParser(fmt.Sprintf("\n\tb. Assigning valid identifier `%s` to the let statement\n", p.curToken.Literal))
-	}
-	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if p.debug {
+			p.log.AppendParser(fmt.Sprintf("\n\tb. Assigning valid identifier `%s` to the let statement\n", p.curToken.Literal))
+		}
+		stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
-	if p.peekTokenIs(token.SEMICOLON) {
-		p.nextToken()
-		return stmt
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			stmt.TypeName = p.parseType()
+			if stmt.TypeName == nil {
+				return nil
+			}
+		}
+
+		// Constants have to be given a value when they're declared
+		if p.peekTokenIs(token.SEMICOLON) && !stmt.Constant {
+			p.nextToken()
+			return stmt
+		}
 	}
 
 	if !p.expectPeek(token.ASSIGN) {
@@ -248,18 +283,24 @@ func (p *Parser) parseWhileStatement() *ast.WhileStatement {
 		return nil
 	}
 
-	p.nextToken()
-
 	return stmt
 }
 
-func (p *Parser) parseForStatement() *ast.ForStatement {
-	stmt := &ast.ForStatement{Token: p.curToken}
+// C-style loops always start with a `let` initializer,
+// anything else is parsed as a for-in loop
+func (p *Parser) parseForStatement() ast.Statement {
+	forToken := p.curToken
 
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
 
+	if !p.peekTokenIs(token.LET) {
+		return p.parseForInStatement(forToken)
+	}
+
+	stmt := &ast.ForStatement{Token: forToken}
+
 	if !p.expectPeek(token.LET) {
 		return nil
 	}
@@ -297,6 +338,43 @@ func (p *Parser) parseForStatement() *ast.ForStatement {
 	return stmt
 }
 
+func (p *Parser) parseForInStatement(forToken token.Token) ast.Statement {
+	stmt := &ast.ForInStatement{Token: forToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.COMMA) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Key = stmt.Value
+		stmt.Value = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+	p.nextToken()
+
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+
+	return stmt
+}
+
 func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
 	stmt := &ast.ContinueStatement{Token: p.curToken}
 
@@ -307,6 +385,293 @@ func (p *Parser) parseContinueStatement() *ast.ContinueStatement {
 	return stmt
 }
 
+func (p *Parser) parseTypeStatement() ast.Statement {
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("%d. Steps in parsing type statement:\n", p.encounterCount))
+		p.log.AppendParser(fmt.Sprintf("\n\ta. Assigning token to the statement to track positioning [line: %d, col: %d]\n", p.curToken.Line, p.curToken.Col))
+	}
+	stmt := &ast.TypeStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("\n\tb. Parsing the fields of type `%s`\n", stmt.Name.Value))
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		field := &ast.FieldDecl{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+
+		if p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			field.TypeName = p.parseType()
+			if field.TypeName == nil {
+				return nil
+			}
+		}
+
+		if p.peekTokenIs(token.ASSIGN) {
+			p.nextToken()
+			p.nextToken()
+			field.Default = p.parseExpression(LOWEST)
+		}
+
+		stmt.Fields = append(stmt.Fields, field)
+
+		// Fields are separated by commas, a trailing comma is fine
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("\n\tc. Successfully parsed the entire type statement: `%s`\n", stmt.String()))
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseEnumStatement() ast.Statement {
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("%d. Steps in parsing enum statement:\n", p.encounterCount))
+		p.log.AppendParser(fmt.Sprintf("\n\ta. Assigning token to the statement to track positioning [line: %d, col: %d]\n", p.curToken.Line, p.curToken.Col))
+	}
+	stmt := &ast.EnumStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("\n\tb. Parsing the variants of enum `%s`\n", stmt.Name.Value))
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		variant := &ast.VariantDecl{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+
+		// Variants with a field list carry a payload, `Circle(r)`
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+			variant.Fields = []*ast.Identifier{}
+			for !p.peekTokenIs(token.RPAREN) {
+				if !p.expectPeek(token.IDENT) {
+					return nil
+				}
+				variant.Fields = append(variant.Fields, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal})
+				if !p.peekTokenIs(token.RPAREN) && !p.expectPeek(token.COMMA) {
+					return nil
+				}
+			}
+			p.nextToken()
+		}
+
+		if p.peekTokenIs(token.ASSIGN) {
+			p.nextToken()
+			p.nextToken()
+			variant.Value = p.parseExpression(LOWEST)
+		}
+
+		stmt.Variants = append(stmt.Variants, variant)
+
+		// Variants are separated by commas, a trailing comma is fine
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("\n\tc. Successfully parsed the entire enum statement: `%s`\n", stmt.String()))
+	}
+
+	return stmt
+}
+
+// `fn first<T>(xs: array<T>) -> T { ... }` is shorthand for `let first = fn<T>(xs: array<T>) -> T { ... };`
+func (p *Parser) parseFunctionStatement() ast.Statement {
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("%d. Steps in parsing function declaration:\n", p.encounterCount))
+		p.log.AppendParser(fmt.Sprintf("\n\ta. Assigning token to the statement to track positioning [line: %d, col: %d]\n", p.curToken.Line, p.curToken.Col))
+	}
+	fnToken := p.curToken
+	stmt := &ast.LetStatement{Token: token.Token{Type: token.LET, Literal: "let", Line: fnToken.Line, Col: fnToken.Col}}
+
+	p.nextToken()
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	lit, ok := p.parseFunction(fnToken).(*ast.FunctionLiteral)
+	if !ok {
+		return nil
+	}
+	stmt.Value = lit
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("\n\tb. Successfully parsed the function declaration: `%s`\n", stmt.String()))
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseInterfaceStatement() ast.Statement {
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("%d. Steps in parsing interface statement:\n", p.encounterCount))
+		p.log.AppendParser(fmt.Sprintf("\n\ta. Assigning token to the statement to track positioning [line: %d, col: %d]\n", p.curToken.Line, p.curToken.Col))
+	}
+	stmt := &ast.InterfaceStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("\n\tb. Parsing the methods of interface `%s`\n", stmt.Name.Value))
+	}
+
+	// Each method is a name and a parameter list, with an optional return type
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		method := &ast.MethodSignature{Name: &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}}
+
+		if !p.expectPeek(token.LPAREN) {
+			return nil
+		}
+		method.Parameters, method.ParameterTypes = p.parseFunctionParameters()
+		if method.Parameters == nil {
+			return nil
+		}
+
+		if p.peekTokenIs(token.ARROW) {
+			p.nextToken()
+			method.ReturnType = p.parseType()
+			if method.ReturnType == nil {
+				return nil
+			}
+		}
+		stmt.Methods = append(stmt.Methods, method)
+
+		if p.peekTokenIs(token.SEMICOLON) || p.peekTokenIs(token.COMMA) {
+			p.nextToken()
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("\n\tc. Successfully parsed the entire interface statement: `%s`\n", stmt.String()))
+	}
+
+	return stmt
+}
+
+// `impl Circle { ... }` or `impl Shape for Circle { ... }`, containing function declarations
+func (p *Parser) parseImplStatement() ast.Statement {
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("%d. Steps in parsing impl statement:\n", p.encounterCount))
+		p.log.AppendParser(fmt.Sprintf("\n\ta. Assigning token to the statement to track positioning [line: %d, col: %d]\n", p.curToken.Line, p.curToken.Col))
+	}
+	stmt := &ast.ImplStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	stmt.Target = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.FOR) {
+		p.nextToken()
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+		stmt.Interface = stmt.Target
+		stmt.Target = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("\n\tb. Parsing the methods attached to `%s`\n", stmt.Target.Value))
+	}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.FUNCTION) {
+			return nil
+		}
+		if !p.peekTokenIs(token.IDENT) {
+			p.peekError(token.IDENT)
+			return nil
+		}
+		method, ok := p.parseFunctionStatement().(*ast.LetStatement)
+		if !ok {
+			return nil
+		}
+		stmt.Methods = append(stmt.Methods, method)
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	if p.debug {
+		p.log.AppendParser(fmt.Sprintf("\n\tc. Successfully parsed the entire impl statement: `%s`\n", stmt.String()))
+	}
+
+	return stmt
+}
+
 func (p *Parser) parseBreakStatement() *ast.BreakStatement {
 	stmt := &ast.BreakStatement{Token: p.curToken}
 
@@ -19,6 +19,7 @@ const (
 	LOWEST
 	EQUALS      // ==
 	LESSGREATER // > or <
+	RANGE       // 0..10
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // -x or !x
@@ -28,22 +29,29 @@ const (
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.PLUS_EQ:  SUM,
-	token.MINUS_EQ: SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.MULT_EQ:  PRODUCT,
-	token.DIV_EQ:   PRODUCT,
-	token.LPAREN:   CALL,
-	token.LBRACKET: INDEX,
-	token.INC:      POSTFIX,
-	token.DEC:      POSTFIX,
+	token.EQ:            EQUALS,
+	token.NOT_EQ:        EQUALS,
+	token.IDENTICAL:     EQUALS,
+	token.NOT_IDENTICAL: EQUALS,
+	token.LT:            LESSGREATER,
+	token.IS:            LESSGREATER,
+	token.GT:            LESSGREATER,
+	token.RANGE:         RANGE,
+	token.RANGE_EXCL:    RANGE,
+	token.PLUS:          SUM,
+	token.MINUS:         SUM,
+	token.PLUS_EQ:       SUM,
+	token.MINUS_EQ:      SUM,
+	token.SLASH:         PRODUCT,
+	token.ASTERISK:      PRODUCT,
+	token.MODULO:        PRODUCT,
+	token.MULT_EQ:       PRODUCT,
+	token.DIV_EQ:        PRODUCT,
+	token.LPAREN:        CALL,
+	token.LBRACKET:      INDEX,
+	token.DOT:           INDEX,
+	token.INC:           POSTFIX,
+	token.DEC:           POSTFIX,
 }
 
 type (
@@ -63,6 +71,8 @@ type Parser struct {
 	curToken  token.Token
 	peekToken token.Token
 
+	inGenerator bool // whether the body being parsed belongs to a generator, the only place yield is allowed
+
 	prefixParseFns  map[token.TokenType]prefixParseFn
 	infixParseFns   map[token.TokenType]infixParseFn
 	postfixParseFns map[token.TokenType]postfixParseFn
@@ -91,21 +101,33 @@ func New(l *lexer.Lexer, log *logger.Logger, debug bool) *Parser {
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+	p.registerPrefix(token.ELLIPSIS, p.parseSpreadExpression)
+	p.registerPrefix(token.YIELD, p.parseYieldExpression)
+	p.registerPrefix(token.SPAWN, p.parseSpawnExpression)
+	p.registerPrefix(token.SELECT, p.parseSelectExpression)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
 	p.registerInfix(token.MINUS, p.parseInfixExpression)
 	p.registerInfix(token.SLASH, p.parseInfixExpression)
 	p.registerInfix(token.ASTERISK, p.parseInfixExpression)
+	p.registerInfix(token.MODULO, p.parseInfixExpression)
 	p.registerInfix(token.PLUS_EQ, p.parseInfixExpression)
 	p.registerInfix(token.MINUS_EQ, p.parseInfixExpression)
 	p.registerInfix(token.MULT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.DIV_EQ, p.parseInfixExpression)
 	p.registerInfix(token.EQ, p.parseInfixExpression)
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
+	p.registerInfix(token.IDENTICAL, p.parseInfixExpression)
+	p.registerInfix(token.NOT_IDENTICAL, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.IS, p.parseInfixExpression)
+	p.registerInfix(token.RANGE, p.parseRangeExpression)
+	p.registerInfix(token.RANGE_EXCL, p.parseRangeExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 
 	p.postfixParseFns = make(map[token.TokenType]postfixParseFn)
@@ -142,6 +164,41 @@ func (p *Parser) expectPeek(t token.TokenType) bool {
 	}
 }
 
+// Advances onto a type annotation, used by annotations and type patterns
+// Type names are identifiers, except for `fn` which is a keyword, and may take type arguments: array<int>, hash<string, User>
+func (p *Parser) parseType() *ast.TypeExpr {
+	if p.peekTokenIs(token.FUNCTION) {
+		p.nextToken()
+	} else if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+	typ := &ast.TypeExpr{Token: p.curToken, Name: p.curToken.Literal}
+
+	if !p.peekTokenIs(token.LT) {
+		return typ
+	}
+	p.nextToken()
+
+	for {
+		arg := p.parseType()
+		if arg == nil {
+			return nil
+		}
+		typ.Args = append(typ.Args, arg)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
+	}
+
+	if !p.expectPeek(token.GT) {
+		return nil
+	}
+
+	return typ
+}
+
 func (p *Parser) peekError(t token.TokenType) {
 	msg := fmt.Sprintf("expected next token to be %s, got %s ('%s') instead",
 		t, p.peekToken.Type, p.peekToken.Literal)
@@ -186,7 +243,7 @@ func (p *Parser) noPrefixParseFnError(t token.TokenType) {
 
 func isStatement(t token.TokenType) bool {
 	switch t {
-	case token.LET, token.RETURN, token.MOD:
+	case token.LET, token.CONST, token.RETURN, token.MOD, token.TYPE, token.ENUM, token.INTERFACE, token.IMPL:
 		return true
 	}
 	return false
@@ -210,6 +267,9 @@ func (p *Parser) ParseProgram() *ast.Program {
 
 		result := checkNilStmt(stmt)
 		if result {
+			// Still step past the offending token, otherwise a statement that
+			// fails on its first token would be parsed again forever
+			p.nextToken()
 			continue
 		} else {
 			if p.debug {
@@ -292,6 +352,11 @@ func checkNilStmt(stmt ast.Statement) bool {
 			return true
 		}
 	}
+	if mod, ok := stmt.(*ast.ModuleStatement); ok {
+		if mod == nil {
+			return true
+		}
+	}
 	return false
 }
 
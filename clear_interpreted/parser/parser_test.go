@@ -797,13 +797,16 @@ func TestParsingHashLiteralsStringKeys(t *testing.T) {
 		"two":   2,
 		"three": 3,
 	}
-	for key, value := range hash.Pairs {
-		literal, ok := key.(*ast.StringLiteral)
+	for i, pair := range hash.Pairs {
+		literal, ok := pair.Key.(*ast.StringLiteral)
 		if !ok {
-			t.Errorf("key is not ast.StringLiteral. got=%T", key)
+			t.Errorf("key is not ast.StringLiteral. got=%T", pair.Key)
+		}
+		if literal.String() != []string{"one", "two", "three"}[i] {
+			t.Errorf("hash.Pairs[%d] is out of source order. got=%q", i, literal.String())
 		}
 		expectedValue := expected[literal.String()]
-		testIntegerLiteral(t, value, expectedValue)
+		testIntegerLiteral(t, pair.Value, expectedValue)
 	}
 }
 
@@ -850,10 +853,10 @@ func TestParsingHashLiteralsWithExpressions(t *testing.T) {
 			testInfixExpression(t, e, 15, "/", 5)
 		},
 	}
-	for key, value := range hash.Pairs {
-		literal, ok := key.(*ast.StringLiteral)
+	for _, pair := range hash.Pairs {
+		literal, ok := pair.Key.(*ast.StringLiteral)
 		if !ok {
-			t.Errorf("key is not ast.StringLiteral. got=%T", key)
+			t.Errorf("key is not ast.StringLiteral. got=%T", pair.Key)
 			continue
 		}
 		testFunc, ok := tests[literal.String()]
@@ -861,7 +864,7 @@ func TestParsingHashLiteralsWithExpressions(t *testing.T) {
 			t.Errorf("No test function for key %q found", literal.String())
 			continue
 		}
-		testFunc(value)
+		testFunc(pair.Value)
 	}
 }
 
@@ -905,6 +908,456 @@ func TestParsingIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestParsingSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"a[1:3]", "(a[1:3])"},
+		{"a[:n]", "(a[:n])"},
+		{"a[2:]", "(a[2:])"},
+		{"a[:]", "(a[:])"},
+		{"a[::2]", "(a[::2])"},
+		{"a[1:-1:2]", "(a[1:(-1):2])"},
+		{"a[i + 1:len(a)]", "(a[(i + 1):len(a)])"},
+		{"a[-1]", "(a[(-1)])"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestParsingMemberExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"math.sqrt", "math.sqrt"},
+		{"config.db.host", "config.db.host"},
+		{"math.sqrt(16)", "math.sqrt(16)"},
+		{`"abc".upper()`, "abc.upper()"},
+		{"arr.push(1)", "arr.push(1)"},
+		{"-user.age", "(-user.age)"},
+		{"user.age * 2", "(user.age * 2)"},
+		{"users[0].name", "(users[0]).name"},
+		{"(a + b).c", "(a + b).c"},
+		{"ch.close()", "ch.close()"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	log := logger.NewLogger()
+	l := lexer.New("config.db", log, false)
+	p := New(l, log, false)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	member, ok := stmt.Expression.(*ast.MemberExpression)
+	if !ok {
+		t.Fatalf("exp not *ast.MemberExpression. got=%T", stmt.Expression)
+	}
+	if !testIdentifier(t, member.Object, "config") {
+		return
+	}
+	testIdentifier(t, member.Property, "db")
+}
+
+func TestParsingMatchExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"match (x) { 0 => a, _ => b }", "match (x) { 0 => a, _ => b }"},
+		{"match (x) { n if n > 10 => n }", "match (x) { n if (n > 10) => n }"},
+		{"match (x) { [first, ...rest] => first }", "match (x) { [first, ...rest] => first }"},
+		{"match (x) { [] => 0; [a, [b, _]] => b }", "match (x) { [] => 0, [a, [b, _]] => b }"},
+		{`match (x) { {"type": "user", "name": n} => n }`, `match (x) { {type: user, name: n} => n }`},
+		{"match (x) { {name, age: a} => a }", "match (x) { {name: name, age: a} => a }"},
+		{"match (x) { 1..5 => a 6..<10 => b }", "match (x) { 1..5 => a, 6..<10 => b }"},
+		{"match (x) { -1 => a, -2.5..0 => b }", "match (x) { (-1) => a, (-2.5)..0 => b }"},
+		{"match (x) { n: int => n, _: string => 0 }", "match (x) { n: int => n, _: string => 0 }"},
+		{"match (x) { true => { let y = 1; y } }", "match (x) { true => let y = 1;y }"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestMatchPatternErrors(t *testing.T) {
+	tests := []string{
+		"match (x) { [...rest, a] => a }",
+		"match (x) { a + 1 => a }",
+		"match (x) { 0 a }",
+		"match (x) { -y => y }",
+	}
+
+	for _, input := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(input, log, false)
+		p := New(l, log, false)
+		p.ParseProgram()
+
+		if len(p.Errors) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestParsingDestructuringAndSpread(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let [a, b, ...rest] = arr;", "let [a, b, ...rest] = arr;"},
+		{"let {name, age} = person;", "let {name: name, age: age} = person;"},
+		{`let {name = "anon", address: {city}} = user;`, "let {name: name = anon, address: {city: city}} = user;"},
+		{"let [a, [b, c = 3]] = x;", "let [a, [b, c = 3]] = x;"},
+		{"[...a, ...b, 1]", "[...a, ...b, 1]"},
+		{"f(...args, x)", "f(...args, x)"},
+		{`{...defaults, "x": 1}`, "{...defaults, x:1}"},
+		{"[...a.b]", "[...a.b]"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
+
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		constant bool
+	}{
+		{"const x = 5;", "const x = 5;", true},
+		{"const [a, b] = pair;", "const [a, b] = pair;", true},
+		{"let y = 1;", "let y = 1;", false},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt, ok := program.Statements[0].(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("stmt not *ast.LetStatement. got=%T", program.Statements[0])
+		}
+		if stmt.Constant != tt.constant {
+			t.Errorf("stmt.Constant wrong for %q. expected=%t, got=%t", tt.input, tt.constant, stmt.Constant)
+		}
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+
+	log := logger.NewLogger()
+	l := lexer.New("const x;", log, false)
+	p := New(l, log, false)
+	p.ParseProgram()
+	if len(p.Errors) == 0 {
+		t.Errorf("expected an error for a constant without a value")
+	}
+}
+
+func TestTypeStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"type Point { x, y }", "type Point { x, y }"},
+		{"type Point { x: int, y: int = 0, }", "type Point { x: int, y: int = 0 }"},
+		{`type User { name: string, tags = [] };`, "type User { name: string, tags = [] }"},
+		{"type Empty {}", "type Empty {  }"},
+		{"p is Point == true", "((p is Point) == true)"},
+		{"!p is Point", "((!p) is Point)"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	for _, input := range []string{"type { x }", "type Point { x: }", "type Point { x y }"} {
+		log := logger.NewLogger()
+		l := lexer.New(input, log, false)
+		p := New(l, log, false)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestEnumStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"enum Color { Red, Green, Blue }", "enum Color { Red, Green, Blue }"},
+		{"enum Shape { Circle(r), Rect(w, h), }", "enum Shape { Circle(r), Rect(w, h) }"},
+		{"enum Status { Ok = 200, NotFound = 404 };", "enum Status { Ok = 200, NotFound = 404 }"},
+		{"enum Unit { Empty() }", "enum Unit { Empty() }"},
+		{"match (s) { Shape.Circle(r) => r, Shape.Rect(w, _) => w, Color.Red => 0 }", "match (s) { Shape.Circle(r) => r, Shape.Rect(w, _) => w, Color.Red => 0 }"},
+		{"match (s) { Shape.Rect(0, [a, b]) => a, Shape.Circle => 1 }", "match (s) { Shape.Rect(0, [a, b]) => a, Shape.Circle => 1 }"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	for _, input := range []string{"enum { Red }", "enum Color { Red Green }", "enum Shape { Circle(1) }", "match (s) { Shape. => 1 }"} {
+		log := logger.NewLogger()
+		l := lexer.New(input, log, false)
+		p := New(l, log, false)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let x: int = 5;", "let x: int = 5;"},
+		{"const name: string = \"ada\";", "const name: string = ada;"},
+		{"let f = fn(a: int, b) -> bool { a > b };", "let f = fn(a: int, b) -> bool (a > b);"},
+		{"let g = fn(callback: fn) -> fn { callback };", "let g = fn(callback: fn) -> fn callback;"},
+		{"let h = fn() -> null { };", "let h = fn() -> null ;"},
+		{"type Handler { run: fn }", "type Handler { run: fn }"},
+		{"match (x) { f: fn => 1, _ => 0 }", "match (x) { f: fn => 1, _ => 0 }"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	for _, input := range []string{"let x: = 5;", "let x: 5 = 5;", "fn(a:) { a }", "fn(a) -> { a }", "fn(a) -> 1 { a }"} {
+		log := logger.NewLogger()
+		l := lexer.New(input, log, false)
+		p := New(l, log, false)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestGenericTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let xs: array<int> = [1];", "let xs: array<int> = [1];"},
+		{"let h: hash<string, array<User>> = {};", "let h: hash<string, array<User>> = {};"},
+		{"let f = fn<T>(xs: array<T>) -> T { xs[0] };", "let f = fn<T>(xs: array<T>) -> T (xs[0]);"},
+		{"fn first<T>(xs: array<T>) -> T { xs[0] }", "let first = fn<T>(xs: array<T>) -> T (xs[0]);"},
+		{"fn pair<K, V>(k: K, v: V) { [k, v] };", "let pair = fn<K, V>(k: K, v: V) [k, v];"},
+		{"fn(x) { x }(1);", "fn(x) x(1)"},
+		{"type Tree { children: array<Tree> }", "type Tree { children: array<Tree> }"},
+		{"match (x) { xs: array<int> => 1, _ => 0 }", "match (x) { xs: array<int> => 1, _ => 0 }"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	for _, input := range []string{"let xs: array<int = [1];", "let xs: array<> = [];", "let f = fn<>(a) { a };", "let f = fn<T, 1>(a) { a };", "fn first<T>[xs] { xs }"} {
+		log := logger.NewLogger()
+		l := lexer.New(input, log, false)
+		p := New(l, log, false)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestInterfaceStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"interface Shape { area(); scale(by: float) -> Shape; }", "interface Shape { area(); scale(by: float) -> Shape; }"},
+		{"interface Named { name(), greet(other) }", "interface Named { name(); greet(other); }"},
+		{"interface Empty {}", "interface Empty { }"},
+		{"impl Shape for Circle { fn area(self) { self.r * self.r } }", "impl Shape for Circle { fn area(self) (self.r * self.r) }"},
+		{"impl Circle { fn area(self) -> int { 1 } fn scale(self, by: int) { self } }", "impl Circle { fn area(self) -> int 1 fn scale(self, by: int) self }"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	for _, input := range []string{"interface { area() }", "interface Shape { area }", "interface Shape { area() -> }", "impl { fn area(self) { 1 } }", "impl Shape for { }", "impl Circle { let x = 1; }", "impl Circle { fn (self) { 1 } }"} {
+		log := logger.NewLogger()
+		l := lexer.New(input, log, false)
+		p := New(l, log, false)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"fn* count(n) { yield n; }", "let count = fn*(n) yield n;"},
+		{"let g = fn*() { yield 1 + 2; };", "let g = fn*() yield (1 + 2);"},
+		{"fn* outer() { let f = fn*() { yield 1; }; yield f; }", "let outer = fn*() let f = fn*() yield 1;yield f;"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	for _, input := range []string{"yield 1;", "fn f() { yield 1; }", "fn* g() { let f = fn() { yield 1; }; }", "fn* g() { yield; }"} {
+		log := logger.NewLogger()
+		l := lexer.New(input, log, false)
+		p := New(l, log, false)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
+func TestConcurrency(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let t = spawn work(1, 2);", "let t = spawn work(1, 2);"},
+		{"spawn fn() { 1 }();", "spawn fn() 1()"},
+		{"select { v = recv(ch) => { v } send(out, 1) => { 2 } _ => { 3 } }", "select { v = recv(ch) => v, send(out, 1) => 2, _ => 3 }"},
+		{"let x = select { recv(ch) => 1, _ => 2 };", "let x = select { recv(ch) => 1, _ => 2 };"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+
+	for _, input := range []string{"spawn 1;", "spawn work;", "select { v = send(ch, 1) => 1 }", "select { recv(a, b) => 1 }", "select { print(1) => 1 }"} {
+		log := logger.NewLogger()
+		l := lexer.New(input, log, false)
+		p := New(l, log, false)
+		p.ParseProgram()
+		if len(p.Errors) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+		}
+	}
+}
+
 func testLetStatement(t *testing.T, s ast.Statement, name string) bool {
 	if s.TokenLiteral() != "let" {
 		t.Errorf("s.TokenLiteral not 'let'. got=%q", s.TokenLiteral())
@@ -1095,3 +1548,76 @@ func checkParserErrors(t *testing.T, p *Parser) {
 	}
 	t.FailNow()
 }
+
+func TestForInStatementParsing(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedKey      string
+		expectedValue    string
+		expectedIterable string
+	}{
+		{"for (x in arr) { x; }", "", "x", "arr"},
+		{"for (i, x in arr) { x; }", "i", "x", "arr"},
+		{"for (k, v in {\"a\": 1}) { v; }", "k", "v", "{a:1}"},
+		{"for (i in 0..10) { i; }", "", "i", "(0..10)"},
+		{"for (i in 0..<n step 2) { i; }", "", "i", "(0..<n step 2)"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d", len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ForInStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.ForInStatement. got=%T", program.Statements[0])
+		}
+
+		if tt.expectedKey == "" && stmt.Key != nil {
+			t.Errorf("stmt.Key should be nil. got=%q", stmt.Key.Value)
+		}
+		if tt.expectedKey != "" && !testIdentifier(t, stmt.Key, tt.expectedKey) {
+			return
+		}
+		if !testIdentifier(t, stmt.Value, tt.expectedValue) {
+			return
+		}
+		if stmt.Iterable.String() != tt.expectedIterable {
+			t.Errorf("stmt.Iterable wrong. expected=%q, got=%q", tt.expectedIterable, stmt.Iterable.String())
+		}
+		if len(stmt.Body.Statements) != 1 {
+			t.Errorf("stmt.Body.Statements does not contain 1 statement. got=%d", len(stmt.Body.Statements))
+		}
+	}
+}
+
+func TestRangeExpressionPrecedence(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"0..n - 1", "(0..(n - 1))"},
+		{"a + 1..<b * 2", "((a + 1)..<(b * 2))"},
+		{"0..10 step 1 + 1", "(0..10 step (1 + 1))"},
+		{"0..10 == r", "((0..10) == r)"},
+	}
+
+	for _, tt := range tests {
+		log := logger.NewLogger()
+		l := lexer.New(tt.input, log, false)
+		p := New(l, log, false)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		actual := program.String()
+		if actual != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, actual)
+		}
+	}
+}
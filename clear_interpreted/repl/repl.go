@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/ajtroup1/clear/checker"
 	"github.com/ajtroup1/clear/errors"
 	"github.com/ajtroup1/clear/evaluator"
 	"github.com/ajtroup1/clear/lexer"
@@ -40,6 +41,19 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
+		checkErrors := []*errors.Error{}
+		for _, err := range checker.New(l.Lines).Check(program) {
+			if err.IsWarning {
+				io.WriteString(out, "warning: "+err.Message+"\n")
+			} else {
+				checkErrors = append(checkErrors, err)
+			}
+		}
+		if len(checkErrors) != 0 {
+			printParserErrors(out, checkErrors, program.String())
+			continue
+		}
+
 		evaluated := evaluator.Eval(program, env)
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
@@ -25,6 +25,7 @@ const (
 	BANG     = "!"
 	ASTERISK = "*"
 	SLASH    = "/"
+	MODULO   = "%"
 	INC      = "++"
 	DEC      = "--"
 
@@ -39,6 +40,16 @@ const (
 	EQ     = "=="
 	NOT_EQ = "!="
 
+	IDENTICAL     = "==="
+	NOT_IDENTICAL = "!=="
+
+	RANGE      = ".."
+	RANGE_EXCL = "..<"
+	ELLIPSIS   = "..."
+
+	FAT_ARROW = "=>"
+	ARROW     = "->"
+
 	// Delimiters
 	COMMA     = ","
 	DOT       = "."
@@ -55,6 +66,7 @@ const (
 	// Keywords
 	FUNCTION = "FUNCTION"
 	LET      = "LET"
+	CONST    = "CONST"
 	TRUE     = "TRUE"
 	FALSE    = "FALSE"
 	IF       = "IF"
@@ -65,7 +77,16 @@ const (
 	FOR      = "FOR"
 	CONTINUE = "CONTINUE"
 	BREAK    = "BREAK"
+	IN       = "IN"
+	MATCH    = "MATCH"
+	IS       = "IS"
 	TYPE		 = "TYPE"
+	ENUM     = "ENUM"
+	INTERFACE = "INTERFACE"
+	IMPL      = "IMPL"
+	YIELD     = "YIELD"
+	SPAWN     = "SPAWN"
+	SELECT    = "SELECT"
 )
 
 type Token struct {
@@ -82,6 +103,7 @@ func (t *Token) String() string {
 var keywords = map[string]TokenType{
 	"fn":       FUNCTION,
 	"let":      LET,
+	"const":    CONST,
 	"true":     TRUE,
 	"false":    FALSE,
 	"if":       IF,
@@ -93,7 +115,22 @@ var keywords = map[string]TokenType{
 	"for":      FOR,
 	"continue": CONTINUE,
 	"break":    BREAK,
+	"in":       IN,
+	"match":    MATCH,
+	"is":       IS,
 	"type":     TYPE,
+	"enum":     ENUM,
+	"interface": INTERFACE,
+	"impl":      IMPL,
+	"yield":     YIELD,
+	"spawn":     SPAWN,
+	"select":    SELECT,
+}
+
+// Whether the literal is a reserved word rather than a plain identifier
+func IsKeyword(ident string) bool {
+	_, ok := keywords[ident]
+	return ok
 }
 
 func LookupIdent(ident string, logger *logger.Logger, enc int) TokenType {